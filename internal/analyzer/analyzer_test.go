@@ -140,3 +140,79 @@ require (
 		t.Error("go-zero version should be detected")
 	}
 }
+
+func TestParseConfigStruct(t *testing.T) {
+	// Force the built-in go-zero type definitions so the test doesn't depend on the module cache
+	t.Setenv("GOMODCACHE", t.TempDir())
+
+	tmpDir := t.TempDir()
+	goMod := "module github.com/example/user\n\ngo 1.21\n\nrequire github.com/zeromicro/go-zero v1.6.0\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configDir := filepath.Join(tmpDir, "internal", "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	configGo := `package config
+
+import (
+	"github.com/zeromicro/go-zero/core/stores/cache"
+	"github.com/zeromicro/go-zero/rest"
+)
+
+type Config struct {
+	rest.RestConf
+	Auth struct {
+		AccessSecret string
+		AccessExpire int64 ` + "`json:\",default=7200\"`" + `
+	}
+	CacheRedis cache.CacheConf
+	Storage    string ` + "`json:\"storage,default=local,options=local|s3\"`" + `
+}
+`
+	configFile := filepath.Join(configDir, "config.go")
+	if err := os.WriteFile(configFile, []byte(configGo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := analyzer.ParseConfigStruct(configFile, "")
+	if err != nil {
+		t.Fatalf("ParseConfigStruct() failed: %v", err)
+	}
+
+	fields := make(map[string]analyzer.ConfigStructField)
+	for _, field := range cfg.Fields {
+		fields[field.Key] = field
+	}
+
+	// Embedded rest.RestConf and service.ServiceConf are inlined
+	for _, key := range []string{"Name", "Log", "Mode", "Host", "Port", "Timeout", "Auth", "CacheRedis", "storage"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("expected key %q in parsed config struct", key)
+		}
+	}
+
+	if host := fields["Host"]; !host.HasDefault || host.Default != "0.0.0.0" {
+		t.Errorf("Host default = %q, want 0.0.0.0", host.Default)
+	}
+	if mode := fields["Mode"]; len(mode.Options) != 5 || mode.Options[4] != "pro" {
+		t.Errorf("Mode options = %v, want dev|test|rt|pre|pro", mode.Options)
+	}
+	if storage := fields["storage"]; storage.Default != "local" || len(storage.Options) != 2 {
+		t.Errorf("storage field = %+v, want default local with 2 options", storage)
+	}
+	if cache := fields["CacheRedis"]; cache.Kind != "list" || len(cache.Fields) == 0 {
+		t.Errorf("CacheRedis should be a list of redis nodes, got %+v", cache)
+	}
+	if log := fields["Log"]; log.Kind != "struct" || len(log.Fields) == 0 {
+		t.Errorf("Log should resolve to logx.LogConf fields, got %+v", log)
+	}
+	if middlewares := fields["Middlewares"]; middlewares.Kind != "struct" || len(middlewares.Fields) == 0 {
+		t.Errorf("Middlewares should resolve to rest.MiddlewaresConf fields, got %+v", middlewares)
+	}
+	if len(cfg.Fallbacks) == 0 || cfg.Fallbacks[0] != "github.com/zeromicro/go-zero/core/logx" {
+		t.Errorf("Expected built-in fallbacks to be reported, got %v", cfg.Fallbacks)
+	}
+}
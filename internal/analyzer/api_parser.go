@@ -5,6 +5,8 @@ import (
 	"os"
 	"regexp"
 	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 type APISpecification struct {
@@ -25,13 +27,13 @@ type Endpoint struct {
 func ParseAPISpecification(apiFile string) (*APISpecification, error) {
 	content, err := os.ReadFile(apiFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read API file: %w", err)
+		return nil, mcperrors.NewPathError(apiFile, "read", "failed to read API file", err)
 	}
 	spec := &APISpecification{FilePath: apiFile}
 	fileContent := string(content)
 	spec.ServiceName = extractServiceName(fileContent)
 	if spec.ServiceName == "" {
-		return nil, fmt.Errorf("no service name found")
+		return nil, mcperrors.Wrap(mcperrors.ErrValidationFailed, fmt.Errorf("no service name found"))
 	}
 	spec.Endpoints = extractEndpoints(fileContent)
 	spec.Types = extractTypes(fileContent)
@@ -0,0 +1,684 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+const goZeroModule = "github.com/zeromicro/go-zero"
+
+// ConfigStruct represents a service Config struct read from internal/config/config.go
+type ConfigStruct struct {
+	File   string
+	Name   string
+	Fields []ConfigStructField
+	// Fallbacks lists the go-zero packages resolved from the built-in trimmed copies
+	// because their sources were not found in vendor or the module cache
+	Fallbacks []string
+}
+
+// ConfigStructField represents one config key derived from a struct field
+type ConfigStructField struct {
+	Name       string // Go field name
+	Key        string // key used in the config file
+	Type       string // Go type expression
+	Kind       string // "scalar", "struct", "list" or "map"
+	Default    string
+	HasDefault bool
+	Options    []string
+	Optional   bool
+	Range      string
+	Env        string
+	Comment    string
+	Fields     []ConfigStructField // struct fields, or element fields for lists of structs
+}
+
+// HasField reports whether the struct contains a top-level key
+func (c *ConfigStruct) HasField(key string) bool {
+	for _, field := range c.Fields {
+		if strings.EqualFold(field.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// configStructParser resolves struct types across the project and its go-zero dependency
+type configStructParser struct {
+	fset          *token.FileSet
+	projectRoot   string
+	moduleName    string
+	goZeroVersion string
+	packages      map[string]map[string]*typeDecl // import path -> type name -> declaration
+	resolving     map[string]bool                 // guards against recursive types
+	fallbacks     []string                        // packages loaded from builtinGoZeroTypes
+}
+
+type typeDecl struct {
+	expr    ast.Expr
+	imports map[string]string // file-level import alias -> import path
+	pkgPath string
+}
+
+// ParseConfigStruct reads a Config struct with go/ast and follows embedded go-zero types
+// (rest.RestConf, zrpc.RpcServerConf, cache.CacheConf, ...) to produce every config key
+func ParseConfigStruct(configFile string, structName string) (*ConfigStruct, error) {
+	if structName == "" {
+		structName = "Config"
+	}
+
+	absFile, err := filepath.Abs(configFile)
+	if err != nil {
+		return nil, mcperrors.NewPathError(configFile, "resolve", "failed to resolve config file", err)
+	}
+	if _, err := os.Stat(absFile); err != nil {
+		return nil, mcperrors.NewPathError(absFile, "stat", "config struct file not found", err)
+	}
+
+	p := &configStructParser{
+		fset:      token.NewFileSet(),
+		packages:  make(map[string]map[string]*typeDecl),
+		resolving: make(map[string]bool),
+	}
+
+	if root := findModuleRoot(filepath.Dir(absFile)); root != "" {
+		p.projectRoot = root
+		p.moduleName = readModuleName(filepath.Join(root, "go.mod"))
+		if _, version, err := parseDependencies(root); err == nil {
+			p.goZeroVersion = version
+		}
+	}
+
+	// The Config struct's own package is keyed by its directory
+	localPkg := "file://" + filepath.Dir(absFile)
+	decls, err := p.parseDir(filepath.Dir(absFile), localPkg)
+	if err != nil {
+		return nil, err
+	}
+	p.packages[localPkg] = decls
+
+	decl, ok := decls[structName]
+	if !ok {
+		return nil, mcperrors.Wrap(mcperrors.ErrProjectStructure, fmt.Errorf("struct %s not found in %s", structName, filepath.Dir(absFile)))
+	}
+
+	structType, ok := decl.expr.(*ast.StructType)
+	if !ok {
+		return nil, mcperrors.Wrap(mcperrors.ErrProjectStructure, fmt.Errorf("%s is not a struct type", structName))
+	}
+
+	fields := p.structFields(structType, decl)
+	sort.Strings(p.fallbacks)
+
+	return &ConfigStruct{
+		File:      absFile,
+		Name:      structName,
+		Fields:    fields,
+		Fallbacks: p.fallbacks,
+	}, nil
+}
+
+// structFields converts struct fields to config fields, inlining embedded structs
+func (p *configStructParser) structFields(st *ast.StructType, decl *typeDecl) []ConfigStructField {
+	var fields []ConfigStructField
+
+	for _, f := range st.Fields.List {
+		tag := ""
+		if f.Tag != nil {
+			if unquoted, err := strconv.Unquote(f.Tag.Value); err == nil {
+				tag = reflect.StructTag(unquoted).Get("json")
+			}
+		}
+		if tag == "-" {
+			continue
+		}
+		tagName, opts := parseConfigTag(tag)
+
+		// Embedded field: go-zero inlines its keys unless the tag names it
+		if len(f.Names) == 0 {
+			field := p.describeType(f.Type, decl)
+			if field.Kind == "struct" && tagName == "" {
+				fields = append(fields, field.Fields...)
+				continue
+			}
+			field.Name = typeBaseName(f.Type)
+			field.Key = field.Name
+			if tagName != "" {
+				field.Key = tagName
+			}
+			applyTagOptions(&field, opts)
+			fields = append(fields, field)
+			continue
+		}
+
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			field := p.describeType(f.Type, decl)
+			field.Name = name.Name
+			field.Key = name.Name
+			if tagName != "" {
+				field.Key = tagName
+			}
+			field.Comment = fieldComment(f)
+			applyTagOptions(&field, opts)
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// describeType returns the kind and nested fields of a type expression
+func (p *configStructParser) describeType(expr ast.Expr, decl *typeDecl) ConfigStructField {
+	field := ConfigStructField{Type: exprString(expr), Kind: "scalar"}
+
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		inner := p.describeType(t.X, decl)
+		inner.Type = field.Type
+		return inner
+	case *ast.StructType:
+		field.Kind = "struct"
+		field.Fields = p.structFields(t, decl)
+	case *ast.ArrayType:
+		field.Kind = "list"
+		elem := p.describeType(t.Elt, decl)
+		if elem.Kind == "struct" {
+			field.Fields = elem.Fields
+		}
+	case *ast.MapType:
+		field.Kind = "map"
+	case *ast.Ident:
+		if isBuiltinType(t.Name) {
+			return field
+		}
+		if resolved, ok := p.resolveNamed(decl.pkgPath, t.Name); ok {
+			resolved.Type = field.Type
+			return resolved
+		}
+	case *ast.SelectorExpr:
+		pkgIdent, ok := t.X.(*ast.Ident)
+		if !ok {
+			return field
+		}
+		importPath, ok := decl.imports[pkgIdent.Name]
+		if !ok {
+			return field
+		}
+		if resolved, ok := p.resolveNamed(importPath, t.Sel.Name); ok {
+			resolved.Type = field.Type
+			return resolved
+		}
+	}
+
+	return field
+}
+
+// resolveNamed looks up a named type in a package and describes its underlying type
+func (p *configStructParser) resolveNamed(pkgPath, name string) (ConfigStructField, bool) {
+	key := pkgPath + "." + name
+	if p.resolving[key] {
+		return ConfigStructField{}, false
+	}
+
+	decls := p.loadPackage(pkgPath)
+	decl, ok := decls[name]
+	if !ok {
+		return ConfigStructField{}, false
+	}
+
+	p.resolving[key] = true
+	defer delete(p.resolving, key)
+
+	field := p.describeType(decl.expr, decl)
+	return field, true
+}
+
+// loadPackage parses the type declarations of an import path, caching the result
+func (p *configStructParser) loadPackage(pkgPath string) map[string]*typeDecl {
+	if decls, ok := p.packages[pkgPath]; ok {
+		return decls
+	}
+
+	var decls map[string]*typeDecl
+	if dir := p.packageDir(pkgPath); dir != "" {
+		decls, _ = p.parseDir(dir, pkgPath)
+	}
+	if len(decls) == 0 {
+		if src, ok := builtinGoZeroTypes[pkgPath]; ok {
+			decls = p.parseSource(pkgPath, src)
+			p.fallbacks = append(p.fallbacks, pkgPath)
+		}
+	}
+	if decls == nil {
+		decls = make(map[string]*typeDecl)
+	}
+
+	p.packages[pkgPath] = decls
+	return decls
+}
+
+// packageDir maps an import path to a directory in the project, vendor or module cache
+func (p *configStructParser) packageDir(pkgPath string) string {
+	if p.projectRoot == "" {
+		return ""
+	}
+
+	if p.moduleName != "" && (pkgPath == p.moduleName || strings.HasPrefix(pkgPath, p.moduleName+"/")) {
+		return filepath.Join(p.projectRoot, strings.TrimPrefix(pkgPath, p.moduleName))
+	}
+
+	vendorDir := filepath.Join(p.projectRoot, "vendor", filepath.FromSlash(pkgPath))
+	if isDir(vendorDir) {
+		return vendorDir
+	}
+
+	if strings.HasPrefix(pkgPath, goZeroModule+"/") && p.goZeroVersion != "" {
+		modDir := filepath.Join(moduleCacheDir(), escapeModulePath(goZeroModule)+"@v"+p.goZeroVersion)
+		dir := filepath.Join(modDir, filepath.FromSlash(strings.TrimPrefix(pkgPath, goZeroModule+"/")))
+		if isDir(dir) {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+// parseDir collects top-level type declarations from the non-test Go files in a directory
+func (p *configStructParser) parseDir(dir, pkgPath string) (map[string]*typeDecl, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, mcperrors.NewPathError(dir, "read", "failed to read package directory", err)
+	}
+
+	decls := make(map[string]*typeDecl)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(p.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		collectTypeDecls(file, pkgPath, decls)
+	}
+
+	return decls, nil
+}
+
+func (p *configStructParser) parseSource(pkgPath, src string) map[string]*typeDecl {
+	file, err := parser.ParseFile(p.fset, pkgPath+".go", src, parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	decls := make(map[string]*typeDecl)
+	collectTypeDecls(file, pkgPath, decls)
+	return decls
+}
+
+func collectTypeDecls(file *ast.File, pkgPath string, decls map[string]*typeDecl) {
+	imports := make(map[string]string)
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		alias := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		imports[alias] = path
+	}
+
+	for _, d := range file.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			decls[ts.Name.Name] = &typeDecl{
+				expr:    ts.Type,
+				imports: imports,
+				pkgPath: pkgPath,
+			}
+		}
+	}
+}
+
+// parseConfigTag splits a go-zero json tag into its name and options,
+// keeping bracketed values such as options=[a,b] or range=[0:1000) intact
+func parseConfigTag(tag string) (string, []string) {
+	if tag == "" {
+		return "", nil
+	}
+
+	var parts []string
+	depth := 0
+	start := 0
+	for i, ch := range tag {
+		switch ch {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, tag[start:])
+
+	return parts[0], parts[1:]
+}
+
+func applyTagOptions(field *ConfigStructField, opts []string) {
+	for _, opt := range opts {
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "optional":
+			field.Optional = true
+		case "default":
+			field.Default = value
+			field.HasDefault = true
+		case "options":
+			value = strings.Trim(value, "[]")
+			sep := "|"
+			if strings.Contains(value, ",") {
+				sep = ","
+			}
+			for _, option := range strings.Split(value, sep) {
+				if option = strings.TrimSpace(option); option != "" {
+					field.Options = append(field.Options, option)
+				}
+			}
+		case "range":
+			field.Range = value
+		case "env":
+			field.Env = value
+		}
+	}
+}
+
+func fieldComment(f *ast.Field) string {
+	var text string
+	if f.Doc != nil {
+		text = f.Doc.Text()
+	} else if f.Comment != nil {
+		text = f.Comment.Text()
+	}
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\n", " "))
+	// Keep the first sentence; go-zero doc comments can run for several lines
+	if idx := strings.Index(text, ". "); idx >= 0 {
+		text = text[:idx+1]
+	}
+	return text
+}
+
+func typeBaseName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return typeBaseName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return exprString(expr)
+}
+
+func exprString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return exprString(t.X) + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(t.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(t.Elt)
+	case *ast.MapType:
+		return "map[" + exprString(t.Key) + "]" + exprString(t.Value)
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+	return "unknown"
+}
+
+func isBuiltinType(name string) bool {
+	switch name {
+	case "string", "bool", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64",
+		"byte", "rune", "any":
+		return true
+	}
+	return false
+}
+
+// findModuleRoot walks up from dir to the nearest directory containing go.mod
+func findModuleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func readModuleName(goModPath string) string {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+	return ""
+}
+
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if goPath := os.Getenv("GOPATH"); goPath != "" {
+		return filepath.Join(filepath.SplitList(goPath)[0], "pkg", "mod")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "go", "pkg", "mod")
+}
+
+// escapeModulePath applies the module cache case encoding (Upper -> !lower)
+func escapeModulePath(path string) string {
+	var sb strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			sb.WriteRune('!')
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// builtinGoZeroTypes are trimmed copies of common go-zero config types,
+// used when the go-zero sources are not available in vendor or the module cache
+var builtinGoZeroTypes = map[string]string{
+	goZeroModule + "/rest": `package rest
+
+import (
+	"time"
+
+	"github.com/zeromicro/go-zero/core/service"
+)
+
+type RestConf struct {
+	service.ServiceConf
+	Host     string ` + "`json:\",default=0.0.0.0\"`" + `
+	Port     int
+	CertFile string ` + "`json:\",optional\"`" + `
+	KeyFile  string ` + "`json:\",optional\"`" + `
+	Verbose  bool   ` + "`json:\",optional\"`" + `
+	MaxConns int    ` + "`json:\",default=10000\"`" + `
+	MaxBytes int64  ` + "`json:\",default=1048576\"`" + `
+	// milliseconds
+	Timeout      int64         ` + "`json:\",default=3000\"`" + `
+	CpuThreshold int64         ` + "`json:\",default=900,range=[0:1000)\"`" + `
+	Signature    SignatureConf ` + "`json:\",optional\"`" + `
+	// There are default values for all the items in Middlewares.
+	Middlewares MiddlewaresConf
+	// TraceIgnorePaths is paths blacklist for trace middleware.
+	TraceIgnorePaths []string ` + "`json:\",optional\"`" + `
+}
+
+type SignatureConf struct {
+	Strict      bool          ` + "`json:\",default=false\"`" + `
+	Expiry      time.Duration ` + "`json:\",default=1h\"`" + `
+	PrivateKeys []PrivateKeyConf
+}
+
+type PrivateKeyConf struct {
+	Fingerprint string
+	KeyFile     string
+}
+
+type MiddlewaresConf struct {
+	Trace      bool ` + "`json:\",default=true\"`" + `
+	Log        bool ` + "`json:\",default=true\"`" + `
+	Prometheus bool ` + "`json:\",default=true\"`" + `
+	MaxConns   bool ` + "`json:\",default=true\"`" + `
+	Breaker    bool ` + "`json:\",default=true\"`" + `
+	Shedding   bool ` + "`json:\",default=true\"`" + `
+	Timeout    bool ` + "`json:\",default=true\"`" + `
+	Recover    bool ` + "`json:\",default=true\"`" + `
+	Metrics    bool ` + "`json:\",default=true\"`" + `
+	MaxBytes   bool ` + "`json:\",default=true\"`" + `
+	Gunzip     bool ` + "`json:\",default=true\"`" + `
+}
+`,
+	goZeroModule + "/core/service": `package service
+
+import "github.com/zeromicro/go-zero/core/logx"
+
+type ServiceConf struct {
+	Name       string
+	Log        logx.LogConf
+	Mode       string ` + "`json:\",default=pro,options=dev|test|rt|pre|pro\"`" + `
+	MetricsUrl string ` + "`json:\",optional\"`" + `
+}
+`,
+	goZeroModule + "/core/logx": `package logx
+
+type LogConf struct {
+	ServiceName         string ` + "`json:\",optional\"`" + `
+	Mode                string ` + "`json:\",default=console,options=[console,file,volume]\"`" + `
+	Encoding            string ` + "`json:\",default=json,options=[json,plain]\"`" + `
+	TimeFormat          string ` + "`json:\",optional\"`" + `
+	Path                string ` + "`json:\",default=logs\"`" + `
+	Level               string ` + "`json:\",default=info,options=[debug,info,error,severe]\"`" + `
+	Compress            bool   ` + "`json:\",optional\"`" + `
+	Stat                bool   ` + "`json:\",default=true\"`" + `
+	KeepDays            int    ` + "`json:\",optional\"`" + `
+	StackCooldownMillis int    ` + "`json:\",default=100\"`" + `
+	Rotation            string ` + "`json:\",default=daily,options=[daily,size]\"`" + `
+}
+`,
+	goZeroModule + "/zrpc": `package zrpc
+
+import (
+	"github.com/zeromicro/go-zero/core/discov"
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+type RpcServerConf struct {
+	service.ServiceConf
+	ListenOn      string
+	Etcd          discov.EtcdConf    ` + "`json:\",optional,inherit\"`" + `
+	Auth          bool               ` + "`json:\",optional\"`" + `
+	Redis         redis.RedisKeyConf ` + "`json:\",optional\"`" + `
+	StrictControl bool               ` + "`json:\",optional\"`" + `
+	// setting 0 means no timeout
+	Timeout      int64 ` + "`json:\",default=2000\"`" + `
+	CpuThreshold int64 ` + "`json:\",default=900,range=[0:1000)\"`" + `
+	Health       bool  ` + "`json:\",default=true\"`" + `
+}
+
+type RpcClientConf struct {
+	Etcd      discov.EtcdConf ` + "`json:\",optional,inherit\"`" + `
+	Endpoints []string        ` + "`json:\",optional\"`" + `
+	Target    string          ` + "`json:\",optional\"`" + `
+	App       string          ` + "`json:\",optional\"`" + `
+	Token     string          ` + "`json:\",optional\"`" + `
+	NonBlock  bool            ` + "`json:\",default=true\"`" + `
+	Timeout   int64           ` + "`json:\",default=2000\"`" + `
+}
+`,
+	goZeroModule + "/core/discov": `package discov
+
+type EtcdConf struct {
+	Hosts []string
+	Key   string
+	User  string ` + "`json:\",optional\"`" + `
+	Pass  string ` + "`json:\",optional\"`" + `
+}
+`,
+	goZeroModule + "/core/stores/redis": `package redis
+
+type RedisConf struct {
+	Host     string
+	Type     string ` + "`json:\",default=node,options=node|cluster\"`" + `
+	Pass     string ` + "`json:\",optional\"`" + `
+	Tls      bool   ` + "`json:\",optional\"`" + `
+	NonBlock bool   ` + "`json:\",default=true\"`" + `
+}
+
+type RedisKeyConf struct {
+	RedisConf
+	Key string
+}
+`,
+	goZeroModule + "/core/stores/cache": `package cache
+
+import "github.com/zeromicro/go-zero/core/stores/redis"
+
+type CacheConf = ClusterConf
+
+type ClusterConf []NodeConf
+
+type NodeConf struct {
+	redis.RedisConf
+	Weight int ` + "`json:\",default=100\"`" + `
+}
+`,
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 type DatabaseModel struct {
@@ -27,7 +29,7 @@ func ParseTableSchema(ddl string) (*DatabaseModel, error) {
 	if len(tableMatch) > 1 {
 		model.TableName = tableMatch[1]
 	} else {
-		return nil, fmt.Errorf("no table name found in DDL")
+		return nil, mcperrors.Wrap(mcperrors.ErrValidationFailed, fmt.Errorf("no table name found in DDL"))
 	}
 
 	fieldRegex := regexp.MustCompile("`" + `(\w+)` + "`" + `\s+(\w+(?:\(\d+\))?)\s*([^,\n]*)?`)
@@ -1,11 +1,12 @@
 package analyzer
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 // ProjectAnalysis represents a comprehensive analysis of a go-zero project
@@ -73,16 +74,16 @@ func ScanProject(projectPath string) (*ProjectAnalysis, error) {
 		var err error
 		projectPath, err = filepath.Abs(projectPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve project path: %w", err)
+			return nil, mcperrors.NewPathError(projectPath, "resolve", "failed to resolve project path", err)
 		}
 	}
 
 	info, err := os.Stat(projectPath)
 	if err != nil {
-		return nil, fmt.Errorf("project path does not exist: %w", err)
+		return nil, mcperrors.NewPathError(projectPath, "stat", "project path does not exist", err)
 	}
 	if !info.IsDir() {
-		return nil, fmt.Errorf("project path is not a directory")
+		return nil, mcperrors.NewPathError(projectPath, "validate", "project path is not a directory", mcperrors.ErrInvalidPath)
 	}
 
 	analysis := &ProjectAnalysis{
@@ -257,9 +258,10 @@ func discoverConfigFiles(projectPath string) ([]ConfigFile, error) {
 			return nil
 		}
 
-		// Only include common config file names
+		// Only include common config file names, plus anything under go-zero's etc/ directories
 		name := strings.ToLower(filepath.Base(path))
-		if strings.Contains(name, "config") || strings.Contains(name, "settings") ||
+		inEtcDir := filepath.Base(filepath.Dir(path)) == "etc"
+		if inEtcDir || strings.Contains(name, "config") || strings.Contains(name, "settings") ||
 			name == "etc.yaml" || name == "etc.json" {
 			configs = append(configs, ConfigFile{
 				Path: path,
@@ -273,13 +275,25 @@ func discoverConfigFiles(projectPath string) ([]ConfigFile, error) {
 	return configs, err
 }
 
+// FindGoZeroVersion returns the go-zero version required by the go.mod in
+// dir or its closest parent, and the directory holding that go.mod.
+// An empty version means no go.mod was found or it doesn't require go-zero.
+func FindGoZeroVersion(dir string) (string, string, error) {
+	root := findModuleRoot(dir)
+	if root == "" {
+		return "", "", nil
+	}
+	_, version, err := parseDependencies(root)
+	return version, root, err
+}
+
 // parseDependencies extracts dependencies from go.mod
 func parseDependencies(projectPath string) ([]Dependency, string, error) {
 	goModPath := filepath.Join(projectPath, "go.mod")
 
 	content, err := os.ReadFile(goModPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read go.mod: %w", err)
+		return nil, "", mcperrors.NewPathError(goModPath, "read", "failed to read go.mod", err)
 	}
 
 	var deps []Dependency
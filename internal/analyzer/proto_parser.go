@@ -5,6 +5,8 @@ import (
 	"os"
 	"regexp"
 	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 type RPCService struct {
@@ -24,7 +26,7 @@ type RPCMethod struct {
 func ParseProtoSpecification(protoFile string) (*RPCService, error) {
 	content, err := os.ReadFile(protoFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read proto file: %w", err)
+		return nil, mcperrors.NewPathError(protoFile, "read", "failed to read proto file", err)
 	}
 
 	service := &RPCService{
@@ -35,7 +37,7 @@ func ParseProtoSpecification(protoFile string) (*RPCService, error) {
 
 	service.ServiceName = extractRPCServiceName(fileContent)
 	if service.ServiceName == "" {
-		return nil, fmt.Errorf("no service name found")
+		return nil, mcperrors.Wrap(mcperrors.ErrValidationFailed, fmt.Errorf("no service name found"))
 	}
 
 	service.Methods = extractRPCMethods(fileContent)
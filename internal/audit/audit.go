@@ -0,0 +1,207 @@
+// Package audit records every file a tool call creates, modifies or deletes
+// and every subprocess it runs to an append-only JSONL file
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// Event types
+const (
+	TypeFile    = "file"
+	TypeCommand = "command"
+)
+
+// File actions
+const (
+	ActionCreated  = snapshot.ActionCreated
+	ActionModified = snapshot.ActionModified
+	ActionDeleted  = snapshot.ActionDeleted
+)
+
+// maxLineSize bounds a single JSONL entry when reading the log back
+const maxLineSize = 1 << 20
+
+// Event is one line of the audit log
+type Event struct {
+	Time      time.Time      `json:"time"`
+	Type      string         `json:"type"` // "file" or "command"
+	Tool      string         `json:"tool"`
+	Session   string         `json:"session,omitempty"`
+	Arguments map[string]any `json:"arguments,omitempty"` // tool arguments, secrets redacted
+
+	// File events
+	Action    string `json:"action,omitempty"` // created, modified or deleted
+	Path      string `json:"path,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"` // the tracked tree exceeded snapshot.MaxFiles, changes may be missing
+
+	// Command events
+	Step       string   `json:"step,omitempty"`
+	Command    string   `json:"command,omitempty"`
+	Args       []string `json:"args,omitempty"` // secrets redacted
+	Dir        string   `json:"dir,omitempty"`
+	ExitCode   *int     `json:"exit_code,omitempty"`
+	DurationMs int64    `json:"duration_ms,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// Log appends events to a JSONL file. A nil *Log records nothing
+type Log struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Open opens the audit file for appending, creating it and its directory
+// readable by the current user only
+func Open(path string) (*Log, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve audit log path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(absPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(absPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{path: absPath, file: file}, nil
+}
+
+// Path returns the audit file
+func (l *Log) Path() string {
+	if l == nil {
+		return ""
+	}
+	return l.path
+}
+
+// Write appends an event as a single line
+func (l *Log) Write(event Event) error {
+	if l == nil {
+		return nil
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+// Close closes the audit file
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Filter selects events from the log; zero fields match everything
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	Tool  string
+	Type  string
+	Path  string // matches file events at or below the path and commands run there
+	Limit int    // keep only the most recent events
+}
+
+// Match reports whether event passes the filter
+func (f Filter) Match(event Event) bool {
+	if !f.Since.IsZero() && event.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Time.After(f.Until) {
+		return false
+	}
+	if f.Tool != "" && event.Tool != f.Tool {
+		return false
+	}
+	if f.Type != "" && event.Type != f.Type {
+		return false
+	}
+	if f.Path != "" {
+		path := event.Path
+		if event.Type == TypeCommand {
+			path = event.Dir
+		}
+		if path == "" || !snapshot.Within(filepath.Clean(f.Path), path) {
+			return false
+		}
+	}
+	return true
+}
+
+// Query reads the events matching the filter in the order they were written,
+// along with the number of matches before Limit was applied. Lines that
+// can't be decoded, e.g. one cut short by a crash, are skipped
+func (l *Log) Query(filter Filter) ([]Event, int, error) {
+	if l == nil {
+		return nil, 0, nil
+	}
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	matched := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if !filter.Match(event) {
+			continue
+		}
+		matched++
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) > filter.Limit {
+			events = events[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return events, matched, nil
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context carrying the recorder of the current tool call
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext returns the recorder of the current tool call, or nil
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
@@ -0,0 +1,176 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+func openLog(t *testing.T) *audit.Log {
+	t.Helper()
+	log, err := audit.Open(filepath.Join(t.TempDir(), "logs", "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+	return log
+}
+
+func TestRecorderFileChanges(t *testing.T) {
+	log := openLog(t)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "keep.go"), []byte("package a\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "change.go"), []byte("package a\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "remove.go"), []byte("package a\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+
+	recorder := log.Begin("generate_api_from_spec", "session-1", map[string]any{"output_dir": dir, "password": "hunter2"})
+	tracker := snapshot.NewTracker(snapshot.DefaultContentBudget)
+	tracker.Track(dir)
+	tracker.Track(filepath.Join(dir, "sub")) // already covered by dir
+
+	os.WriteFile(filepath.Join(dir, "change.go"), []byte("package a\n\nvar x = 1\n"), 0644)
+	os.Remove(filepath.Join(dir, "remove.go"))
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "sub", "new.go"), []byte("package sub\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".git", "index"), []byte("x"), 0644)
+	recorder.Finish(tracker.Changes(), nil)
+
+	events, matched, err := log.Query(audit.Filter{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	want := map[string]string{
+		filepath.Join(dir, "change.go"):     audit.ActionModified,
+		filepath.Join(dir, "remove.go"):     audit.ActionDeleted,
+		filepath.Join(dir, "sub", "new.go"): audit.ActionCreated,
+	}
+	if matched != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), matched, events)
+	}
+	for _, event := range events {
+		if want[event.Path] != event.Action {
+			t.Errorf("unexpected event %s %s", event.Action, event.Path)
+		}
+		if event.Type != audit.TypeFile || event.Tool != "generate_api_from_spec" || event.Session != "session-1" {
+			t.Errorf("event not attributed to the tool call: %+v", event)
+		}
+		if event.Arguments["password"] == "hunter2" || event.Arguments["output_dir"] != dir {
+			t.Errorf("unexpected arguments: %v", event.Arguments)
+		}
+	}
+}
+
+func TestRecorderCommand(t *testing.T) {
+	log := openLog(t)
+	recorder := log.Begin("generate_model", "", nil)
+	ctx := audit.WithRecorder(context.Background(), recorder)
+
+	audit.FromContext(ctx).Command("goctl", "/usr/local/bin/goctl",
+		[]string{"model", "mysql", "datasource", "--url", "root:s3cret@tcp(127.0.0.1:3306)/shop", "--pwd", "abc", "--token=xyz"},
+		"/work", 1, 1500*time.Millisecond, errors.New("exit status 1\nmore output"))
+
+	events, _, err := log.Query(audit.Filter{Type: audit.TypeCommand})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected one command event, got %v %v", events, err)
+	}
+	event := events[0]
+	args := strings.Join(event.Args, " ")
+	if strings.Contains(args, "s3cret") || strings.Contains(args, " abc") || strings.Contains(args, "xyz") {
+		t.Errorf("secrets not redacted: %s", args)
+	}
+	if event.ExitCode == nil || *event.ExitCode != 1 || event.DurationMs != 1500 || event.Error != "exit status 1" {
+		t.Errorf("unexpected command event: %+v", event)
+	}
+
+	// Without a recorder nothing is recorded and nothing panics
+	audit.FromContext(context.Background()).Command("goctl", "goctl", nil, "", 0, 0, nil)
+	var nilLog *audit.Log
+	nilLog.Begin("x", "", nil).Finish(nil, nil)
+}
+
+func TestRecorderRedactsEnvAndOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer log.Close()
+
+	recorder := log.Begin("validate_config", "", map[string]any{
+		"config_path": "etc/user-api.yaml",
+		"env": map[string]string{
+			"MYSQL_PASSWORD":     "mysqlHunter2",
+			"AUTH_ACCESS_SECRET": "jwtHunter2",
+			"PORT":               "8888",
+		},
+		"overrides":    `{"Redis": {"Pass": "redisHunter2"}, "Mysql": {"DataSource": "root:dsnHunter2@tcp(db:3306)/user"}}`,
+		"access_token": "tokenHunter2",
+	})
+	recorder.Finish(nil, []string{"/work/user"})
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	for _, secret := range []string{"mysqlHunter2", "jwtHunter2", "8888", "redisHunter2", "dsnHunter2", "tokenHunter2"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("audit log contains %q:\n%s", secret, content)
+		}
+	}
+	if !strings.Contains(string(content), "etc/user-api.yaml") || !strings.Contains(string(content), "Redis") {
+		t.Errorf("non-secret arguments should be kept:\n%s", content)
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	log := openLog(t)
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i, event := range []audit.Event{
+		{Type: audit.TypeFile, Tool: "create_api_service", Action: audit.ActionCreated, Path: "/work/user/user.go"},
+		{Type: audit.TypeCommand, Tool: "create_api_service", Command: "go", Dir: "/work/user"},
+		{Type: audit.TypeFile, Tool: "generate_model", Action: audit.ActionCreated, Path: "/work/model/user.go"},
+		{Type: audit.TypeFile, Tool: "generate_model", Action: audit.ActionCreated, Path: "/work/users/x.go"},
+	} {
+		event.Time = base.Add(time.Duration(i) * time.Hour)
+		if err := log.Write(event); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter audit.Filter
+		want   int
+	}{
+		{"all", audit.Filter{}, 4},
+		{"tool", audit.Filter{Tool: "generate_model"}, 2},
+		{"path includes commands run there", audit.Filter{Path: "/work/user"}, 2},
+		{"since", audit.Filter{Since: base.Add(90 * time.Minute)}, 2},
+		{"until", audit.Filter{Until: base.Add(time.Hour)}, 2},
+		{"type", audit.Filter{Type: audit.TypeCommand}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, matched, err := log.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if matched != tt.want {
+				t.Errorf("expected %d matches, got %d", tt.want, matched)
+			}
+		})
+	}
+
+	events, matched, _ := log.Query(audit.Filter{Limit: 1})
+	if matched != 4 || len(events) != 1 || events[0].Path != "/work/users/x.go" {
+		t.Errorf("limit should keep the most recent event, got %d %+v", matched, events)
+	}
+}
@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/security"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// secretFlags are command flags whose next argument is a credential
+var secretFlags = map[string]bool{
+	"-pwd":       true,
+	"--pwd":      true,
+	"-password":  true,
+	"--password": true,
+	"-token":     true,
+	"--token":    true,
+}
+
+// secretNameParts mark argument and environment variable names holding a
+// credential, such as MYSQL_PASSWORD, AUTH_ACCESS_SECRET or REDIS_PASS.
+// Names are compared lowercased with '_' and '-' removed
+var secretNameParts = []string{"pass", "secret", "token", "apikey", "accesskey", "privatekey", "credential"}
+
+// envArguments map environment variable names to values; every value is
+// redacted since the names don't reliably tell which ones are credentials
+var envArguments = map[string]bool{
+	"env": true,
+}
+
+// jsonArguments hold a JSON object of config values as a string
+var jsonArguments = map[string]bool{
+	"overrides": true,
+}
+
+// Recorder collects the audit events of one tool call. A nil *Recorder
+// records nothing
+type Recorder struct {
+	log       *Log
+	tool      string
+	session   string
+	arguments map[string]any
+}
+
+// Begin starts recording a tool call; input is the tool's arguments
+func (l *Log) Begin(tool, session string, input any) *Recorder {
+	if l == nil {
+		return nil
+	}
+	return &Recorder{
+		log:       l,
+		tool:      tool,
+		session:   session,
+		arguments: RedactArguments(input),
+	}
+}
+
+// Command records a finished subprocess
+func (r *Recorder) Command(step, name string, args []string, dir string, exitCode int, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	event := r.event(TypeCommand)
+	event.Step = step
+	event.Command = name
+	event.Args = RedactArgs(args)
+	event.Dir = dir
+	event.ExitCode = &exitCode
+	event.DurationMs = duration.Milliseconds()
+	if err != nil {
+		event.Error = security.RedactDSN(firstLine(err.Error()))
+	}
+	r.log.Write(event)
+}
+
+// Finish records the files the call created, modified or deleted, including
+// those written by goctl or go, as detected by the call's snapshot.Tracker.
+// Truncated lists tracked paths too large to compare fully; each gets a
+// file event without an action so the gap shows in the log
+func (r *Recorder) Finish(changes []snapshot.Change, truncated []string) {
+	if r == nil {
+		return
+	}
+	for _, change := range changes {
+		if change.Dir {
+			continue
+		}
+		event := r.event(TypeFile)
+		event.Action = change.Action
+		event.Path = change.Path
+		event.Truncated = change.Truncated
+		r.log.Write(event)
+	}
+	for _, path := range truncated {
+		event := r.event(TypeFile)
+		event.Path = path
+		event.Truncated = true
+		r.log.Write(event)
+	}
+}
+
+func (r *Recorder) event(eventType string) Event {
+	return Event{
+		Time:      time.Now(),
+		Type:      eventType,
+		Tool:      r.tool,
+		Session:   r.session,
+		Arguments: r.arguments,
+	}
+}
+
+// RedactArguments converts tool input to a map with credentials masked:
+// values of secret-named fields, DSN passwords, private keys, environment
+// variable values and secrets inside JSON-encoded config overrides
+func RedactArguments(input any) map[string]any {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil
+	}
+	var arguments map[string]any
+	if err := json.Unmarshal(data, &arguments); err != nil || len(arguments) == 0 {
+		return nil
+	}
+	for key, value := range arguments {
+		switch {
+		case envArguments[key]:
+			arguments[key] = redactEnv(value)
+		case jsonArguments[key]:
+			arguments[key] = redactJSON(key, value)
+		default:
+			arguments[key] = redactValue(key, value)
+		}
+	}
+	return arguments
+}
+
+// redactEnv masks every value of an environment variable map
+func redactEnv(value any) any {
+	env, ok := value.(map[string]any)
+	if !ok {
+		return redactValue("", value)
+	}
+	for name, inner := range env {
+		if s, ok := inner.(string); ok && s != "" {
+			env[name] = security.RedactSecret(s)
+		}
+	}
+	return env
+}
+
+// redactJSON redacts the values inside a JSON object passed as a string;
+// a string that doesn't parse as an object is masked entirely
+func redactJSON(key string, value any) any {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return redactValue(key, value)
+	}
+	var object map[string]any
+	if err := json.Unmarshal([]byte(s), &object); err != nil {
+		return security.RedactSecret(s)
+	}
+	data, err := json.Marshal(redactValue(key, object))
+	if err != nil {
+		return security.RedactSecret(s)
+	}
+	return string(data)
+}
+
+// isSecretName reports whether an argument, config key or variable name holds a credential
+func isSecretName(name string) bool {
+	if security.IsSecretField(name) {
+		return true
+	}
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	for _, part := range secretNameParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactValue(key string, value any) any {
+	switch v := value.(type) {
+	case string:
+		if v != "" && (isSecretName(key) || strings.Contains(v, "PRIVATE KEY-----")) {
+			return security.RedactSecret(v)
+		}
+		return security.RedactDSN(v)
+	case map[string]any:
+		for k, inner := range v {
+			v[k] = redactValue(k, inner)
+		}
+		return v
+	case []any:
+		for i, inner := range v {
+			v[i] = redactValue(key, inner)
+		}
+		return v
+	}
+	return value
+}
+
+// RedactArgs masks DSN passwords and the values of password flags in command arguments
+func RedactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case i > 0 && secretFlags[args[i-1]]:
+			redacted[i] = security.RedactSecret(arg)
+		case strings.Contains(arg, "="):
+			flag, value, _ := strings.Cut(arg, "=")
+			if secretFlags[flag] {
+				redacted[i] = flag + "=" + security.RedactSecret(value)
+			} else {
+				redacted[i] = security.RedactDSN(arg)
+			}
+		default:
+			redacted[i] = security.RedactDSN(arg)
+		}
+	}
+	return redacted
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,187 @@
+// Package diagnostics recognizes common goctl, protoc and go failures in
+// command output and turns them into typed errors with a file, line, cause
+// and suggested remedy
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jinguoxing/mcp-gozero/internal/doctor"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+// rule recognizes one kind of failure in a line of output
+type rule struct {
+	kind    string
+	pattern *regexp.Regexp
+	remedy  func(match []string) string
+}
+
+// rules are tried in order; the first match classifies a line
+var rules = []rule{
+	{
+		kind:    mcperrors.KindProtocPlugin,
+		pattern: regexp.MustCompile(`(protoc-gen-[\w-]+): program not found or is not executable|exec: "(protoc(?:-gen-[\w-]+)?)": executable file not found`),
+		remedy: func(match []string) string {
+			return installRemedy(firstGroup(match))
+		},
+	},
+	{
+		kind:    mcperrors.KindMissingGoPackage,
+		pattern: regexp.MustCompile(`unable to determine Go import path|(?i)go_package[^\n]*(?:required|missing|not (?:set|found|specified))|(?:missing|no)[^\n]*go_package`),
+		remedy: func(match []string) string {
+			return `Add a Go import path to the .proto file, e.g. option go_package = "./<package>";`
+		},
+	},
+	{
+		kind:    mcperrors.KindUnsupportedAny,
+		pattern: regexp.MustCompile(`(?i)(?:unsupported|not supported|unknown|undefined|invalid)[^\n]*(?:\bany\b|interface\s*\{\s*\})|(?:\bany\b|interface\s*\{\s*\})[^\n]*(?:unsupported|not supported)`),
+		remedy: func(match []string) string {
+			return "goctl does not support any or interface{} in .api types; use a concrete type, a struct, " +
+				"map[string]string or a string holding JSON"
+		},
+	},
+	{
+		kind:    mcperrors.KindDuplicateHandler,
+		pattern: regexp.MustCompile(`(?i)duplicate\s+(?:handler|route)(?:\s+(?:name|path))?[:\s]+['"]?([\w/{}:.-]+)|(\w+Handler) redeclared in this block`),
+		remedy: func(match []string) string {
+			return fmt.Sprintf("Give every route a unique @handler name and method/path; %s is declared more than once", firstGroup(match))
+		},
+	},
+	{
+		kind:    mcperrors.KindNetwork,
+		pattern: regexp.MustCompile(`dial tcp: lookup \S+|dial tcp [^\n]*(?:i/o timeout|connection refused)|TLS handshake timeout|connection reset by peer`),
+		remedy: func(match []string) string {
+			return "The module proxy could not be reached; check network access and GOPROXY (go env GOPROXY) and retry, " +
+				"the doctor tool checks both"
+		},
+	},
+	{
+		kind: mcperrors.KindModuleResolution,
+		pattern: regexp.MustCompile(`no required module provides package ([^\s;]+)|cannot find module providing package (\S+)` +
+			`|missing go\.sum entry for module providing package (\S+)|package (\S+) is not in (?:std|GOROOT)` +
+			`|module (\S+): (?:reading|Get) |(\S+@\S+): invalid version`),
+		remedy: func(match []string) string {
+			switch {
+			case match[3] != "":
+				return "Run go mod tidy to record the missing checksums in go.sum"
+			case match[5] != "", match[6] != "":
+				return "Check the module path and version; a private module may need GOPRIVATE or GOPROXY settings"
+			default:
+				return fmt.Sprintf("Run go mod tidy or go get %s; if the package belongs to this service, "+
+					"its import path must start with the module path in go.mod", firstGroup(match))
+			}
+		},
+	},
+	{
+		kind:    mcperrors.KindAPISyntax,
+		pattern: regexp.MustCompile(`\S+\.api(?::|\s+(?:line\s+)?)\d+:\d+`),
+		remedy: func(match []string) string {
+			return "Fix the .api syntax at the reported line and column, then run the tool again"
+		},
+	},
+	{
+		kind:    mcperrors.KindBuild,
+		pattern: regexp.MustCompile(`^\S+\.go:\d+(?::\d+)?: `),
+		remedy: func(match []string) string {
+			return "Fix the reported Go code; if the file is generated, fix the .api or .proto source and regenerate it"
+		},
+	},
+}
+
+// position is file:line:column or goctl's "file line:column"
+var position = regexp.MustCompile(`(\S+\.(?:api|proto|go))(?::|\s+(?:line\s+)?)(\d+)(?::(\d+))?:?\s*`)
+
+// fileName finds a source file named in a line without a position
+var fileName = regexp.MustCompile(`"?([\w./-]+\.(?:api|proto|go))\b"?`)
+
+// Parse recognizes the failures in command output, one per line at most,
+// in the order they appear
+func Parse(output string) []*mcperrors.DiagnosticError {
+	var found []*mcperrors.DiagnosticError
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		diagnostic := parseLine(line)
+		if diagnostic == nil {
+			continue
+		}
+		key := fmt.Sprintf("%s|%s|%d|%s", diagnostic.Kind, diagnostic.File, diagnostic.Line, diagnostic.Cause)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		found = append(found, diagnostic)
+	}
+	return found
+}
+
+// Diagnose parses the output of the ExecutionError in err's chain; each
+// diagnostic wraps that ExecutionError. It returns nil if err carries no
+// ExecutionError or nothing in its output is recognized
+func Diagnose(err error) []*mcperrors.DiagnosticError {
+	var execErr *mcperrors.ExecutionError
+	if !errors.As(err, &execErr) {
+		return nil
+	}
+	found := Parse(execErr.Stdout + "\n" + execErr.Stderr)
+	for _, diagnostic := range found {
+		diagnostic.ExecutionError = execErr
+	}
+	return found
+}
+
+func parseLine(line string) *mcperrors.DiagnosticError {
+	for _, r := range rules {
+		match := r.pattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		diagnostic := &mcperrors.DiagnosticError{
+			Kind:   r.kind,
+			Cause:  line,
+			Remedy: r.remedy(match),
+		}
+		if loc := position.FindStringSubmatchIndex(line); loc != nil {
+			diagnostic.File = line[loc[2]:loc[3]]
+			diagnostic.Line, _ = strconv.Atoi(line[loc[4]:loc[5]])
+			if loc[6] >= 0 {
+				diagnostic.Column, _ = strconv.Atoi(line[loc[6]:loc[7]])
+			}
+			if cause := strings.TrimSpace(line[loc[1]:]); cause != "" {
+				diagnostic.Cause = cause
+			}
+		} else if name := fileName.FindStringSubmatch(line); name != nil {
+			diagnostic.File = name[1]
+		}
+		return diagnostic
+	}
+	return nil
+}
+
+// firstGroup returns the first non-empty capture group
+func firstGroup(match []string) string {
+	for _, group := range match[1:] {
+		if group != "" {
+			return group
+		}
+	}
+	return ""
+}
+
+// installRemedy returns how to install a missing executable
+func installRemedy(name string) string {
+	for _, binary := range doctor.Binaries {
+		if binary.Name == name {
+			return fmt.Sprintf("Install %s: %s (the doctor tool checks the whole toolchain)", name, binary.Fix)
+		}
+	}
+	return fmt.Sprintf("Install %s and make sure it is on PATH (the doctor tool checks the whole toolchain)", name)
+}
@@ -0,0 +1,160 @@
+package diagnostics_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jinguoxing/mcp-gozero/internal/diagnostics"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		kind   string
+		file   string
+		line   int
+		cause  string
+		remedy string
+	}{
+		{
+			name:   "api syntax error",
+			output: "user.api 12:5 syntax error: expected ')', got 'IDENT'\n",
+			kind:   mcperrors.KindAPISyntax,
+			file:   "user.api",
+			line:   12,
+			cause:  "syntax error: expected ')', got 'IDENT'",
+		},
+		{
+			name:   "api syntax error of the antlr parser",
+			output: "Error: /tmp/spec/user.api line 3:10  mismatched input 'returns' expecting '('",
+			kind:   mcperrors.KindAPISyntax,
+			file:   "/tmp/spec/user.api",
+			line:   3,
+			cause:  "mismatched input 'returns' expecting '('",
+		},
+		{
+			name:   "unsupported any",
+			output: "user.api 7:9 unsupported type: any",
+			kind:   mcperrors.KindUnsupportedAny,
+			file:   "user.api",
+			line:   7,
+			remedy: "interface{}",
+		},
+		{
+			name: "missing go_package",
+			output: `protoc-gen-go: unable to determine Go import path for "order.proto"
+
+Please specify either:
+	• a "go_package" option in the .proto source file, or
+	• a "M" argument on the command line.
+--go_out: protoc-gen-go: Plugin failed with status code 1.`,
+			kind:   mcperrors.KindMissingGoPackage,
+			file:   "order.proto",
+			remedy: "option go_package",
+		},
+		{
+			name:   "protoc plugin not found",
+			output: "protoc-gen-go-grpc: program not found or is not executable\n--go-grpc_out: protoc-gen-go-grpc: Plugin failed with status code 1.",
+			kind:   mcperrors.KindProtocPlugin,
+			remedy: "go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest",
+		},
+		{
+			name:   "protoc not found",
+			output: `exec: "protoc": executable file not found in $PATH`,
+			kind:   mcperrors.KindProtocPlugin,
+			remedy: "goctl env check --install",
+		},
+		{
+			name:   "duplicate handler",
+			output: "user.api 20:3 duplicate handler: GetUserHandler",
+			kind:   mcperrors.KindDuplicateHandler,
+			file:   "user.api",
+			line:   20,
+			remedy: "GetUserHandler",
+		},
+		{
+			name:   "duplicate handler in go build",
+			output: "# example/user/internal/handler\ninternal/handler/routes.go:18:6: GetUserHandler redeclared in this block",
+			kind:   mcperrors.KindDuplicateHandler,
+			file:   "internal/handler/routes.go",
+			line:   18,
+		},
+		{
+			name:   "module resolution",
+			output: "user.go:6:2: no required module provides package github.com/example/user/internal/config; to add it:\n\tgo get github.com/example/user/internal/config",
+			kind:   mcperrors.KindModuleResolution,
+			file:   "user.go",
+			line:   6,
+			remedy: "go get github.com/example/user/internal/config",
+		},
+		{
+			name:   "unreachable proxy",
+			output: "go: github.com/zeromicro/go-zero@v1.6.0: Get \"https://proxy.golang.org/github.com/zeromicro/go-zero/@v/v1.6.0.mod\": dial tcp: lookup proxy.golang.org on 127.0.0.53:53: no such host",
+			kind:   mcperrors.KindNetwork,
+			remedy: "GOPROXY",
+		},
+		{
+			name:   "compile error",
+			output: "# example/user\n./user.go:12:2: undefined: handler.RegisterHandlers",
+			kind:   mcperrors.KindBuild,
+			file:   "./user.go",
+			line:   12,
+			cause:  "undefined: handler.RegisterHandlers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := diagnostics.Parse(tt.output)
+			if len(found) != 1 {
+				t.Fatalf("expected one diagnostic, got %v", found)
+			}
+			got := found[0]
+			if got.Kind != tt.kind || got.File != tt.file || got.Line != tt.line {
+				t.Errorf("got %s at %s:%d, want %s at %s:%d", got.Kind, got.File, got.Line, tt.kind, tt.file, tt.line)
+			}
+			if tt.cause != "" && got.Cause != tt.cause {
+				t.Errorf("cause = %q, want %q", got.Cause, tt.cause)
+			}
+			if !strings.Contains(got.Remedy, tt.remedy) {
+				t.Errorf("remedy %q should mention %q", got.Remedy, tt.remedy)
+			}
+		})
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	if found := diagnostics.Parse("Done.\nexit status 1\n"); len(found) != 0 {
+		t.Errorf("expected no diagnostics, got %v", found)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	stderr := "user.api 4:1 syntax error: unexpected '}'\nuser.api 4:1 syntax error: unexpected '}'\nuser.api 9:12 unsupported type: interface{}"
+	execErr := mcperrors.NewExecutionError("goctl", []string{"api", "go"}, "", stderr, 1, errors.New("exit status 1"))
+
+	found := diagnostics.Diagnose(fmt.Errorf("failed to generate API code: %w", execErr))
+	if len(found) != 2 {
+		t.Fatalf("expected repeated lines to be reported once, got %v", found)
+	}
+	if found[0].Error() != "user.api:4:1: syntax error: unexpected '}'" {
+		t.Errorf("unexpected message %q", found[0].Error())
+	}
+
+	var err error = found[1]
+	var target *mcperrors.ExecutionError
+	if !errors.Is(err, mcperrors.ErrUnsupportedAny) || !errors.As(err, &target) || target != execErr {
+		t.Error("a diagnostic should match its kind and wrap the ExecutionError")
+	}
+	if mcperrors.Category(err) != mcperrors.CategoryExecution {
+		t.Errorf("category = %q", mcperrors.Category(err))
+	}
+
+	if found := diagnostics.Diagnose(errors.New(stderr)); found != nil {
+		t.Error("errors without an ExecutionError should not be diagnosed")
+	}
+}
@@ -0,0 +1,43 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the concept as a markdown document
+func (c *Concept) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", c.Name))
+	sb.WriteString(fmt.Sprintf("Category: %s\n\n", c.Category))
+	sb.WriteString(c.Description + "\n")
+	if c.Example != "" {
+		sb.WriteString("\n## Example\n\n```go\n" + strings.TrimSpace(c.Example) + "\n```\n")
+	}
+	if len(c.RelatedDocs) > 0 {
+		sb.WriteString("\n## Related\n\n")
+		for _, doc := range c.RelatedDocs {
+			sb.WriteString(fmt.Sprintf("- %s\n", doc))
+		}
+	}
+	return sb.String()
+}
+
+// Markdown renders the migration guide as a markdown document
+func (g *MigrationGuide) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Migrating from %s to %s\n\n", g.FromFramework, g.ToGoZero))
+	sb.WriteString(fmt.Sprintf("Difficulty: %s\n\n", g.Difficulty))
+	sb.WriteString("## Key Differences\n\n" + g.KeyDifferences + "\n")
+	if len(g.Steps) > 0 {
+		// Steps are already numbered in the database
+		sb.WriteString("\n## Steps\n\n")
+		for _, step := range g.Steps {
+			sb.WriteString(step + "\n")
+		}
+	}
+	if g.Example != "" {
+		sb.WriteString("\n## Example\n\n```go\n" + strings.TrimSpace(g.Example) + "\n```\n")
+	}
+	return sb.String()
+}
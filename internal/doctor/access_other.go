@@ -0,0 +1,21 @@
+//go:build !unix
+
+package doctor
+
+import (
+	"errors"
+	"os"
+)
+
+// canWrite reports whether dir is writable from its permission bits,
+// without writing anything
+func canWrite(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0200 == 0 {
+		return errors.New("permission denied")
+	}
+	return nil
+}
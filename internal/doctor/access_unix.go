@@ -0,0 +1,14 @@
+//go:build unix
+
+package doctor
+
+import "syscall"
+
+// wOK is the access(2) mode that checks write permission
+const wOK = 0x2
+
+// canWrite reports whether the current user may create files in dir,
+// without writing anything
+func canWrite(dir string) error {
+	return syscall.Access(dir, wOK)
+}
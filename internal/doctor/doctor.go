@@ -0,0 +1,383 @@
+// Package doctor checks the toolchain and environment the generation tools
+// depend on: go, goctl, protoc and its Go plugins, GOPATH/bin on PATH, the
+// module cache, GOFLAGS, GOPROXY reachability and write permissions
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/goctl"
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// proxyTimeout bounds the request to each GOPROXY entry
+const proxyTimeout = 5 * time.Second
+
+// Check is one item of the checklist
+type Check struct {
+	Name    string
+	Status  string
+	Detail  string
+	Fix     string // command or action that resolves a warn or fail
+	Path    string // binary checks: the executable found
+	Version string // binary checks: the version it reports
+	Minimum string // binary checks: the oldest supported version
+}
+
+// Binary is an executable the tools need and the oldest supported version
+type Binary struct {
+	Name    string
+	Args    []string // prints the version
+	Minimum goctl.Version
+	Purpose string
+	Fix     string
+}
+
+// Binaries lists the executables checked, in checklist order
+var Binaries = []Binary{
+	{"go", []string{"version"}, goctl.Version{Major: 1, Minor: 19}, "builds and tidies every generated service",
+		"Install Go 1.19 or newer from https://go.dev/dl/"},
+	{"goctl", []string{"--version"}, goctl.Version{Major: 1, Minor: 4}, "generates go-zero code",
+		"go install github.com/zeromicro/go-zero/tools/goctl@latest"},
+	{"protoc", []string{"--version"}, goctl.Version{Major: 3}, "compiles .proto files for create_rpc_service",
+		"goctl env check --install --verbose --force"},
+	{"protoc-gen-go", []string{"--version"}, goctl.Version{Major: 1, Minor: 28}, "generates protobuf Go code for create_rpc_service",
+		"go install google.golang.org/protobuf/cmd/protoc-gen-go@latest"},
+	{"protoc-gen-go-grpc", []string{"--version"}, goctl.Version{Major: 1, Minor: 2}, "generates gRPC Go code for create_rpc_service",
+		"go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest"},
+}
+
+// Options configures Run
+type Options struct {
+	Dirs       []string     // directories the tools must be able to write to
+	HTTPClient *http.Client // used for GOPROXY; defaults to a client with a short timeout
+}
+
+// goEnv is the part of go env the checks use
+type goEnv struct {
+	GOPATH     string
+	GOMODCACHE string
+	GOFLAGS    string
+	GOPROXY    string
+}
+
+// Run performs every check
+func Run(ctx context.Context, opts Options) []Check {
+	var checks []Check
+	goPath := ""
+	for _, binary := range Binaries {
+		check := checkBinary(ctx, binary)
+		if binary.Name == "go" && check.Path != "" {
+			goPath = check.Path
+		}
+		checks = append(checks, check)
+	}
+
+	env, err := readGoEnv(ctx, goPath)
+	if err != nil {
+		checks = append(checks, Check{
+			Name:   "go env",
+			Status: StatusFail,
+			Detail: err.Error(),
+			Fix:    "Install Go and make sure `go env` runs",
+		})
+	} else {
+		checks = append(checks,
+			checkGopathBin(env),
+			checkModuleCache(env),
+			checkGoflags(env),
+			checkGoproxy(ctx, env, opts.HTTPClient),
+		)
+	}
+
+	for _, dir := range opts.Dirs {
+		checks = append(checks, checkWritable(dir))
+	}
+	return checks
+}
+
+// Failed returns the number of failed checks
+func Failed(checks []Check) int {
+	failed := 0
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Format renders the checklist with a fix under every warning and failure
+func Format(checks []Check) string {
+	var b strings.Builder
+	b.WriteString("=== Environment Doctor ===\n")
+	for _, check := range checks {
+		mark := "✅"
+		switch check.Status {
+		case StatusWarn:
+			mark = "⚠️"
+		case StatusFail:
+			mark = "❌"
+		}
+		fmt.Fprintf(&b, "%s %s: %s\n", mark, check.Name, check.Detail)
+		if check.Status != StatusPass && check.Fix != "" {
+			fmt.Fprintf(&b, "   fix: %s\n", check.Fix)
+		}
+	}
+	if failed := Failed(checks); failed > 0 {
+		fmt.Fprintf(&b, "\n❌ %d of %d checks failed\n", failed, len(checks))
+	} else {
+		fmt.Fprintf(&b, "\n✅ All %d checks passed\n", len(checks))
+	}
+	return b.String()
+}
+
+func checkBinary(ctx context.Context, binary Binary) Check {
+	check := Check{Name: binary.Name, Minimum: binary.Minimum.String(), Fix: binary.Fix}
+	path, err := goctl.DiscoverBinary(binary.Name)
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("not found; %s", binary.Purpose)
+		return check
+	}
+	check.Path = path
+
+	var version goctl.Version
+	if binary.Name == "goctl" {
+		// Shares the cached detection the generation tools use
+		caps := goctl.DetectCapabilities(ctx, path)
+		if !caps.Detected {
+			err = fmt.Errorf("%s", caps.Error)
+		}
+		version = caps.Version
+	} else {
+		var output string
+		if output, err = run(ctx, path, binary.Args...); err == nil {
+			version, err = goctl.ParseVersion(output)
+		}
+	}
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s: cannot determine version: %v", path, err)
+		return check
+	}
+
+	check.Version = version.String()
+	if !version.AtLeast(binary.Minimum) {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s is version %s, need %s or newer", path, check.Version, check.Minimum)
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = fmt.Sprintf("%s %s (minimum %s)", path, check.Version, check.Minimum)
+	return check
+}
+
+func readGoEnv(ctx context.Context, goPath string) (goEnv, error) {
+	var env goEnv
+	if goPath == "" {
+		return env, fmt.Errorf("go not found, cannot read go env")
+	}
+	output, err := run(ctx, goPath, "env", "-json", "GOPATH", "GOMODCACHE", "GOFLAGS", "GOPROXY")
+	if err != nil {
+		return env, fmt.Errorf("go env failed: %v", err)
+	}
+	// Warnings go env prints follow the JSON object
+	if err := json.NewDecoder(strings.NewReader(output)).Decode(&env); err != nil {
+		return env, fmt.Errorf("failed to parse go env: %v", err)
+	}
+	return env, nil
+}
+
+func checkGopathBin(env goEnv) Check {
+	check := Check{Name: "GOPATH/bin on PATH"}
+	onPath := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		onPath[filepath.Clean(dir)] = true
+	}
+	var missing []string
+	for _, dir := range filepath.SplitList(env.GOPATH) {
+		bin := filepath.Join(dir, "bin")
+		if !onPath[filepath.Clean(bin)] {
+			missing = append(missing, bin)
+		}
+	}
+	if len(missing) == 0 {
+		check.Status = StatusPass
+		check.Detail = "binaries installed with go install are found"
+		return check
+	}
+	check.Status = StatusWarn
+	check.Detail = fmt.Sprintf("%s is not on PATH, so tools installed with go install aren't found by your shell", strings.Join(missing, ", "))
+	check.Fix = `export PATH="$PATH:$(go env GOPATH)/bin"`
+	return check
+}
+
+func checkModuleCache(env goEnv) Check {
+	check := Check{Name: "module cache"}
+	if env.GOMODCACHE == "" {
+		check.Status = StatusFail
+		check.Detail = "GOMODCACHE is not set"
+		check.Fix = "go env -w GOMODCACHE=$HOME/go/pkg/mod"
+		return check
+	}
+
+	// A missing cache is created on first download if its parent is writable.
+	// The cache may lie outside the allowed roots, so it is never written to
+	dir := env.GOMODCACHE
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if err := canWrite(dir); err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s is not writable: %v", env.GOMODCACHE, err)
+		check.Fix = fmt.Sprintf("sudo chown -R $(id -u) %s, or go env -w GOMODCACHE=<writable dir>", env.GOMODCACHE)
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = env.GOMODCACHE
+	return check
+}
+
+func checkGoflags(env goEnv) Check {
+	check := Check{Name: "GOFLAGS"}
+	var problems []string
+	for _, flag := range strings.Fields(env.GOFLAGS) {
+		switch {
+		case flag == "-mod=vendor":
+			problems = append(problems, "-mod=vendor breaks go mod tidy and go build in generated services, which have no vendor directory")
+		case strings.HasPrefix(flag, "-modfile="):
+			problems = append(problems, "-modfile makes go ignore the go.mod of generated services")
+		}
+	}
+	if len(problems) > 0 {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%q: %s", env.GOFLAGS, strings.Join(problems, "; "))
+		check.Fix = "go env -u GOFLAGS (and unset GOFLAGS in the server's environment)"
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = "no flags that break generated modules"
+	if env.GOFLAGS != "" {
+		check.Detail = fmt.Sprintf("%q has no flags that break generated modules", env.GOFLAGS)
+	}
+	return check
+}
+
+func checkGoproxy(ctx context.Context, env goEnv, client *http.Client) Check {
+	check := Check{Name: "GOPROXY", Fix: "go env -w GOPROXY=https://proxy.golang.org,direct (or a reachable mirror such as https://goproxy.cn,direct)"}
+	if client == nil {
+		client = &http.Client{Timeout: proxyTimeout}
+	}
+
+	var proxies []string
+	for _, entry := range strings.FieldsFunc(env.GOPROXY, func(r rune) bool { return r == ',' || r == '|' }) {
+		switch entry = strings.TrimSpace(entry); entry {
+		case "off":
+			if len(proxies) == 0 {
+				check.Status = StatusWarn
+				check.Detail = "GOPROXY=off: only modules already in the module cache can be used, go mod tidy fails for new dependencies"
+				return check
+			}
+		case "direct", "":
+		default:
+			proxies = append(proxies, entry)
+		}
+	}
+	if len(proxies) == 0 {
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("%q: modules are fetched directly from their repositories", env.GOPROXY)
+		return check
+	}
+
+	var unreachable []string
+	for _, proxy := range proxies {
+		if err := probeProxy(ctx, client, proxy); err != nil {
+			unreachable = append(unreachable, fmt.Sprintf("%s (%v)", proxy, err))
+			continue
+		}
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("%s is reachable", proxy)
+		return check
+	}
+	check.Status = StatusFail
+	check.Detail = "unreachable: " + strings.Join(unreachable, ", ") + "; go mod tidy will fail for modules not in the cache"
+	return check
+}
+
+// probeProxy succeeds on any HTTP response; only connection failures count
+func probeProxy(ctx context.Context, client *http.Client, proxy string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(proxy, "/")+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func checkWritable(dir string) Check {
+	check := Check{Name: "write access"}
+	if err := probeWrite(dir); err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s: %v", dir, err)
+		check.Fix = fmt.Sprintf("sudo chown -R $(id -u) %s, or generate into a writable directory", dir)
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = dir
+	return check
+}
+
+// probeWrite creates and removes a temporary file in dir
+func probeWrite(dir string) error {
+	file, err := os.CreateTemp(dir, ".mcp-gozero-doctor-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// run executes a probe command and returns its stdout followed by its stderr
+func run(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr strings.Builder
+	err := process.Run(ctx, process.Command{
+		Step:   process.StepToolCheck,
+		Name:   name,
+		Args:   args,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if text := strings.TrimSpace(stderr.String()); text != "" {
+			return "", fmt.Errorf("%v: %s", err, text)
+		}
+		return "", err
+	}
+	return stdout.String() + stderr.String(), nil
+}
@@ -0,0 +1,119 @@
+package doctor_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jinguoxing/mcp-gozero/internal/doctor"
+)
+
+func writeScript(t *testing.T, path, output string) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho '"+output+"'\n"), 0755); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found in PATH")
+	}
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer proxy.Close()
+
+	home := t.TempDir()
+	bin := filepath.Join(home, "go", "bin")
+	writeScript(t, filepath.Join(bin, "goctl"), "goctl version 1.6.3 linux/amd64")
+	writeScript(t, filepath.Join(bin, "protoc"), "libprotoc 25.1")
+	writeScript(t, filepath.Join(bin, "protoc-gen-go"), "protoc-gen-go v1.20.0")
+	t.Setenv("HOME", home)
+	t.Setenv("GOCTL_PATH", "")
+	t.Setenv("PATH", filepath.Dir(goBin))
+	t.Setenv("GOPATH", filepath.Join(home, "gopath"))
+	t.Setenv("GOMODCACHE", filepath.Join(home, "gopath", "pkg", "mod"))
+	t.Setenv("GOFLAGS", "-mod=vendor")
+	t.Setenv("GOPROXY", proxy.URL+",direct")
+
+	writable := t.TempDir()
+	missing := filepath.Join(writable, "missing")
+	checks := doctor.Run(context.Background(), doctor.Options{Dirs: []string{writable, missing}})
+
+	status := make(map[string]string)
+	for _, check := range checks {
+		if check.Name == "write access" {
+			status[check.Name+" "+strings.SplitN(check.Detail, ":", 2)[0]] = check.Status
+			continue
+		}
+		status[check.Name] = check.Status
+	}
+	want := map[string]string{
+		"go":                       doctor.StatusPass,
+		"goctl":                    doctor.StatusPass,
+		"protoc":                   doctor.StatusPass,
+		"protoc-gen-go":            doctor.StatusFail, // older than the minimum
+		"protoc-gen-go-grpc":       doctor.StatusFail, // not installed
+		"GOPATH/bin on PATH":       doctor.StatusWarn,
+		"module cache":             doctor.StatusPass,
+		"GOFLAGS":                  doctor.StatusFail,
+		"GOPROXY":                  doctor.StatusPass,
+		"write access " + writable: doctor.StatusPass,
+		"write access " + missing:  doctor.StatusFail,
+	}
+	for name, expected := range want {
+		if status[name] != expected {
+			t.Errorf("%s: expected %s, got %q", name, expected, status[name])
+		}
+	}
+	if failed := doctor.Failed(checks); failed != 4 {
+		t.Errorf("expected 4 failed checks, got %d", failed)
+	}
+
+	report := doctor.Format(checks)
+	for _, fix := range []string{
+		"go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest",
+		"need 1.28.0 or newer",
+		"go env -u GOFLAGS",
+		"4 of 11 checks failed",
+	} {
+		if !strings.Contains(report, fix) {
+			t.Errorf("report should contain %q:\n%s", fix, report)
+		}
+	}
+}
+
+func TestRunGoproxy(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found in PATH")
+	}
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		goproxy string
+		want    string
+	}{
+		{"off", doctor.StatusWarn},
+		{"direct", doctor.StatusPass},
+		{closed.URL, doctor.StatusFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goproxy, func(t *testing.T) {
+			t.Setenv("GOPROXY", tt.goproxy)
+			for _, check := range doctor.Run(context.Background(), doctor.Options{}) {
+				if check.Name == "GOPROXY" && check.Status != tt.want {
+					t.Errorf("expected %s, got %s: %s", tt.want, check.Status, check.Detail)
+				}
+			}
+		})
+	}
+}
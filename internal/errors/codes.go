@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// Error codes reported to clients. Codes are stable: agents branch on them,
+// so existing codes must not be renamed. Diagnosed failures use their kind
+// (e.g. KindMissingGoPackage) as the code
+const (
+	CodeInvalidInput       = "invalid_input"
+	CodeInvalidServiceName = "invalid_service_name"
+	CodeInvalidPort        = "invalid_port"
+	CodeInvalidPath        = "invalid_path"
+	CodeGoctlNotFound      = "goctl_not_found"
+	CodeGoctlFailed        = "goctl_failed"
+	CodeCommandFailed      = "command_failed"
+	CodeModuleInit         = "module_init_failed"
+	CodeImportFix          = "import_fix_failed"
+	CodeConfigUpdate       = "config_update_failed"
+	CodeStyleConflict      = "style_conflict"
+	CodeProjectStructure   = "invalid_project_structure"
+	CodeBuild              = "build_failed"
+	CodeTimeout            = "timeout"
+	CodeCancelled          = "cancelled"
+	CodeNotFound           = "not_found"
+	CodeInternal           = "internal"
+)
+
+// sentinelCodes maps sentinels to codes, most specific first
+var sentinelCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrGoctlNotFound, CodeGoctlNotFound},
+	{ErrInvalidServiceName, CodeInvalidServiceName},
+	{ErrInvalidPort, CodeInvalidPort},
+	{ErrInvalidPath, CodeInvalidPath},
+	{ErrModuleInit, CodeModuleInit},
+	{ErrImportFix, CodeImportFix},
+	{ErrConfigUpdate, CodeConfigUpdate},
+	{ErrStyleConflict, CodeStyleConflict},
+	{ErrProjectStructure, CodeProjectStructure},
+	{ErrBuild, CodeBuild},
+	{ErrGoctlExecution, CodeGoctlFailed},
+}
+
+// Code returns the machine-readable code of an error; unknown errors are
+// internal
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+	var diagErr *DiagnosticError
+	if errors.As(err, &diagErr) {
+		return diagErr.Kind
+	}
+	switch {
+	case IsTimeout(err):
+		return CodeTimeout
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return CodeCancelled
+	}
+	for _, sc := range sentinelCodes {
+		if errors.Is(err, sc.err) {
+			return sc.code
+		}
+	}
+	var execErr *ExecutionError
+	switch {
+	case errors.As(err, &execErr):
+		if strings.HasPrefix(filepath.Base(execErr.Command), "goctl") {
+			return CodeGoctlFailed
+		}
+		return CodeCommandFailed
+	case isExitError(err):
+		return CodeCommandFailed
+	case IsValidationError(err):
+		return CodeInvalidInput
+	case IsNotFound(err):
+		return CodeNotFound
+	default:
+		return CodeInternal
+	}
+}
+
+// Retryable reports whether the same call may succeed when repeated, e.g.
+// after a timeout or a network failure; invalid input and failures in the
+// generated code are not retryable
+func Retryable(err error) bool {
+	return IsTimeout(err) || errors.Is(err, ErrNetwork)
+}
+
+// Details returns the fields of the typed errors in err's chain, such as
+// the invalid field, the path or the command's exit code
+func Details(err error) map[string]any {
+	details := make(map[string]any)
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		details["field"] = valErr.Field
+		details["value"] = valErr.Value
+	}
+	var pathErr *PathError
+	if errors.As(err, &pathErr) {
+		details["path"] = pathErr.Path
+		details["op"] = pathErr.Op
+	}
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		details["step"] = timeoutErr.Step
+		details["timeout"] = timeoutErr.Timeout.String()
+	}
+	var execErr *ExecutionError
+	if errors.As(err, &execErr) {
+		details["command"] = filepath.Base(execErr.Command)
+		details["args"] = execErr.Args
+		details["exit_code"] = execErr.ExitCode
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+// Wrap marks err with a sentinel for errors.Is and Code, keeping err's
+// message
+func Wrap(sentinel, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &wrapError{sentinel: sentinel, err: err}
+}
+
+type wrapError struct {
+	sentinel error
+	err      error
+}
+
+func (e *wrapError) Error() string { return e.err.Error() }
+
+func (e *wrapError) Unwrap() []error { return []error{e.sentinel, e.err} }
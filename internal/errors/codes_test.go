@@ -0,0 +1,73 @@
+package errors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+func TestCode(t *testing.T) {
+	goctlFailure := mcperrors.NewExecutionError("/usr/local/bin/goctl", []string{"api", "new", "user"}, "", "boom", 1, errors.New("exit status 1"))
+	goFailure := mcperrors.NewExecutionError("go", []string{"build"}, "", "boom", 1, errors.New("exit status 1"))
+
+	tests := []struct {
+		name     string
+		err      error
+		code     string
+		category string
+	}{
+		{"validation", mcperrors.NewValidationError("port", "80", "port must be between 1024 and 65535", mcperrors.ErrInvalidPort), mcperrors.CodeInvalidPort, mcperrors.CategoryValidation},
+		{"generic validation", fmt.Errorf("bad input: %w", mcperrors.ErrValidationFailed), mcperrors.CodeInvalidInput, mcperrors.CategoryValidation},
+		{"goctl", goctlFailure, mcperrors.CodeGoctlFailed, mcperrors.CategoryExecution},
+		{"go", goFailure, mcperrors.CodeCommandFailed, mcperrors.CategoryExecution},
+		{"sentinel over command", mcperrors.Wrap(mcperrors.ErrModuleInit, fmt.Errorf("go mod tidy failed: %w", goFailure)), mcperrors.CodeModuleInit, mcperrors.CategoryExecution},
+		{"timeout", fmt.Errorf("go mod tidy failed: %w", mcperrors.NewTimeoutError("go_mod_tidy", "go", nil, time.Minute)), mcperrors.CodeTimeout, mcperrors.CategoryTimeout},
+		{"cancelled", fmt.Errorf("goctl: %w", context.Canceled), mcperrors.CodeCancelled, mcperrors.CategoryCancelled},
+		{"diagnostic", &mcperrors.DiagnosticError{ExecutionError: goctlFailure, Kind: mcperrors.KindAPISyntax}, mcperrors.KindAPISyntax, mcperrors.CategoryExecution},
+		{"not found", mcperrors.NewPathError("/tmp/user.api", "read", "failed to read API file", nil), mcperrors.CodeNotFound, mcperrors.CategoryNotFound},
+		{"exit error", &exec.ExitError{}, mcperrors.CodeCommandFailed, mcperrors.CategoryExecution},
+		{"unknown", errors.New("boom"), mcperrors.CodeInternal, mcperrors.CategoryInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := mcperrors.Code(tt.err); code != tt.code {
+				t.Errorf("Code = %q, want %q", code, tt.code)
+			}
+			if category := mcperrors.Category(tt.err); category != tt.category {
+				t.Errorf("Category = %q, want %q", category, tt.category)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	if !mcperrors.Retryable(mcperrors.NewTimeoutError("goctl", "goctl", nil, time.Minute)) {
+		t.Error("timeouts should be retryable")
+	}
+	if !mcperrors.Retryable(&mcperrors.DiagnosticError{Kind: mcperrors.KindNetwork}) {
+		t.Error("network failures should be retryable")
+	}
+	if mcperrors.Retryable(mcperrors.NewValidationError("service_name", "1x", "must start with a letter", mcperrors.ErrInvalidServiceName)) {
+		t.Error("invalid input should not be retryable")
+	}
+}
+
+func TestDetails(t *testing.T) {
+	err := mcperrors.Wrap(mcperrors.ErrBuild, fmt.Errorf("build failed: %w",
+		mcperrors.NewExecutionError("/usr/local/go/bin/go", []string{"build", "."}, "", "", 2, errors.New("exit status 2"))))
+	details := mcperrors.Details(err)
+	if details["command"] != "go" || details["exit_code"] != 2 {
+		t.Errorf("unexpected details %v", details)
+	}
+	if err.Error() != "build failed: go execution failed: /usr/local/go/bin/go [build .] (exit code: 2)\nerror: exit status 2" {
+		t.Errorf("Wrap should keep the message, got %q", err.Error())
+	}
+	if mcperrors.Details(errors.New("boom")) != nil {
+		t.Error("untyped errors have no details")
+	}
+}
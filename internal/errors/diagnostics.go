@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of failure recognized in goctl, protoc and go output
+const (
+	KindAPISyntax        = "api_syntax"
+	KindUnsupportedAny   = "unsupported_any"
+	KindMissingGoPackage = "missing_go_package"
+	KindProtocPlugin     = "protoc_plugin_not_found"
+	KindDuplicateHandler = "duplicate_handler"
+	KindModuleResolution = "module_resolution"
+	KindNetwork          = "network"
+	KindBuild            = "build"
+)
+
+var (
+	ErrAPISyntax        = errors.New("api syntax error")
+	ErrUnsupportedAny   = errors.New("unsupported any type")
+	ErrMissingGoPackage = errors.New("missing go_package option")
+	ErrProtocPlugin     = errors.New("protoc plugin not found")
+	ErrDuplicateHandler = errors.New("duplicate handler")
+	ErrModuleResolution = errors.New("module resolution failed")
+	ErrNetwork          = errors.New("network failure")
+	ErrBuild            = errors.New("build failed")
+)
+
+var kindErrors = map[string]error{
+	KindAPISyntax:        ErrAPISyntax,
+	KindUnsupportedAny:   ErrUnsupportedAny,
+	KindMissingGoPackage: ErrMissingGoPackage,
+	KindProtocPlugin:     ErrProtocPlugin,
+	KindDuplicateHandler: ErrDuplicateHandler,
+	KindModuleResolution: ErrModuleResolution,
+	KindNetwork:          ErrNetwork,
+	KindBuild:            ErrBuild,
+}
+
+// DiagnosticError is an ExecutionError whose output was recognized as a
+// known failure: where it happened, what caused it and how to fix it.
+// It matches both its kind's sentinel (e.g. ErrMissingGoPackage) and the
+// ExecutionError with errors.Is and errors.As
+type DiagnosticError struct {
+	*ExecutionError `json:"-"`
+
+	Kind   string `json:"kind"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	Cause  string `json:"cause"`
+	Remedy string `json:"remedy"`
+}
+
+func (e *DiagnosticError) Error() string {
+	if location := e.Location(); location != "" {
+		return fmt.Sprintf("%s: %s", location, e.Cause)
+	}
+	return e.Cause
+}
+
+func (e *DiagnosticError) Unwrap() []error {
+	errs := []error{kindErrors[e.Kind]}
+	if e.ExecutionError != nil {
+		errs = append(errs, e.ExecutionError)
+	}
+	return errs
+}
+
+// Location returns file:line:column, as far as known
+func (e *DiagnosticError) Location() string {
+	switch {
+	case e.File == "":
+		return ""
+	case e.Line == 0:
+		return e.File
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d", e.File, e.Line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	}
+}
+
+func IsDiagnostic(err error) bool {
+	var diagErr *DiagnosticError
+	return errors.As(err, &diagErr)
+}
@@ -1,8 +1,12 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os/exec"
+	"path/filepath"
+	"time"
 )
 
 // Common error types for mcp-zero project
@@ -18,6 +22,8 @@ var (
 	ErrModuleInit         = errors.New("module initialization failed")
 	ErrImportFix          = errors.New("import path fix failed")
 	ErrConfigUpdate       = errors.New("config update failed")
+	ErrStyleConflict      = errors.New("style conflicts detected")
+	ErrTimeout            = errors.New("command timed out")
 )
 
 // ValidationError represents a validation failure
@@ -48,7 +54,7 @@ func NewValidationError(field, value, message string, err error) *ValidationErro
 	}
 }
 
-// ExecutionError represents a goctl execution failure
+// ExecutionError represents a failed goctl or go command
 type ExecutionError struct {
 	Command  string
 	Args     []string
@@ -59,7 +65,7 @@ type ExecutionError struct {
 }
 
 func (e *ExecutionError) Error() string {
-	msg := fmt.Sprintf("goctl execution failed: %s %v (exit code: %d)", e.Command, e.Args, e.ExitCode)
+	msg := fmt.Sprintf("%s execution failed: %s %v (exit code: %d)", filepath.Base(e.Command), e.Command, e.Args, e.ExitCode)
 	if e.Stderr != "" {
 		msg += fmt.Sprintf("\nstderr: %s", e.Stderr)
 	}
@@ -84,6 +90,31 @@ func NewExecutionError(command string, args []string, stdout, stderr string, exi
 	}
 }
 
+// TimeoutError represents a subprocess killed after exceeding its step timeout
+type TimeoutError struct {
+	Step    string
+	Command string
+	Args    []string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s: %s %v", e.Step, e.Timeout, e.Command, e.Args)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return ErrTimeout
+}
+
+func NewTimeoutError(step, command string, args []string, timeout time.Duration) *TimeoutError {
+	return &TimeoutError{
+		Step:    step,
+		Command: command,
+		Args:    args,
+		Timeout: timeout,
+	}
+}
+
 // PathError represents a file/directory path error
 type PathError struct {
 	Path    string
@@ -130,3 +161,42 @@ func IsExecutionError(err error) bool {
 	var execErr *ExecutionError
 	return errors.As(err, &execErr) || errors.Is(err, ErrGoctlExecution)
 }
+
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrTimeout)
+}
+
+// Error categories reported in tool metrics and logs
+const (
+	CategoryValidation = "validation"
+	CategoryTimeout    = "timeout"
+	CategoryCancelled  = "cancelled"
+	CategoryExecution  = "execution"
+	CategoryNotFound   = "not_found"
+	CategoryInternal   = "internal"
+)
+
+// Category classifies an error for metrics; unknown errors are internal
+func Category(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case IsTimeout(err):
+		return CategoryTimeout
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return CategoryCancelled
+	case IsValidationError(err):
+		return CategoryValidation
+	case IsExecutionError(err), isExitError(err):
+		return CategoryExecution
+	case IsNotFound(err):
+		return CategoryNotFound
+	default:
+		return CategoryInternal
+	}
+}
+
+func isExitError(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr)
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 // UpdateConfigFile updates configuration files with correct settings
@@ -13,7 +15,7 @@ func UpdateConfigFile(projectPath string, serviceName string, port int) error {
 	etcDir := filepath.Join(projectPath, "etc")
 	configFiles, err := filepath.Glob(filepath.Join(etcDir, "*.yaml"))
 	if err != nil {
-		return fmt.Errorf("failed to search for config files: %w", err)
+		return mcperrors.Wrap(mcperrors.ErrConfigUpdate, mcperrors.NewPathError(etcDir, "glob", "failed to search for config files", err))
 	}
 
 	if len(configFiles) == 0 {
@@ -25,7 +27,7 @@ func UpdateConfigFile(projectPath string, serviceName string, port int) error {
 	configFile := configFiles[0]
 	content, err := os.ReadFile(configFile)
 	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
+		return mcperrors.Wrap(mcperrors.ErrConfigUpdate, mcperrors.NewPathError(configFile, "read", "failed to read config file", err))
 	}
 
 	configContent := string(content)
@@ -42,7 +44,7 @@ func UpdateConfigFile(projectPath string, serviceName string, port int) error {
 
 	// Write updated config
 	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+		return mcperrors.Wrap(mcperrors.ErrConfigUpdate, mcperrors.NewPathError(configFile, "write", "failed to write config file", err))
 	}
 
 	return nil
@@ -1,10 +1,13 @@
 package fixer_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jinguoxing/mcp-gozero/internal/fixer"
 )
@@ -39,7 +42,7 @@ func main() {
 
 	// Fix imports
 	moduleName := "github.com/test/project"
-	err = fixer.FixImports(tmpDir, moduleName)
+	_, err = fixer.FixImports(tmpDir, moduleName)
 	if err != nil {
 		t.Fatalf("FixImports() failed: %v", err)
 	}
@@ -85,7 +88,7 @@ func TestInitializeGoModule(t *testing.T) {
 	moduleName := "github.com/test/testmodule"
 
 	// Initialize module
-	err = fixer.InitializeGoModule(tmpDir, moduleName)
+	err = fixer.InitializeGoModule(context.Background(), tmpDir, moduleName)
 	if err != nil {
 		t.Fatalf("InitializeGoModule() failed: %v", err)
 	}
@@ -107,7 +110,7 @@ func TestInitializeGoModule(t *testing.T) {
 	}
 
 	// Test idempotency - calling again should not fail
-	err = fixer.InitializeGoModule(tmpDir, moduleName)
+	err = fixer.InitializeGoModule(context.Background(), tmpDir, moduleName)
 	if err != nil {
 		t.Errorf("InitializeGoModule() should be idempotent but failed on second call: %v", err)
 	}
@@ -122,7 +125,7 @@ func TestTidyGoModule(t *testing.T) {
 
 	// Create a simple Go module
 	moduleName := "github.com/test/tidytest"
-	err = fixer.InitializeGoModule(tmpDir, moduleName)
+	err = fixer.InitializeGoModule(context.Background(), tmpDir, moduleName)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -142,7 +145,7 @@ func main() {
 	}
 
 	// Run tidy
-	err = fixer.TidyGoModule(tmpDir)
+	err = fixer.TidyGoModule(context.Background(), tmpDir)
 	if err != nil {
 		t.Fatalf("TidyGoModule() failed: %v", err)
 	}
@@ -165,7 +168,7 @@ func TestVerifyBuild(t *testing.T) {
 
 		// Create a simple valid Go project
 		moduleName := "github.com/test/buildtest"
-		err = fixer.InitializeGoModule(tmpDir, moduleName)
+		err = fixer.InitializeGoModule(context.Background(), tmpDir, moduleName)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -183,13 +186,13 @@ func main() {
 			t.Fatal(err)
 		}
 
-		err = fixer.TidyGoModule(tmpDir)
+		err = fixer.TidyGoModule(context.Background(), tmpDir)
 		if err != nil {
 			t.Fatal(err)
 		}
 
 		// Verify build succeeds
-		err = fixer.VerifyBuild(tmpDir)
+		err = fixer.VerifyBuild(context.Background(), tmpDir)
 		if err != nil {
 			t.Errorf("VerifyBuild() should succeed for valid project, got error: %v", err)
 		}
@@ -205,7 +208,7 @@ func main() {
 
 		// Create project with syntax error
 		moduleName := "github.com/test/invalidtest"
-		err = fixer.InitializeGoModule(tmpDir, moduleName)
+		err = fixer.InitializeGoModule(context.Background(), tmpDir, moduleName)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -221,7 +224,7 @@ func main() {
 		}
 
 		// Verify build fails
-		err = fixer.VerifyBuild(tmpDir)
+		err = fixer.VerifyBuild(context.Background(), tmpDir)
 		if err == nil {
 			t.Error("VerifyBuild() should fail for invalid project")
 		}
@@ -343,7 +346,7 @@ func Handle() {}
 
 	// Fix imports
 	moduleName := "example.com/myproject"
-	err = fixer.FixImports(tmpDir, moduleName)
+	_, err = fixer.FixImports(tmpDir, moduleName)
 	if err != nil {
 		t.Fatalf("FixImports() failed: %v", err)
 	}
@@ -364,3 +367,144 @@ func Handle() {}
 		}
 	}
 }
+
+func TestFixImportsOnlyTouchesImportSpecs(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `package main
+
+import (
+	"fmt"
+
+	"` + tmpDir + `/internal/config"
+)
+
+// Generated from ` + tmpDir + `/user.api
+func main() {
+	fmt.Println("` + tmpDir + `/etc/user.yaml", config.Config{})
+}
+`
+	mainFile := filepath.Join(tmpDir, "main.go")
+	vendorFile := filepath.Join(tmpDir, "vendor", "example.com", "lib", "lib.go")
+	untouchedFile := filepath.Join(tmpDir, "internal", "config", "config.go")
+	for path, data := range map[string]string{
+		mainFile:      content,
+		vendorFile:    "package lib\n\nimport _ \"" + tmpDir + "/internal/config\"\n",
+		untouchedFile: "package config\n\ntype Config struct{}\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(untouchedFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := fixer.FixImports(tmpDir, "github.com/test/project")
+	if err != nil {
+		t.Fatalf("FixImports() failed: %v", err)
+	}
+
+	want := []fixer.FileChange{{
+		Path:    "main.go",
+		Imports: []fixer.ImportChange{{Line: 6, Old: tmpDir + "/internal/config", New: "github.com/test/project/internal/config"}},
+	}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+
+	modified, _ := os.ReadFile(mainFile)
+	if !strings.Contains(string(modified), "// Generated from "+tmpDir+"/user.api") ||
+		!strings.Contains(string(modified), `"`+tmpDir+`/etc/user.yaml"`) {
+		t.Errorf("comments and string literals should be left alone:\n%s", modified)
+	}
+	if vendored, _ := os.ReadFile(vendorFile); !strings.Contains(string(vendored), tmpDir) {
+		t.Error("vendor should be skipped")
+	}
+	if info, _ := os.Stat(untouchedFile); !info.ModTime().Equal(old) {
+		t.Error("files without matching imports should not be written")
+	}
+}
+
+func TestRewriteImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `package main
+
+import (
+	"github.com/example/user/internal/svc"
+	"github.com/example/userapi/types"
+	legacy "github.com/example/user/pkg/legacy"
+	"github.com/example/user"
+)
+
+var _ = svc.ServiceContext{}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := fixer.RewriteImports(tmpDir, map[string]string{
+		"github.com/example/user":            "github.com/acme/user",
+		"github.com/example/user/pkg/legacy": "github.com/acme/legacy/",
+	})
+	if err != nil {
+		t.Fatalf("RewriteImports() failed: %v", err)
+	}
+	if len(changes) != 1 || len(changes[0].Imports) != 3 {
+		t.Fatalf("expected three rewritten imports in one file, got %+v", changes)
+	}
+
+	modified, _ := os.ReadFile(filepath.Join(tmpDir, "main.go"))
+	for _, expected := range []string{
+		`"github.com/acme/user/internal/svc"`,
+		`legacy "github.com/acme/legacy"`,
+		`"github.com/acme/user"`,
+		`"github.com/example/userapi/types"`,
+	} {
+		if !strings.Contains(string(modified), expected) {
+			t.Errorf("expected %s in:\n%s", expected, modified)
+		}
+	}
+}
+
+func TestRewriteImportsInvalidSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "testdata"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "pkg"), 0755)
+	files := map[string]string{
+		"testdata/broken.go": "package main\n\nimport \"a/x\"\n\nfunc {",
+		"pkg/broken.go":      "package pkg\n\nimport \"a/x\"\n\nfunc {",
+		"main.go":            "package main\n\nimport \"a/x\"\n\nvar _ = x.Y\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, err := fixer.RewriteImports(tmpDir, map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("unparseable files should not fail the rewrite: %v", err)
+	}
+	byPath := make(map[string]fixer.FileChange)
+	for _, change := range changes {
+		byPath[change.Path] = change
+	}
+	if _, ok := byPath["testdata/broken.go"]; ok {
+		t.Error("testdata should be skipped without being reported")
+	}
+	if skipped := byPath["pkg/broken.go"].Skipped; !strings.Contains(skipped, "parse error") {
+		t.Errorf("expected pkg/broken.go to be reported as skipped, got %+v", byPath["pkg/broken.go"])
+	}
+	if change := byPath["main.go"]; change.Skipped != "" || len(change.Imports) != 1 || change.Imports[0].New != "b/x" {
+		t.Errorf("expected main.go to be rewritten, got %+v", change)
+	}
+	for _, name := range []string{"testdata/broken.go", "pkg/broken.go"} {
+		if data, _ := os.ReadFile(filepath.Join(tmpDir, name)); string(data) != files[name] {
+			t.Errorf("%s should be left alone, got:\n%s", name, data)
+		}
+	}
+}
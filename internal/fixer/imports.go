@@ -1,63 +1,155 @@
 package fixer
 
 import (
-"fmt"
-"os"
-"path/filepath"
-"regexp"
-"strings"
+	"bytes"
+	"errors"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
+// ImportChange is one import path rewritten in a file
+type ImportChange struct {
+	Line int    `json:"line"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// FileChange lists the imports rewritten in one file
+type FileChange struct {
+	Path    string         `json:"path"` // slash-separated, relative to the project
+	Imports []ImportChange `json:"imports"`
+	Skipped string         `json:"skipped,omitempty"` // why the file was left alone, e.g. a parse error
+}
+
 // FixImports fixes import paths in generated code
-// Replaces absolute paths with local module names
-func FixImports(projectPath string, moduleName string) error {
-	// Find all .go files
-	var goFiles []string
-	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
-if err != nil {
-return err
+// Replaces imports of the absolute project path with the module name
+func FixImports(projectPath string, moduleName string) ([]FileChange, error) {
+	return RewriteImports(projectPath, map[string]string{filepath.ToSlash(projectPath): moduleName})
 }
-if !info.IsDir() && strings.HasSuffix(path, ".go") {
-			goFiles = append(goFiles, path)
+
+// RewriteImports maps import path prefixes in every .go file below
+// projectPath: an import equal to an old prefix, or below it, gets the new
+// prefix, the longest matching prefix winning. Only import specs change;
+// strings and comments are left alone. vendor, testdata and hidden
+// directories are skipped, rewritten files are formatted with go/format, and
+// files without a matching import are not written. Files that don't parse
+// are left alone and reported with Skipped set
+func RewriteImports(projectPath string, mapping map[string]string) ([]FileChange, error) {
+	rewrite := prefixRewriter(mapping)
+	changes := []FileChange{}
+	err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipDir(projectPath, path, d) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		imports, err := rewriteFileImports(path, rewrite)
+		var syntaxErr scanner.ErrorList
+		if errors.As(err, &syntaxErr) {
+			changes = append(changes, FileChange{Path: filepath.ToSlash(rel), Imports: []ImportChange{}, Skipped: "parse error: " + syntaxErr[0].Error()})
+			return nil
+		}
+		if err != nil {
+			return mcperrors.NewPathError(path, "fix", "failed to fix imports", err)
+		}
+		if len(imports) > 0 {
+			changes = append(changes, FileChange{Path: filepath.ToSlash(rel), Imports: imports})
 		}
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("failed to walk project directory: %w", err)
-	}
-
-	// Fix imports in each file
-	for _, goFile := range goFiles {
-		if err := fixImportsInFile(goFile, projectPath, moduleName); err != nil {
-			return fmt.Errorf("failed to fix imports in %s: %w", goFile, err)
+		var pathErr *mcperrors.PathError
+		if !errors.As(err, &pathErr) {
+			err = mcperrors.NewPathError(projectPath, "walk", "failed to walk project directory", err)
 		}
+		return nil, mcperrors.Wrap(mcperrors.ErrImportFix, err)
 	}
-
-	return nil
+	return changes, nil
 }
 
-// fixImportsInFile fixes imports in a single Go file
-func fixImportsInFile(filePath string, projectPath string, moduleName string) error {
-	content, err := os.ReadFile(filePath)
+// rewriteFileImports rewrites the imports of one file and writes it back
+// formatted if any changed
+func rewriteFileImports(path string, rewrite func(string) (string, bool)) ([]ImportChange, error) {
+	src, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
 	}
 
-	originalContent := string(content)
-	modifiedContent := originalContent
+	var imports []ImportChange
+	for _, spec := range file.Imports {
+		oldPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		newPath, ok := rewrite(oldPath)
+		if !ok || newPath == oldPath {
+			continue
+		}
+		spec.Path.Value = strconv.Quote(newPath)
+		imports = append(imports, ImportChange{Line: fset.Position(spec.Pos()).Line, Old: oldPath, New: newPath})
+	}
+	if len(imports) == 0 {
+		return nil, nil
+	}
 
-	// Fix absolute path imports
-	// Pattern: import "absolute/path/to/project/internal/..."
-	// Replace with: import "moduleName/internal/..."
-	absPathPattern := regexp.MustCompile(regexp.QuoteMeta(projectPath) + `(/[^"]+)`)
-	modifiedContent = absPathPattern.ReplaceAllString(modifiedContent, moduleName+"$1")
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return imports, nil
+}
 
-	// Only write if content changed
-	if modifiedContent != originalContent {
-		if err := os.WriteFile(filePath, []byte(modifiedContent), 0644); err != nil {
-			return err
+// prefixRewriter returns a function mapping a path equal to or below an old
+// prefix to the new prefix, the longest matching prefix winning
+func prefixRewriter(mapping map[string]string) func(string) (string, bool) {
+	type prefixMapping struct{ old, new string }
+	prefixes := make([]prefixMapping, 0, len(mapping))
+	for oldPrefix, newPrefix := range mapping {
+		prefixes = append(prefixes, prefixMapping{strings.TrimSuffix(oldPrefix, "/"), strings.TrimSuffix(newPrefix, "/")})
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i].old) > len(prefixes[j].old) })
+	return func(path string) (string, bool) {
+		for _, prefix := range prefixes {
+			if path == prefix.old || strings.HasPrefix(path, prefix.old+"/") {
+				return prefix.new + strings.TrimPrefix(path, prefix.old), true
+			}
 		}
+		return "", false
 	}
+}
 
-	return nil
+// skipDir reports whether a walk below root should skip the directory:
+// vendor, testdata and hidden directories are not part of the project's sources
+func skipDir(root, path string, d fs.DirEntry) bool {
+	return path != root && (d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), "."))
 }
@@ -0,0 +1,242 @@
+package fixer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+// ModuleRename is the outcome of RenameModule
+type ModuleRename struct {
+	OldModule string       `json:"old_module"`
+	NewModule string       `json:"new_module"`
+	Files     []FileChange `json:"files"` // go.mod, .go imports, .api imports, go_package options and go.work
+}
+
+var (
+	moduleDirective = regexp.MustCompile(`^(\s*module\s+"?)([^"\s]+)("?.*)$`)
+	apiImportLine   = regexp.MustCompile(`^(\s*(?:import\s+)?")([^"]+)(".*)$`)
+	goPackageOption = regexp.MustCompile(`^(\s*option\s+go_package\s*=\s*")([^";]+)((?:;[^"]*)?".*)$`)
+	workReplaceLine = regexp.MustCompile(`^(\s*(?:replace\s+)?)(\S+)((?:\s+\S+)?\s+=>\s+)(\S+)(.*)$`)
+	blockStart      = regexp.MustCompile(`^\s*(import|replace)\s*\(\s*(//.*)?$`)
+	singleDirective = regexp.MustCompile(`^\s*(import|replace)\s`)
+	blockEnd        = regexp.MustCompile(`^\s*\)\s*(//.*)?$`)
+	specExtensions  = map[string]bool{".api": true, ".proto": true}
+)
+
+// FindGoWork returns the go.work file governing projectPath, looking in
+// projectPath and its parents, or "" if there is none
+func FindGoWork(projectPath string) string {
+	dir := projectPath
+	for {
+		goWork := filepath.Join(dir, "go.work")
+		if fileExists(goWork) {
+			return goWork
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// RenameModule renames the module in projectPath's go.mod to newModule and
+// rewrites every reference to the old module path below projectPath: Go
+// import specs, import paths of .api files and go_package options of .proto
+// files. When goWork is set, its replace directives of the old module are
+// updated too. Only files that change are written; the build is left to
+// the caller
+func RenameModule(projectPath, newModule, goWork string) (*ModuleRename, error) {
+	goMod := filepath.Join(projectPath, "go.mod")
+	var oldModule string
+	goModChanges, err := rewriteLines(goMod, func(line string, _ string) (string, string, string, bool) {
+		m := moduleDirective.FindStringSubmatch(line)
+		if m == nil || oldModule != "" {
+			return "", "", "", false
+		}
+		oldModule = m[2]
+		return m[1] + newModule + m[3], m[2], newModule, true
+	})
+	if err != nil {
+		return nil, mcperrors.Wrap(mcperrors.ErrImportFix, mcperrors.NewPathError(goMod, "rename", "failed to update go.mod", err))
+	}
+	if oldModule == "" {
+		return nil, mcperrors.Wrap(mcperrors.ErrProjectStructure, fmt.Errorf("could not find module name in go.mod"))
+	}
+
+	rename := &ModuleRename{OldModule: oldModule, NewModule: newModule, Files: []FileChange{}}
+	if oldModule == newModule {
+		return rename, nil
+	}
+	rename.Files = append(rename.Files, FileChange{Path: "go.mod", Imports: goModChanges})
+
+	mapping := map[string]string{oldModule: newModule}
+	goChanges, err := RewriteImports(projectPath, mapping)
+	if err != nil {
+		return nil, err
+	}
+	rename.Files = append(rename.Files, goChanges...)
+
+	specChanges, err := rewriteSpecImports(projectPath, prefixRewriter(mapping))
+	if err != nil {
+		return nil, err
+	}
+	rename.Files = append(rename.Files, specChanges...)
+
+	if goWork != "" {
+		workChanges, err := rewriteWorkReplaces(goWork, prefixRewriter(mapping))
+		if err != nil {
+			return nil, mcperrors.Wrap(mcperrors.ErrImportFix, mcperrors.NewPathError(goWork, "rename", "failed to update go.work", err))
+		}
+		if len(workChanges) > 0 {
+			rel, _ := filepath.Rel(projectPath, goWork)
+			rename.Files = append(rename.Files, FileChange{Path: filepath.ToSlash(rel), Imports: workChanges})
+		}
+	}
+
+	return rename, nil
+}
+
+// rewriteSpecImports rewrites the import paths of .api files and the
+// go_package options of .proto files below projectPath
+func rewriteSpecImports(projectPath string, rewrite func(string) (string, bool)) ([]FileChange, error) {
+	changes := []FileChange{}
+	err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipDir(projectPath, path, d) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if !specExtensions[ext] {
+			return nil
+		}
+
+		pattern := goPackageOption
+		if ext == ".api" {
+			pattern = apiImportLine
+		}
+		imports, err := rewriteLines(path, func(line string, block string) (string, string, string, bool) {
+			if ext == ".api" && block != "import" && !singleDirective.MatchString(line) {
+				return "", "", "", false
+			}
+			m := pattern.FindStringSubmatch(line)
+			if m == nil {
+				return "", "", "", false
+			}
+			newPath, ok := rewrite(m[2])
+			if !ok {
+				return "", "", "", false
+			}
+			return m[1] + newPath + m[3], m[2], newPath, true
+		})
+		if err != nil {
+			return mcperrors.NewPathError(path, "rename", "failed to rewrite module references", err)
+		}
+		if len(imports) > 0 {
+			rel, _ := filepath.Rel(projectPath, path)
+			changes = append(changes, FileChange{Path: filepath.ToSlash(rel), Imports: imports})
+		}
+		return nil
+	})
+	if err != nil {
+		var pathErr *mcperrors.PathError
+		if !errors.As(err, &pathErr) {
+			err = mcperrors.NewPathError(projectPath, "walk", "failed to walk project directory", err)
+		}
+		return nil, mcperrors.Wrap(mcperrors.ErrImportFix, err)
+	}
+	return changes, nil
+}
+
+// rewriteWorkReplaces maps the module paths on both sides of the replace
+// directives in a go.work file
+func rewriteWorkReplaces(goWork string, rewrite func(string) (string, bool)) ([]ImportChange, error) {
+	return rewriteLines(goWork, func(line string, block string) (string, string, string, bool) {
+		if block != "replace" && !strings.HasPrefix(strings.TrimSpace(line), "replace ") {
+			return "", "", "", false
+		}
+		m := workReplaceLine.FindStringSubmatch(line)
+		if m == nil {
+			return "", "", "", false
+		}
+		module, moduleOK := rewrite(m[2])
+		target, targetOK := rewrite(m[4])
+		switch {
+		case moduleOK && targetOK:
+			return m[1] + module + m[3] + target + m[5], m[2], module, true
+		case moduleOK:
+			return m[1] + module + m[3] + m[4] + m[5], m[2], module, true
+		case targetOK:
+			return m[1] + m[2] + m[3] + target + m[5], m[4], target, true
+		}
+		return "", "", "", false
+	})
+}
+
+// rewriteLines applies fn to each line of a file, telling it which
+// parenthesized block ("import", "replace" or "") the line is in. fn
+// returns the new line and the old and new path it changed; the file is
+// written only if a line changed
+func rewriteLines(path string, fn func(line, block string) (string, string, string, bool)) ([]ImportChange, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out     bytes.Buffer
+		changes []ImportChange
+		block   string
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		switch {
+		case block == "" && blockStart.MatchString(line):
+			block = blockStart.FindStringSubmatch(line)[1]
+		case block != "" && blockEnd.MatchString(line):
+			block = ""
+		default:
+			if newLine, oldPath, newPath, ok := fn(line, block); ok && newLine != line {
+				line = newLine
+				changes = append(changes, ImportChange{Line: lineNo, Old: oldPath, New: newPath})
+			}
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	rewritten := out.Bytes()
+	if !bytes.HasSuffix(content, []byte("\n")) {
+		rewritten = bytes.TrimSuffix(rewritten, []byte("\n"))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, rewritten, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,134 @@
+package fixer_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jinguoxing/mcp-gozero/internal/fixer"
+)
+
+func TestRenameModule(t *testing.T) {
+	workDir := t.TempDir()
+	projectDir := filepath.Join(workDir, "user")
+	files := map[string]string{
+		"go.mod": "module github.com/example/user\n\ngo 1.21\n",
+		"user.go": `package main
+
+import "github.com/example/user/internal/config"
+
+// Module github.com/example/user
+func main() { _ = config.Config{} }
+`,
+		"internal/config/config.go": "package config\n\ntype Config struct{}\n",
+		"user.api": `syntax = "v1"
+
+import "github.com/example/user/api/types.api"
+import (
+	"github.com/example/user/api/admin.api"
+	"shared/common.api"
+)
+
+info (
+	module: "github.com/example/user/api"
+)
+`,
+		"pb/user.proto": `syntax = "proto3";
+
+package user;
+option go_package = "github.com/example/user/pb;user";
+`,
+		"vendor/github.com/example/user/lib.go": "package lib\n\nimport _ \"github.com/example/user/internal\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	goWork := filepath.Join(workDir, "go.work")
+	work := `go 1.21
+
+use ./user
+
+replace github.com/example/user/tools v1.0.0 => ./tools
+
+replace (
+	github.com/other/lib => github.com/example/user/lib v0.1.0
+	github.com/other/keep => ./keep
+)
+`
+	if err := os.WriteFile(goWork, []byte(work), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if found := fixer.FindGoWork(projectDir); found != goWork {
+		t.Fatalf("FindGoWork() = %q, want %q", found, goWork)
+	}
+
+	rename, err := fixer.RenameModule(projectDir, "github.com/acme/user", goWork)
+	if err != nil {
+		t.Fatalf("RenameModule() failed: %v", err)
+	}
+	if rename.OldModule != "github.com/example/user" {
+		t.Errorf("OldModule = %q", rename.OldModule)
+	}
+
+	changed := make(map[string]int)
+	for _, file := range rename.Files {
+		changed[file.Path] = len(file.Imports)
+	}
+	want := map[string]int{"go.mod": 1, "user.go": 1, "user.api": 2, "pb/user.proto": 1, "../go.work": 2}
+	if len(changed) != len(want) {
+		t.Errorf("changed files = %v, want %v", changed, want)
+	}
+	for path, count := range want {
+		if changed[path] != count {
+			t.Errorf("%s: %d changes, want %d", path, changed[path], count)
+		}
+	}
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	for name, expected := range map[string][]string{
+		"go.mod":                                {"module github.com/acme/user\n"},
+		"user.go":                               {`"github.com/acme/user/internal/config"`, "// Module github.com/example/user"},
+		"user.api":                              {`import "github.com/acme/user/api/types.api"`, `"github.com/acme/user/api/admin.api"`, `"shared/common.api"`, `module: "github.com/example/user/api"`},
+		"pb/user.proto":                         {`option go_package = "github.com/acme/user/pb;user";`},
+		"../go.work":                            {"replace github.com/acme/user/tools v1.0.0 => ./tools", "github.com/other/lib => github.com/acme/user/lib v0.1.0", "github.com/other/keep => ./keep"},
+		"vendor/github.com/example/user/lib.go": {`"github.com/example/user/internal"`},
+	} {
+		content := read(name)
+		for _, s := range expected {
+			if !strings.Contains(content, s) {
+				t.Errorf("%s should contain %q:\n%s", name, s, content)
+			}
+		}
+	}
+}
+
+func TestRenameModuleUnchanged(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module github.com/acme/user\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rename, err := fixer.RenameModule(projectDir, "github.com/acme/user", "")
+	if err != nil {
+		t.Fatalf("RenameModule() failed: %v", err)
+	}
+	if len(rename.Files) != 0 {
+		t.Errorf("renaming to the same path should change nothing, got %+v", rename.Files)
+	}
+
+	if _, err := fixer.RenameModule(t.TempDir(), "github.com/acme/user", ""); err == nil {
+		t.Error("RenameModule() should fail without go.mod")
+	}
+}
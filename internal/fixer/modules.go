@@ -1,14 +1,23 @@
 package fixer
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+	"github.com/jinguoxing/mcp-gozero/internal/progress"
 )
 
 // InitializeGoModule initializes a Go module in the project directory
-func InitializeGoModule(projectPath string, moduleName string) error {
+func InitializeGoModule(ctx context.Context, projectPath string, moduleName string) error {
+	return InitializeGoModuleWithOutput(ctx, projectPath, moduleName, nil)
+}
+
+// InitializeGoModuleWithOutput initializes a Go module, passing go command output lines to onLine
+func InitializeGoModuleWithOutput(ctx context.Context, projectPath string, moduleName string, onLine progress.LineFunc) error {
 	// Check if go.mod already exists
 	goModPath := filepath.Join(projectPath, "go.mod")
 	if _, err := os.Stat(goModPath); err == nil {
@@ -17,47 +26,77 @@ func InitializeGoModule(projectPath string, moduleName string) error {
 	}
 
 	// Run go mod init
-	cmd := exec.Command("go", "mod", "init", moduleName)
-	cmd.Dir = projectPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("go mod init failed: %s\n%s", err, string(output))
+	if err := runGo(ctx, process.StepGoModInit, projectPath, onLine, "mod", "init", moduleName); err != nil {
+		return mcperrors.Wrap(mcperrors.ErrModuleInit, fmt.Errorf("go mod init failed: %w", err))
 	}
 
 	// Run go mod tidy to resolve dependencies
-	return TidyGoModule(projectPath)
+	return TidyGoModuleWithOutput(ctx, projectPath, onLine)
 }
 
 // TidyGoModule runs go mod tidy to resolve dependencies
-func TidyGoModule(projectPath string) error {
-	cmd := exec.Command("go", "mod", "tidy")
-	cmd.Dir = projectPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("go mod tidy failed: %s\n%s", err, string(output))
+func TidyGoModule(ctx context.Context, projectPath string) error {
+	return TidyGoModuleWithOutput(ctx, projectPath, nil)
+}
+
+// TidyGoModuleWithOutput runs go mod tidy, passing output lines to onLine
+func TidyGoModuleWithOutput(ctx context.Context, projectPath string, onLine progress.LineFunc) error {
+	if err := runGo(ctx, process.StepGoModTidy, projectPath, onLine, "mod", "tidy"); err != nil {
+		return mcperrors.Wrap(mcperrors.ErrModuleInit, fmt.Errorf("go mod tidy failed: %w", err))
 	}
 
 	return nil
 }
 
 // VerifyBuild verifies the project builds successfully
-func VerifyBuild(projectPath string) error {
-	cmd := exec.Command("go", "build", "-o", "/dev/null", ".")
-	cmd.Dir = projectPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("build failed: %s\n%s", err, string(output))
+func VerifyBuild(ctx context.Context, projectPath string) error {
+	return VerifyBuildWithOutput(ctx, projectPath, nil)
+}
+
+// VerifyBuildWithOutput verifies the project builds, passing compiler output lines to onLine
+func VerifyBuildWithOutput(ctx context.Context, projectPath string, onLine progress.LineFunc) error {
+	if err := runGo(ctx, process.StepGoBuild, projectPath, onLine, "build", "-o", os.DevNull, "."); err != nil {
+		return mcperrors.Wrap(mcperrors.ErrBuild, fmt.Errorf("build failed: %w", err))
 	}
 
 	return nil
 }
 
+// VerifyBuildAllWithOutput verifies every package of the module builds,
+// passing compiler output lines to onLine
+func VerifyBuildAllWithOutput(ctx context.Context, projectPath string, onLine progress.LineFunc) error {
+	if err := runGo(ctx, process.StepGoBuild, projectPath, onLine, "build", "./..."); err != nil {
+		return mcperrors.Wrap(mcperrors.ErrBuild, fmt.Errorf("build failed: %w", err))
+	}
+
+	return nil
+}
+
+// runGo runs a go command in dir, bounded by ctx and the step timeout; a
+// failure is returned as an ExecutionError carrying the combined output
+func runGo(ctx context.Context, step process.Step, dir string, onLine progress.LineFunc, args ...string) error {
+	output := progress.NewLineWriter(onLine)
+	err := process.Run(ctx, process.Command{
+		Step:   step,
+		Name:   "go",
+		Args:   args,
+		Dir:    dir,
+		Stdout: output,
+		Stderr: output,
+	})
+	output.Flush()
+	if err != nil {
+		return mcperrors.NewExecutionError("go", args, "", output.String(), process.ExitCode(err), err)
+	}
+	return nil
+}
+
 // GetGoModuleName extracts module name from go.mod file
 func GetGoModuleName(projectPath string) (string, error) {
 	goModPath := filepath.Join(projectPath, "go.mod")
 	content, err := os.ReadFile(goModPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read go.mod: %w", err)
+		return "", mcperrors.NewPathError(goModPath, "read", "failed to read go.mod", err)
 	}
 
 	// Parse module name from first line: "module modulename"
@@ -70,5 +109,5 @@ func GetGoModuleName(projectPath string) (string, error) {
 		return lines[7:endIdx], nil
 	}
 
-	return "", fmt.Errorf("could not find module name in go.mod")
+	return "", mcperrors.Wrap(mcperrors.ErrProjectStructure, fmt.Errorf("could not find module name in go.mod"))
 }
@@ -4,100 +4,120 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
-// StyleConflictPair represents a pair of files that conflict due to different naming styles
-type StyleConflictPair struct {
-	GoZeroStyle string // go_zero style: snake_case (e.g., service_context.go)
-	GoZeroFlat  string // gozero style: flat (e.g., servicecontext.go)
+// styleConflict is one generated file present under several style names in
+// the same directory, e.g. service_context.go and servicecontext.go
+type styleConflict struct {
+	dir   string
+	files []generatedFile
 }
 
-// knownStyleConflicts lists all known file pairs that can conflict between go_zero and gozero styles
-var knownStyleConflicts = []StyleConflictPair{
-	{GoZeroStyle: "service_context.go", GoZeroFlat: "servicecontext.go"},
-	// Add more known conflicts here if discovered
+// findStyleConflicts groups the generated files of every kind in
+// generatedFileKinds by directory and style-independent name, returning the
+// groups with more than one file
+func findStyleConflicts(projectPath string) ([]styleConflict, error) {
+	groups := make(map[string]*styleConflict)
+	var keys []string
+	err := walkGeneratedFiles(projectPath, func(file generatedFile) error {
+		dir := filepath.Dir(file.path)
+		key := dir + "\x00" + flatKey(fileStem(filepath.Base(file.path)))
+		group, ok := groups[key]
+		if !ok {
+			group = &styleConflict{dir: dir}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.files = append(group.files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+	var conflicts []styleConflict
+	for _, key := range keys {
+		if group := groups[key]; len(group.files) > 1 {
+			conflicts = append(conflicts, *group)
+		}
+	}
+	return conflicts, nil
+}
+
+// names returns the file names of a conflict
+func (c styleConflict) names() []string {
+	names := make([]string, len(c.files))
+	for i, file := range c.files {
+		names[i] = filepath.Base(file.path)
+	}
+	return names
+}
+
+// words returns the words of the conflicting files' identifier, preferring
+// a name that keeps word boundaries over a gozero one
+func (c styleConflict) words() []string {
+	best := c.files[0].words
+	for _, file := range c.files[1:] {
+		if len(file.words) > len(best) {
+			best = file.words
+		}
+	}
+	return best
 }
 
 // CleanupStyleConflicts removes conflicting files based on the chosen style
-// This prevents duplicate type declarations when switching between go_zero and gozero styles
+// This prevents duplicate type declarations when switching between goctl
+// styles: of a file generated under several style names, only the one
+// named in style is kept
 func CleanupStyleConflicts(projectPath string, style string) error {
-	// Determine which style's files to keep
-	keepGoZeroStyle := (style == "go_zero")
-
-	// Walk through project directories looking for conflicts
-	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			// Ignore "no such file" errors - we might have deleted the file during cleanup
-			if os.IsNotExist(err) {
-				return nil
-			}
-			return err
-		}
+	conflicts, err := findStyleConflicts(projectPath)
+	if err != nil {
+		return err
+	}
 
-		// Skip if not a directory
-		if !info.IsDir() {
-			return nil
+	for _, conflict := range conflicts {
+		keep := generatedFile{words: conflict.words(), test: conflict.files[0].test}.name(style)
+		if !fileExists(filepath.Join(conflict.dir, keep)) {
+			// None of the files has the chosen style; leave them for ValidateNoStyleConflicts
+			continue
 		}
-
-		// Check each known conflict pair
-		for _, conflict := range knownStyleConflicts {
-			goZeroFile := filepath.Join(path, conflict.GoZeroStyle)
-			gozeroFile := filepath.Join(path, conflict.GoZeroFlat)
-
-			// Check if both files exist (conflict!)
-			goZeroExists := fileExists(goZeroFile)
-			gozeroExists := fileExists(gozeroFile)
-
-			if goZeroExists && gozeroExists {
-				// Both exist - remove the one we don't want
-				var fileToRemove string
-				if keepGoZeroStyle {
-					fileToRemove = gozeroFile
-				} else {
-					fileToRemove = goZeroFile
-				}
-
-				// Double-check file exists before removing
-				if fileExists(fileToRemove) {
-					if err := os.Remove(fileToRemove); err != nil {
-						return fmt.Errorf("failed to remove conflicting file %s: %w", fileToRemove, err)
-					}
-				}
+		for _, file := range conflict.files {
+			if filepath.Base(file.path) == keep {
+				continue
+			}
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return mcperrors.Wrap(mcperrors.ErrStyleConflict, mcperrors.NewPathError(file.path, "remove", "failed to remove conflicting file", err))
 			}
 		}
+	}
 
-		return nil
-	})
-
-	return err
+	return nil
 }
 
 // DetectExistingStyle detects which naming style is currently used in the project
-// Returns "go_zero" or "gozero", or empty string if cannot determine
+// Returns "go_zero", "gozero" or "goZero" for the style most generated files
+// with multi-word names use, or empty string if cannot determine
 func DetectExistingStyle(projectPath string) string {
-	for _, conflict := range knownStyleConflicts {
-		// Check in common locations
-		commonDirs := []string{
-			filepath.Join(projectPath, "internal", "svc"),
-			filepath.Join(projectPath, "internal", "handler"),
-			filepath.Join(projectPath, "internal", "logic"),
+	counts := make(map[string]int)
+	walkGeneratedFiles(projectPath, func(file generatedFile) error {
+		if style := file.style(); style != "" {
+			counts[style]++
 		}
+		return nil
+	})
 
-		for _, dir := range commonDirs {
-			goZeroFile := filepath.Join(dir, conflict.GoZeroStyle)
-			gozeroFile := filepath.Join(dir, conflict.GoZeroFlat)
-
-			if fileExists(goZeroFile) {
-				return "go_zero"
-			}
-			if fileExists(gozeroFile) {
-				return "gozero"
-			}
+	detected := ""
+	for _, style := range Styles {
+		if counts[style] > counts[detected] {
+			detected = style
 		}
 	}
-
-	return ""
+	return detected
 }
 
 // fileExists checks if a file exists
@@ -121,37 +141,21 @@ func SuggestStyleBasedOnExisting(projectPath string, defaultStyle string) string
 // ValidateNoStyleConflicts checks if there are any style conflicts in the project
 // Returns an error if conflicts are found
 func ValidateNoStyleConflicts(projectPath string) error {
-	var conflicts []string
-
-	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if !info.IsDir() {
-			return nil
-		}
-
-		for _, conflict := range knownStyleConflicts {
-			goZeroFile := filepath.Join(path, conflict.GoZeroStyle)
-			gozeroFile := filepath.Join(path, conflict.GoZeroFlat)
-
-			if fileExists(goZeroFile) && fileExists(gozeroFile) {
-				relPath, _ := filepath.Rel(projectPath, path)
-				conflicts = append(conflicts, fmt.Sprintf("%s: both %s and %s exist",
-					relPath, conflict.GoZeroStyle, conflict.GoZeroFlat))
-			}
-		}
-
-		return nil
-	})
-
+	found, err := findStyleConflicts(projectPath)
 	if err != nil {
 		return err
 	}
 
+	var conflicts []string
+	for _, conflict := range found {
+		relPath, _ := filepath.Rel(projectPath, conflict.dir)
+		names := conflict.names()
+		conflicts = append(conflicts, fmt.Sprintf("%s: both %s and %s exist",
+			relPath, strings.Join(names[:len(names)-1], ", "), names[len(names)-1]))
+	}
+
 	if len(conflicts) > 0 {
-		return fmt.Errorf("style conflicts detected:\n%s", strings.Join(conflicts, "\n"))
+		return mcperrors.Wrap(mcperrors.ErrStyleConflict, fmt.Errorf("style conflicts detected:\n%s", strings.Join(conflicts, "\n")))
 	}
 
 	return nil
@@ -1,10 +1,13 @@
 package fixer_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 	"github.com/jinguoxing/mcp-gozero/internal/fixer"
 )
 
@@ -184,3 +187,64 @@ func TestValidateNoStyleConflicts(t *testing.T) {
 		os.Remove(gozeroFile)
 	})
 }
+
+func TestValidateNoStyleConflictsAllKinds(t *testing.T) {
+	tests := []struct {
+		name  string
+		dir   string
+		files []string
+	}{
+		{"handler go_zero and goZero", "internal/handler/user", []string{"get_user_handler.go", "getUserHandler.go"}},
+		{"logic gozero and go_zero", "internal/logic", []string{"getuserlogic.go", "get_user_logic.go"}},
+		{"svc in three styles", "internal/svc", []string{"service_context.go", "servicecontext.go", "serviceContext.go"}},
+		{"rpc server", "internal/server", []string{"user_center_server.go", "usercenterserver.go"}},
+		{"rpc client", "usercenterclient", []string{"user_center.go", "userCenter.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			dir := filepath.Join(tmpDir, filepath.FromSlash(tt.dir))
+			os.MkdirAll(dir, 0755)
+			os.MkdirAll(filepath.Join(tmpDir, "internal"), 0755)
+			for _, name := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("package x\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := fixer.ValidateNoStyleConflicts(tmpDir)
+			if !errors.Is(err, mcperrors.ErrStyleConflict) {
+				t.Fatalf("expected a style conflict, got %v", err)
+			}
+			for _, name := range tt.files {
+				if !strings.Contains(err.Error(), name) {
+					t.Errorf("conflict should name %s: %v", name, err)
+				}
+			}
+		})
+	}
+
+	t.Run("single word names and other files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		handlerDir := filepath.Join(tmpDir, "internal", "handler")
+		os.MkdirAll(handlerDir, 0755)
+		for _, name := range []string{"routes.go", "get_user_handler.go", "get_user_handler_test.go", "middleware.go"} {
+			os.WriteFile(filepath.Join(handlerDir, name), []byte("package handler\n"), 0644)
+		}
+		if err := fixer.ValidateNoStyleConflicts(tmpDir); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
+}
+
+func TestDetectExistingStyleCamel(t *testing.T) {
+	tmpDir := t.TempDir()
+	logicDir := filepath.Join(tmpDir, "internal", "logic")
+	os.MkdirAll(logicDir, 0755)
+	for _, name := range []string{"getUserLogic.go", "listUsersLogic.go", "get_order_logic.go"} {
+		os.WriteFile(filepath.Join(logicDir, name), []byte("package logic\n"), 0644)
+	}
+	if style := fixer.DetectExistingStyle(tmpDir); style != "goZero" {
+		t.Errorf("Expected 'goZero', got '%s'", style)
+	}
+}
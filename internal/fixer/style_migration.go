@@ -0,0 +1,182 @@
+package fixer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+// FileRename is a generated file renamed to another style
+type FileRename struct {
+	Kind string `json:"kind"`
+	Old  string `json:"old"` // slash-separated, relative to the project
+	New  string `json:"new"`
+}
+
+// StyleMigration is the outcome of MigrateStyle
+type StyleMigration struct {
+	From     string       `json:"from,omitempty"`
+	To       string       `json:"to"`
+	Renamed  []FileRename `json:"renamed"`
+	Settings []FileChange `json:"settings"` // style settings of mcp-gozero.yaml and goctl invocations
+}
+
+var (
+	styleSetting = regexp.MustCompile(`^(style:\s*["']?)([A-Za-z_]+)(["']?.*)$`)
+	styleFlag    = regexp.MustCompile(`(--?style[= ]+["']?)([A-Za-z_]+)`)
+)
+
+// MigrateStyle renames the generated files below projectPath (handlers,
+// routes, logic, types, svc, config and RPC server and client files) from
+// style from, or from any style if from is empty, to style to. It then
+// points the style settings at the new style: the style key of a
+// mcp-gozero.yaml in projectPath and the --style flag of goctl commands in
+// Makefiles, shell scripts, .api files and go:generate directives. Nothing
+// is renamed if a new name is already taken; mixed-style duplicates left
+// afterwards are reported as an ErrStyleConflict
+func MigrateStyle(projectPath, from, to string) (*StyleMigration, error) {
+	migration := &StyleMigration{From: from, To: to, Renamed: []FileRename{}, Settings: []FileChange{}}
+
+	type rename struct {
+		file    generatedFile
+		newPath string
+	}
+	var renames []rename
+	err := walkGeneratedFiles(projectPath, func(file generatedFile) error {
+		style := file.style()
+		if style == "" || style == to || (from != "" && style != from) {
+			return nil
+		}
+		renames = append(renames, rename{file: file, newPath: filepath.Join(filepath.Dir(file.path), file.name(to))})
+		return nil
+	})
+	if err != nil {
+		return nil, mcperrors.Wrap(mcperrors.ErrStyleConflict, mcperrors.NewPathError(projectPath, "walk", "failed to walk project directory", err))
+	}
+
+	// Check every new name before renaming anything
+	targets := make(map[string]string)
+	var taken []string
+	for _, r := range renames {
+		rel := relSlash(projectPath, r.newPath)
+		if other, ok := targets[r.newPath]; ok {
+			taken = append(taken, fmt.Sprintf("%s: both %s and %s would be renamed to it", rel, other, relSlash(projectPath, r.file.path)))
+			continue
+		}
+		targets[r.newPath] = relSlash(projectPath, r.file.path)
+		if info, err := os.Stat(r.newPath); err == nil {
+			if current, err := os.Stat(r.file.path); err != nil || !os.SameFile(info, current) {
+				taken = append(taken, fmt.Sprintf("%s already exists next to %s", rel, filepath.Base(r.file.path)))
+			}
+		}
+	}
+	if len(taken) > 0 {
+		return nil, mcperrors.Wrap(mcperrors.ErrStyleConflict, fmt.Errorf("cannot migrate to %s, new names are taken:\n%s", to, strings.Join(taken, "\n")))
+	}
+
+	for _, r := range renames {
+		if err := os.Rename(r.file.path, r.newPath); err != nil {
+			return nil, mcperrors.Wrap(mcperrors.ErrStyleConflict, mcperrors.NewPathError(r.file.path, "rename", "failed to rename generated file", err))
+		}
+		migration.Renamed = append(migration.Renamed, FileRename{
+			Kind: r.file.kind.Kind,
+			Old:  relSlash(projectPath, r.file.path),
+			New:  relSlash(projectPath, r.newPath),
+		})
+	}
+	sort.Slice(migration.Renamed, func(i, j int) bool { return migration.Renamed[i].Old < migration.Renamed[j].Old })
+
+	settings, err := rewriteStyleSettings(projectPath, to)
+	if err != nil {
+		return nil, err
+	}
+	migration.Settings = settings
+
+	if err := ValidateNoStyleConflicts(projectPath); err != nil {
+		return migration, err
+	}
+	return migration, nil
+}
+
+// rewriteStyleSettings sets the style key of projectPath/mcp-gozero.yaml
+// and the style flag of goctl commands below projectPath to style
+func rewriteStyleSettings(projectPath, style string) ([]FileChange, error) {
+	changes := []FileChange{}
+	rewrite := func(path string, fn func(line string) (string, string, bool)) error {
+		imports, err := rewriteLines(path, func(line, _ string) (string, string, string, bool) {
+			newLine, old, ok := fn(line)
+			return newLine, old, style, ok
+		})
+		if err != nil {
+			return mcperrors.Wrap(mcperrors.ErrConfigUpdate, mcperrors.NewPathError(path, "update", "failed to update style setting", err))
+		}
+		if len(imports) > 0 {
+			changes = append(changes, FileChange{Path: relSlash(projectPath, path), Imports: imports})
+		}
+		return nil
+	}
+
+	if serverConfig := filepath.Join(projectPath, "mcp-gozero.yaml"); fileExists(serverConfig) {
+		err := rewrite(serverConfig, func(line string) (string, string, bool) {
+			m := styleSetting.FindStringSubmatch(line)
+			if m == nil {
+				return "", "", false
+			}
+			return m[1] + style + m[3], m[2], true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipDir(projectPath, path, d) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		isGo := strings.HasSuffix(name, ".go")
+		if !isGo && name != "Makefile" && !strings.HasSuffix(name, ".mk") && !strings.HasSuffix(name, ".sh") && !strings.HasSuffix(name, ".api") {
+			return nil
+		}
+		return rewrite(path, func(line string) (string, string, bool) {
+			if !strings.Contains(line, "goctl") || (isGo && !strings.HasPrefix(strings.TrimSpace(line), "//go:generate")) {
+				return "", "", false
+			}
+			m := styleFlag.FindStringSubmatch(line)
+			if m == nil {
+				return "", "", false
+			}
+			return styleFlag.ReplaceAllString(line, "${1}"+style), m[2], true
+		})
+	})
+	if err != nil {
+		var pathErr *mcperrors.PathError
+		if !errors.As(err, &pathErr) {
+			err = mcperrors.Wrap(mcperrors.ErrConfigUpdate, mcperrors.NewPathError(projectPath, "walk", "failed to walk project directory", err))
+		}
+		return nil, err
+	}
+	return changes, nil
+}
+
+// relSlash returns path relative to root with forward slashes
+func relSlash(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,129 @@
+package fixer_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/fixer"
+)
+
+// writeProject writes files, keyed by slash-separated paths, below dir
+func writeProject(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFormatFileName(t *testing.T) {
+	words := []string{"Get", "User", "By", "ID", "Handler"}
+	for style, want := range map[string]string{
+		fixer.StyleSnake: "get_user_by_id_handler",
+		fixer.StyleFlat:  "getuserbyidhandler",
+		fixer.StyleCamel: "getUserByIdHandler",
+	} {
+		if got := fixer.FormatFileName(style, words); got != want {
+			t.Errorf("FormatFileName(%s) = %q, want %q", style, got, want)
+		}
+	}
+}
+
+func TestMigrateStyle(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeProject(t, tmpDir, map[string]string{
+		"internal/handler/routes.go":                       "package handler\n",
+		"internal/handler/user/getuserbyidhandler.go":      "package user\n\nfunc GetUserByIDHandler() {}\n",
+		"internal/handler/user/getuserbyidhandler_test.go": "package user\n",
+		"internal/logic/user/getuserbyidlogic.go":          "package user\n\ntype GetUserByIDLogic struct{}\n",
+		"internal/svc/servicecontext.go":                   "package svc\n\ntype ServiceContext struct{}\n",
+		"internal/types/types.go":                          "package types\n",
+		"internal/config/config.go":                        "package config\n",
+		"internal/server/usercenterserver.go":              "package server\n\ntype UserCenterServer struct{}\n",
+		"usercenterclient/usercenter.go":                   "package usercenterclient\n\ntype UserCenter interface{}\n",
+		"pb/usercenter.pb.go":                              "package pb\n",
+		"mcp-gozero.yaml":                                  "module_prefix: github.com/acme\nstyle: gozero # team default\n",
+		"Makefile":                                         "api:\n\tgoctl api go -api user.api -dir . --style gozero\n",
+		"gen.go":                                           "package main\n\n//go:generate goctl api go -api user.api -dir . -style=gozero\n\nconst style = \"goctl --style gozero\"\n",
+	})
+
+	if style := fixer.DetectExistingStyle(tmpDir); style != fixer.StyleFlat {
+		t.Fatalf("DetectExistingStyle() = %q, want gozero", style)
+	}
+
+	migration, err := fixer.MigrateStyle(tmpDir, fixer.StyleFlat, fixer.StyleSnake)
+	if err != nil {
+		t.Fatalf("MigrateStyle() failed: %v", err)
+	}
+
+	want := []fixer.FileRename{
+		{Kind: "handler", Old: "internal/handler/user/getuserbyidhandler.go", New: "internal/handler/user/get_user_by_id_handler.go"},
+		{Kind: "handler", Old: "internal/handler/user/getuserbyidhandler_test.go", New: "internal/handler/user/get_user_by_id_handler_test.go"},
+		{Kind: "logic", Old: "internal/logic/user/getuserbyidlogic.go", New: "internal/logic/user/get_user_by_id_logic.go"},
+		{Kind: "server", Old: "internal/server/usercenterserver.go", New: "internal/server/user_center_server.go"},
+		{Kind: "svc", Old: "internal/svc/servicecontext.go", New: "internal/svc/service_context.go"},
+		{Kind: "client", Old: "usercenterclient/usercenter.go", New: "usercenterclient/user_center.go"},
+	}
+	if !reflect.DeepEqual(migration.Renamed, want) {
+		t.Errorf("Renamed = %+v\nwant %+v", migration.Renamed, want)
+	}
+	for _, rename := range want {
+		if _, err := os.Stat(filepath.Join(tmpDir, filepath.FromSlash(rename.New))); err != nil {
+			t.Errorf("%s should exist: %v", rename.New, err)
+		}
+	}
+
+	settings := make(map[string]int)
+	for _, file := range migration.Settings {
+		settings[file.Path] = len(file.Imports)
+	}
+	if !reflect.DeepEqual(settings, map[string]int{"mcp-gozero.yaml": 1, "Makefile": 1, "gen.go": 1}) {
+		t.Errorf("Settings = %+v", migration.Settings)
+	}
+	config, _ := os.ReadFile(filepath.Join(tmpDir, "mcp-gozero.yaml"))
+	if !strings.Contains(string(config), "style: go_zero # team default") {
+		t.Errorf("mcp-gozero.yaml not updated:\n%s", config)
+	}
+	gen, _ := os.ReadFile(filepath.Join(tmpDir, "gen.go"))
+	if !strings.Contains(string(gen), "-style=go_zero") || !strings.Contains(string(gen), `"goctl --style gozero"`) {
+		t.Errorf("only the go:generate line should change:\n%s", gen)
+	}
+
+	// Back to goZero from whatever style the files have
+	migration, err = fixer.MigrateStyle(tmpDir, "", fixer.StyleCamel)
+	if err != nil {
+		t.Fatalf("MigrateStyle() failed: %v", err)
+	}
+	if len(migration.Renamed) != len(want) {
+		t.Errorf("expected %d renames, got %+v", len(want), migration.Renamed)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "internal", "handler", "user", "getUserByIdHandler.go")); err != nil {
+		t.Errorf("expected the goZero handler name: %v", err)
+	}
+}
+
+func TestMigrateStyleNameTaken(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeProject(t, tmpDir, map[string]string{
+		"internal/logic/getuserlogic.go":   "package logic\n\ntype GetUserLogic struct{}\n",
+		"internal/logic/get_user_logic.go": "package logic\n",
+	})
+
+	_, err := fixer.MigrateStyle(tmpDir, fixer.StyleFlat, fixer.StyleSnake)
+	if !errors.Is(err, mcperrors.ErrStyleConflict) {
+		t.Fatalf("expected ErrStyleConflict, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "internal", "logic", "getuserlogic.go")); err != nil {
+		t.Error("nothing should be renamed when a new name is taken")
+	}
+}
@@ -0,0 +1,295 @@
+package fixer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// goctl file naming styles
+const (
+	StyleSnake = "go_zero" // service_context.go
+	StyleFlat  = "gozero"  // servicecontext.go
+	StyleCamel = "goZero"  // serviceContext.go
+)
+
+// Styles lists the goctl naming styles
+var Styles = []string{StyleSnake, StyleFlat, StyleCamel}
+
+// ValidStyle reports whether style is a goctl naming style
+func ValidStyle(style string) bool {
+	for _, s := range Styles {
+		if s == style {
+			return true
+		}
+	}
+	return false
+}
+
+// GeneratedFileKind describes files goctl names after an identifier in the
+// chosen style
+type GeneratedFileKind struct {
+	Kind   string // handler, routes, logic, types, svc, config, server or client
+	Dir    string // directory below the service; handler, logic and server files may be in group subdirectories
+	Name   string // identifier a file with a fixed name is named after, e.g. ServiceContext
+	Suffix string // identifier suffix of files named after a handler, logic or server, e.g. Handler
+}
+
+// generatedFileKinds lists the files goctl names by style. The RPC client
+// lives in <service>client/<service>.go next to internal and is named after
+// the service
+var generatedFileKinds = []GeneratedFileKind{
+	{Kind: "routes", Dir: "internal/handler", Name: "Routes"},
+	{Kind: "handler", Dir: "internal/handler", Suffix: "Handler"},
+	{Kind: "logic", Dir: "internal/logic", Suffix: "Logic"},
+	{Kind: "types", Dir: "internal/types", Name: "Types"},
+	{Kind: "svc", Dir: "internal/svc", Name: "ServiceContext"},
+	{Kind: "config", Dir: "internal/config", Name: "Config"},
+	{Kind: "server", Dir: "internal/server", Suffix: "Server"},
+	{Kind: "client", Dir: "*client"},
+}
+
+// generatedFile is a file of a generated kind with the words of the
+// identifier it is named after
+type generatedFile struct {
+	path  string // absolute
+	kind  GeneratedFileKind
+	words []string
+	test  bool // a _test.go file named after the generated file
+}
+
+// name returns the file name in a style
+func (f generatedFile) name(style string) string {
+	name := FormatFileName(style, f.words)
+	if f.test {
+		name += "_test"
+	}
+	return name + ".go"
+}
+
+// style returns the style the file is named in, or "" for names of a single
+// word, which are the same in every style
+func (f generatedFile) style() string {
+	if len(f.words) < 2 {
+		return ""
+	}
+	stem := strings.TrimSuffix(fileStem(filepath.Base(f.path)), "_test")
+	switch {
+	case strings.Contains(stem, "_"):
+		return StyleSnake
+	case strings.ToLower(stem) != stem:
+		return StyleCamel
+	default:
+		return StyleFlat
+	}
+}
+
+// FormatFileName joins the words of an identifier the way goctl names files
+// in a style, e.g. service_context, servicecontext or serviceContext
+func FormatFileName(style string, words []string) string {
+	lower := make([]string, len(words))
+	for i, word := range words {
+		lower[i] = strings.ToLower(word)
+	}
+	switch style {
+	case StyleSnake:
+		return strings.Join(lower, "_")
+	case StyleCamel:
+		for i := 1; i < len(lower); i++ {
+			runes := []rune(lower[i])
+			runes[0] = unicode.ToUpper(runes[0])
+			lower[i] = string(runes)
+		}
+		return strings.Join(lower, "")
+	default:
+		return strings.Join(lower, "")
+	}
+}
+
+// splitWords splits an identifier or file stem into words at underscores,
+// dashes and case changes: GetUserByIDHandler, get_user_by_id_handler and
+// getUserByIdHandler all give get, user, by, id, handler
+func splitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+// flatKey is the style-independent key of a file stem: the names of one
+// file in every style share it
+func flatKey(stem string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(stem))
+}
+
+// fileStem returns a file name without .go
+func fileStem(name string) string {
+	return strings.TrimSuffix(name, ".go")
+}
+
+// classifyGeneratedFile returns the generated file at path below root, or
+// false if it is not of a generated kind
+func classifyGeneratedFile(root, filePath string) (generatedFile, bool) {
+	name := filepath.Base(filePath)
+	if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, ".pb.go") {
+		return generatedFile{}, false
+	}
+	stem := fileStem(name)
+	file := generatedFile{path: filePath}
+	if strings.HasSuffix(stem, "_test") {
+		stem = strings.TrimSuffix(stem, "_test")
+		file.test = true
+	}
+
+	rel, err := filepath.Rel(root, filepath.Dir(filePath))
+	if err != nil {
+		return generatedFile{}, false
+	}
+	dir := filepath.ToSlash(rel)
+	key := flatKey(stem)
+	for _, kind := range generatedFileKinds {
+		if !kind.matchesDir(root, dir) {
+			continue
+		}
+		switch {
+		case kind.Name != "" && key == strings.ToLower(kind.Name):
+			file.kind = kind
+			file.words = splitWords(kind.Name)
+			return file, true
+		case kind.Suffix != "" && strings.HasSuffix(key, strings.ToLower(kind.Suffix)):
+			file.kind = kind
+			file.words = identifierWords(filePath, stem, kind)
+			return file, true
+		case kind.Name == "" && kind.Suffix == "":
+			file.kind = kind
+			file.words = identifierWords(filePath, stem, kind)
+			return file, true
+		}
+	}
+	return generatedFile{}, false
+}
+
+// matchesDir reports whether dir, slash-separated and relative to root, is a
+// directory of the kind in root or in a service below it
+func (k GeneratedFileKind) matchesDir(root, dir string) bool {
+	if k.Dir == "*client" {
+		base := path.Base(dir)
+		if !strings.HasSuffix(base, "client") || base == "client" {
+			return false
+		}
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(path.Dir(dir)), "internal"))
+		return err == nil && info.IsDir()
+	}
+	padded := "/" + dir + "/"
+	if !strings.Contains(padded, "/"+k.Dir+"/") {
+		return false
+	}
+	// Only handler, logic and server files live in group subdirectories
+	return k.Suffix != "" || strings.HasSuffix(padded, "/"+k.Dir+"/")
+}
+
+// identifierWords returns the words of the identifier a generated file is
+// named after: the top-level type or function whose name matches the file
+// name in any style, e.g. GetUserHandler for getuserhandler.go. Without one
+// the file name itself is split, which loses the words of gozero names
+func identifierWords(filePath, stem string, kind GeneratedFileKind) []string {
+	key := flatKey(stem)
+	// A test file is named after the file declaring the identifier
+	candidates := []string{filePath}
+	if entries, err := os.ReadDir(filepath.Dir(filePath)); err == nil {
+		for _, entry := range entries {
+			sibling := filepath.Join(filepath.Dir(filePath), entry.Name())
+			if sibling != filePath && strings.HasSuffix(entry.Name(), ".go") && flatKey(fileStem(entry.Name())) == key {
+				candidates = append(candidates, sibling)
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		file, err := parser.ParseFile(token.NewFileSet(), candidate, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			for _, name := range declNames(decl) {
+				if strings.ToLower(name) == key {
+					return splitWords(name)
+				}
+			}
+		}
+	}
+
+	words := splitWords(stem)
+	if len(words) == 1 && kind.Suffix != "" && key != strings.ToLower(kind.Suffix) {
+		// A gozero name without a matching identifier: split off the suffix
+		return []string{strings.TrimSuffix(key, strings.ToLower(kind.Suffix)), strings.ToLower(kind.Suffix)}
+	}
+	return words
+}
+
+// declNames returns the names of the types and plain functions a top-level
+// declaration declares
+func declNames(decl ast.Decl) []string {
+	var names []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil {
+			names = append(names, d.Name.Name)
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				names = append(names, ts.Name.Name)
+			}
+		}
+	}
+	return names
+}
+
+// walkGeneratedFiles calls fn for each generated file below projectPath,
+// skipping vendor, testdata and hidden directories
+func walkGeneratedFiles(projectPath string, fn func(generatedFile) error) error {
+	return filepath.WalkDir(projectPath, func(filePath string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Ignore files removed during the walk
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if skipDir(projectPath, filePath, d) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if file, ok := classifyGeneratedFile(projectPath, filePath); ok {
+			return fn(file)
+		}
+		return nil
+	})
+}
@@ -5,8 +5,36 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+// Settings configures goctl discovery and the protoc used by goctl rpc
+type Settings struct {
+	Path        string   // goctl executable, checked right after GOCTL_PATH
+	SearchPaths []string // goctl executables or directories containing one, checked before the common locations
+	ProtocPath  string   // protoc executable; its directory is put first on goctl's PATH
+}
+
+var (
+	settingsMu sync.RWMutex
+	settings   Settings
 )
 
+// Configure replaces the discovery settings
+func Configure(s Settings) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	settings = s
+}
+
+func currentSettings() Settings {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
 // DiscoverGoctl finds the goctl executable using multiple fallback strategies
 // Returns the absolute path to goctl or an error if not found
 func DiscoverGoctl() (string, error) {
@@ -17,31 +45,74 @@ func DiscoverGoctl() (string, error) {
 		}
 	}
 
-	// Strategy 2: Search common installation locations
-	commonPaths := []string{
-		"/usr/local/bin/goctl",
-		filepath.Join(os.Getenv("HOME"), "go", "bin", "goctl"),
-		filepath.Join(os.Getenv("HOME"), "Develop", "go", "bin", "goctl"),
+	// Strategy 2: Configured path and search paths
+	configured := currentSettings()
+	if configured.Path != "" && isExecutable(configured.Path) {
+		return configured.Path, nil
 	}
-
-	// Add GOPATH/bin if GOPATH is set
-	if goPath := os.Getenv("GOPATH"); goPath != "" {
-		commonPaths = append(commonPaths, filepath.Join(goPath, "bin", "goctl"))
-	}
-
-	for _, path := range commonPaths {
+	for _, path := range configured.SearchPaths {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "goctl")
+		}
 		if isExecutable(path) {
 			return path, nil
 		}
 	}
 
-	// Strategy 3: Search in PATH
-	if path, err := exec.LookPath("goctl"); err == nil {
+	// Strategy 3 and 4: common installation locations, then PATH
+	if path := searchCommonPaths("goctl"); path != "" {
 		return path, nil
 	}
 
 	// Not found - return actionable error
-	return "", fmt.Errorf("goctl not found. Install with:\n  go install github.com/zeromicro/go-zero/tools/goctl@latest\nOr set GOCTL_PATH environment variable")
+	return "", mcperrors.Wrap(mcperrors.ErrGoctlNotFound, fmt.Errorf("goctl not found. Install with:\n  go install github.com/zeromicro/go-zero/tools/goctl@latest\nOr set GOCTL_PATH environment variable or goctl.path in mcp-gozero.yaml"))
+}
+
+// DiscoverBinary finds a toolchain executable such as go, protoc or
+// protoc-gen-go the way DiscoverGoctl finds goctl: the configured path
+// (goctl and protoc), the common installation locations, then PATH
+func DiscoverBinary(name string) (string, error) {
+	switch name {
+	case "goctl":
+		return DiscoverGoctl()
+	case "protoc":
+		if protocPath := currentSettings().ProtocPath; protocPath != "" && isExecutable(protocPath) {
+			return protocPath, nil
+		}
+	}
+	if path := searchCommonPaths(name); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("%s not found in /usr/local/bin, GOPATH/bin or PATH", name)
+}
+
+// searchCommonPaths looks for name in the usual installation directories
+// and GOPATH/bin, then in PATH; it returns "" when not found
+func searchCommonPaths(name string) string {
+	commonDirs := []string{
+		"/usr/local/bin",
+		filepath.Join(os.Getenv("HOME"), "go", "bin"),
+		filepath.Join(os.Getenv("HOME"), "Develop", "go", "bin"),
+	}
+
+	// Add GOPATH/bin if GOPATH is set
+	if goPath := os.Getenv("GOPATH"); goPath != "" {
+		for _, dir := range filepath.SplitList(goPath) {
+			commonDirs = append(commonDirs, filepath.Join(dir, "bin"))
+		}
+	}
+	commonDirs = append(commonDirs, "/usr/local/go/bin")
+
+	for _, dir := range commonDirs {
+		if path := filepath.Join(dir, name); isExecutable(path) {
+			return path
+		}
+	}
+
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	return ""
 }
 
 // isExecutable checks if a file exists and is executable
@@ -1,31 +1,65 @@
 package goctl
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
+	"os"
 	"path/filepath"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+	"github.com/jinguoxing/mcp-gozero/internal/progress"
+)
+
+// Environment variables selecting the record and replay modes of NewExecutor
+const (
+	EnvRecord = "MCP_GOZERO_GOCTL_RECORD" // directory to save every goctl run to
+	EnvReplay = "MCP_GOZERO_GOCTL_REPLAY" // directory of saved runs to replay instead of running goctl
 )
 
-// Executor handles safe execution of goctl commands
-type Executor struct {
+// Executor runs goctl commands. The tools use the binary executor; tests
+// substitute a FakeExecutor or replay recorded runs.
+type Executor interface {
+	// GetPath returns the goctl executable, or a description of the stand-in
+	GetPath() string
+	// Capabilities returns what this goctl supports
+	Capabilities(ctx context.Context) Capabilities
+	// Run executes goctl with args in dir (the current directory if empty),
+	// passing every stdout and stderr line to onLine if set
+	Run(ctx context.Context, dir string, onLine progress.LineFunc, args ...string) *ExecuteResult
+}
+
+// BinaryExecutor handles safe execution of the goctl executable
+type BinaryExecutor struct {
 	goctlPath string
 }
 
 // NewExecutor creates a new goctl executor
-// Discovers goctl path on initialization
-func NewExecutor() (*Executor, error) {
+// Discovers goctl path on initialization. With MCP_GOZERO_GOCTL_REPLAY set,
+// recorded runs are replayed instead; with MCP_GOZERO_GOCTL_RECORD set, the
+// runs of the discovered goctl are recorded.
+func NewExecutor() (Executor, error) {
+	if dir := os.Getenv(EnvReplay); dir != "" {
+		return LoadReplay(dir)
+	}
+
 	goctlPath, err := DiscoverGoctl()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Executor{
+	executor := &BinaryExecutor{
 		goctlPath: goctlPath,
-	}, nil
+	}
+	if dir := os.Getenv(EnvRecord); dir != "" {
+		return NewRecorder(executor, dir), nil
+	}
+	return executor, nil
 }
 
-// ExecuteResult contains the result of a goctl command execution
+// ExecuteResult contains the result of a goctl command execution; a failed
+// run's Error is an *errors.ExecutionError
 type ExecuteResult struct {
 	Stdout   string
 	Stderr   string
@@ -33,70 +67,63 @@ type ExecuteResult struct {
 	Error    error
 }
 
-// Execute runs a goctl command with the given arguments
-// Uses absolute paths and captures both stdout and stderr
-func (e *Executor) Execute(args ...string) *ExecuteResult {
-	cmd := exec.Command(e.goctlPath, args...)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	result := &ExecuteResult{
-		Stdout: stdout.String(),
-		Stderr: stderr.String(),
-	}
-
-	if err != nil {
-		result.Error = err
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitErr.ExitCode()
-		} else {
-			result.ExitCode = -1
-		}
-	}
-
-	return result
-}
-
-// ExecuteInDir runs a goctl command in a specific directory
-func (e *Executor) ExecuteInDir(dir string, args ...string) *ExecuteResult {
-	// Ensure directory is absolute
-	absDir, err := filepath.Abs(dir)
-	if err != nil {
-		return &ExecuteResult{
-			Error:    fmt.Errorf("failed to get absolute path: %w", err),
-			ExitCode: -1,
+// Run executes goctl, killing it when ctx is cancelled or the goctl step
+// timeout elapses. Uses absolute paths and captures both stdout and stderr.
+func (e *BinaryExecutor) Run(ctx context.Context, dir string, onLine progress.LineFunc, args ...string) *ExecuteResult {
+	if dir != "" {
+		// Ensure directory is absolute
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return &ExecuteResult{
+				Error:    fmt.Errorf("failed to get absolute path: %w", err),
+				ExitCode: -1,
+			}
 		}
+		dir = absDir
 	}
 
-	cmd := exec.Command(e.goctlPath, args...)
-	cmd.Dir = absDir
+	stdout := progress.NewLineWriter(onLine)
+	stderr := progress.NewLineWriter(onLine)
+
+	err := process.Run(ctx, process.Command{
+		Step:   process.StepGoctl,
+		Name:   e.goctlPath,
+		Args:   args,
+		Dir:    dir,
+		Env:    protocEnv(),
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	stdout.Flush()
+	stderr.Flush()
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err = cmd.Run()
 	result := &ExecuteResult{
 		Stdout: stdout.String(),
 		Stderr: stderr.String(),
 	}
-
 	if err != nil {
-		result.Error = err
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitErr.ExitCode()
-		} else {
-			result.ExitCode = -1
-		}
+		result.ExitCode = process.ExitCode(err)
+		result.Error = mcperrors.NewExecutionError(e.goctlPath, audit.RedactArgs(args), result.Stdout, result.Stderr, result.ExitCode, err)
 	}
 
 	return result
 }
 
 // GetPath returns the discovered goctl path
-func (e *Executor) GetPath() string {
+func (e *BinaryExecutor) GetPath() string {
 	return e.goctlPath
 }
+
+// Capabilities detects what the executor's goctl supports
+func (e *BinaryExecutor) Capabilities(ctx context.Context) Capabilities {
+	return DetectCapabilities(ctx, e.goctlPath)
+}
+
+// protocEnv puts the configured protoc first on PATH so goctl rpc picks it up
+func protocEnv() []string {
+	protocPath := currentSettings().ProtocPath
+	if protocPath == "" {
+		return nil
+	}
+	return []string{"PATH=" + filepath.Dir(protocPath) + string(os.PathListSeparator) + os.Getenv("PATH")}
+}
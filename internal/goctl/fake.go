@@ -0,0 +1,136 @@
+package goctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/progress"
+)
+
+// namePlaceholder is replaced by the service name in fixture paths and contents
+const namePlaceholder = "{{name}}"
+
+// Fixture is what FakeExecutor produces for one goctl command
+type Fixture struct {
+	Command  string            // leading arguments it answers, e.g. "api new"
+	Files    map[string]string // slash-separated paths below the run's root
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// LoadFixture reads the files below dir as the output of command
+func LoadFixture(command, dir string) (Fixture, error) {
+	fixture := Fixture{Command: command, Files: make(map[string]string)}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fixture.Files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		return Fixture{}, fmt.Errorf("failed to load fixture %s: %w", dir, err)
+	}
+	return fixture, nil
+}
+
+// FakeExecutor emits fixture trees instead of running goctl. A run uses the
+// first fixture whose Command prefixes its arguments, and writes the fixture
+// files below the run's root: its directory, else the value of the dir flag.
+// {{name}} in fixture paths and contents becomes the service name, taken
+// from the first positional argument after the command (without extension)
+// or the base name of the api flag.
+type FakeExecutor struct {
+	Version  Version // reported by Capabilities; the zero Version means undetected
+	Fixtures []Fixture
+
+	mu   sync.Mutex
+	runs [][]string
+}
+
+// NewFakeExecutor creates a fake goctl of the given version
+func NewFakeExecutor(version Version, fixtures ...Fixture) *FakeExecutor {
+	return &FakeExecutor{Version: version, Fixtures: fixtures}
+}
+
+// GetPath describes the fake
+func (f *FakeExecutor) GetPath() string {
+	return "fake goctl"
+}
+
+// Capabilities returns the capabilities of the fake's version
+func (f *FakeExecutor) Capabilities(ctx context.Context) Capabilities {
+	if f.Version == (Version{}) {
+		return Capabilities{Error: "fake goctl without version"}
+	}
+	return CapabilitiesFor(f.Version)
+}
+
+// Runs returns the arguments of every run so far
+func (f *FakeExecutor) Runs() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.runs...)
+}
+
+// Run writes the files of the matching fixture
+func (f *FakeExecutor) Run(ctx context.Context, dir string, onLine progress.LineFunc, args ...string) *ExecuteResult {
+	f.mu.Lock()
+	f.runs = append(f.runs, append([]string(nil), args...))
+	f.mu.Unlock()
+
+	command := strings.Join(args, " ")
+	for _, fixture := range f.Fixtures {
+		if command != fixture.Command && !strings.HasPrefix(command, fixture.Command+" ") {
+			continue
+		}
+		root, err := runRoot(dir, args)
+		if err != nil {
+			return &ExecuteResult{Error: err, ExitCode: -1}
+		}
+		name := fixtureName(args[len(strings.Fields(fixture.Command)):])
+		files := make(map[string]string, len(fixture.Files))
+		for path, content := range fixture.Files {
+			files[strings.ReplaceAll(path, namePlaceholder, name)] = strings.ReplaceAll(content, namePlaceholder, name)
+		}
+		return replayOutput(root, args, files, fixture.Stdout, fixture.Stderr, fixture.ExitCode, onLine)
+	}
+
+	message := fmt.Sprintf("fake goctl: no fixture for %q", command)
+	return &ExecuteResult{Stderr: message, ExitCode: 1, Error: mcperrors.NewExecutionError("goctl", args, "", message, 1, errors.New(message))}
+}
+
+// fixtureName finds the service name in the arguments following the command
+func fixtureName(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return strings.TrimSuffix(filepath.Base(arg), filepath.Ext(arg))
+		}
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if name == "api" && i+1 < len(args) {
+			return strings.TrimSuffix(filepath.Base(args[i+1]), filepath.Ext(args[i+1]))
+		}
+		i++ // skip the flag's value
+	}
+	return ""
+}
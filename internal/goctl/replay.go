@@ -0,0 +1,285 @@
+package goctl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/progress"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// rootArg stands for the run's root in recorded arguments
+const rootArg = "$ROOT"
+
+// Recording is a goctl run saved by a Recorder: the normalized arguments,
+// the output and the files written below the run's root
+type Recording struct {
+	Version  string            `json:"version,omitempty"`
+	Args     []string          `json:"args"`
+	Stdout   string            `json:"stdout,omitempty"`
+	Stderr   string            `json:"stderr,omitempty"`
+	ExitCode int               `json:"exit_code,omitempty"`
+	Files    map[string]string `json:"files"`          // slash-separated paths relative to the root
+	Dirs     []string          `json:"dirs,omitempty"` // directories created, including empty ones
+}
+
+// Recorder runs goctl through another executor and saves every run as a
+// JSON file in a directory, e.g. testdata, for Replay
+type Recorder struct {
+	executor Executor
+	dir      string
+	mu       sync.Mutex
+}
+
+// NewRecorder records the runs of executor into dir
+func NewRecorder(executor Executor, dir string) *Recorder {
+	return &Recorder{executor: executor, dir: dir}
+}
+
+// GetPath returns the path of the recorded goctl
+func (r *Recorder) GetPath() string {
+	return r.executor.GetPath()
+}
+
+// Capabilities returns the capabilities of the recorded goctl
+func (r *Recorder) Capabilities(ctx context.Context) Capabilities {
+	return r.executor.Capabilities(ctx)
+}
+
+// Run runs goctl and saves the run with the files it created or modified
+func (r *Recorder) Run(ctx context.Context, dir string, onLine progress.LineFunc, args ...string) *ExecuteResult {
+	root, err := runRoot(dir, args)
+	if err != nil {
+		return &ExecuteResult{Error: err, ExitCode: -1}
+	}
+	tracker := snapshot.NewTracker(0)
+	tracker.Track(root)
+
+	result := r.executor.Run(ctx, dir, onLine, args...)
+
+	recording := Recording{
+		Args:     normalizeArgs(root, args),
+		Stdout:   result.Stdout,
+		Stderr:   result.Stderr,
+		ExitCode: result.ExitCode,
+		Files:    make(map[string]string),
+	}
+	if caps := r.executor.Capabilities(ctx); caps.Detected {
+		recording.Version = caps.Version.String()
+	}
+	for _, change := range tracker.Changes() {
+		rel, err := filepath.Rel(root, change.Path)
+		if err != nil || change.Action == snapshot.ActionDeleted {
+			continue
+		}
+		if change.Dir {
+			if change.Action == snapshot.ActionCreated {
+				recording.Dirs = append(recording.Dirs, filepath.ToSlash(rel))
+			}
+			continue
+		}
+		data, err := os.ReadFile(change.Path)
+		if err != nil {
+			continue
+		}
+		recording.Files[filepath.ToSlash(rel)] = string(data)
+	}
+
+	if err := r.save(recording); err != nil && result.Error == nil {
+		result.Error = err
+		result.ExitCode = -1
+	}
+	return result
+}
+
+var unsafeName = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+func (r *Recorder) save(recording Recording) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to record goctl run: %w", err)
+	}
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to record goctl run: %w", err)
+	}
+	// Named by command and arguments, so re-recording a run replaces it
+	command := recording.Args
+	if len(command) > 2 {
+		command = command[:2]
+	}
+	sum := sha256.Sum256([]byte(strings.Join(recording.Args, "\x00")))
+	name := fmt.Sprintf("%s-%x.json", unsafeName.ReplaceAllString(strings.Join(command, "_"), "_"), sum[:4])
+	if err := os.WriteFile(filepath.Join(r.dir, name), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to record goctl run: %w", err)
+	}
+	return nil
+}
+
+// Replay answers goctl runs from recordings instead of running goctl
+type Replay struct {
+	dir        string
+	recordings []Recording
+}
+
+// LoadReplay loads every recording saved in dir
+func LoadReplay(dir string) (*Replay, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no recorded goctl runs in %s (record them with %s=%s)", dir, EnvRecord, dir)
+	}
+	sort.Strings(paths)
+
+	replay := &Replay{dir: dir}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		var recording Recording
+		if err := json.Unmarshal(data, &recording); err != nil {
+			return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+		}
+		replay.recordings = append(replay.recordings, recording)
+	}
+	return replay, nil
+}
+
+// GetPath describes the replay
+func (r *Replay) GetPath() string {
+	return "replay of " + r.dir
+}
+
+// Capabilities returns the capabilities of the recorded goctl
+func (r *Replay) Capabilities(ctx context.Context) Capabilities {
+	for _, recording := range r.recordings {
+		if version, err := ParseVersion(recording.Version); err == nil {
+			return CapabilitiesFor(version)
+		}
+	}
+	return Capabilities{Error: "recordings carry no goctl version"}
+}
+
+// Run replays the recording with the same normalized arguments
+func (r *Replay) Run(ctx context.Context, dir string, onLine progress.LineFunc, args ...string) *ExecuteResult {
+	root, err := runRoot(dir, args)
+	if err != nil {
+		return &ExecuteResult{Error: err, ExitCode: -1}
+	}
+	key := strings.Join(normalizeArgs(root, args), " ")
+	for _, recording := range r.recordings {
+		if strings.Join(recording.Args, " ") == key {
+			for _, dir := range recording.Dirs {
+				target := filepath.Join(root, filepath.FromSlash(dir))
+				if !snapshot.Within(root, target) {
+					return &ExecuteResult{Error: fmt.Errorf("replayed directory %s is outside %s", dir, root), ExitCode: -1}
+				}
+				if err := os.MkdirAll(target, 0755); err != nil {
+					return &ExecuteResult{Error: err, ExitCode: -1}
+				}
+			}
+			return replayOutput(root, args, recording.Files, recording.Stdout, recording.Stderr, recording.ExitCode, onLine)
+		}
+	}
+	message := fmt.Sprintf("no recorded goctl run for %q in %s", key, r.dir)
+	return &ExecuteResult{Stderr: message, ExitCode: 1, Error: mcperrors.NewExecutionError("goctl", audit.RedactArgs(args), "", message, 1, errors.New(message))}
+}
+
+// runRoot is the directory a run writes to: its working directory, else the
+// value of the dir flag, else the current directory
+func runRoot(dir string, args []string) (string, error) {
+	if dir == "" {
+		for i, arg := range args {
+			if (arg == "-dir" || arg == "--dir") && i+1 < len(args) {
+				dir = args[i+1]
+				break
+			}
+		}
+	}
+	if dir == "" {
+		dir = "."
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return root, nil
+}
+
+// normalizeArgs makes arguments comparable across machines and temporary
+// directories: the root becomes $ROOT, other absolute paths their base name,
+// and secrets such as DSN passwords are redacted
+func normalizeArgs(root string, args []string) []string {
+	normalized := audit.RedactArgs(args)
+	for i, arg := range normalized {
+		switch {
+		case arg == root:
+			normalized[i] = rootArg
+		case strings.HasPrefix(arg, root+string(filepath.Separator)):
+			normalized[i] = rootArg + "/" + filepath.ToSlash(strings.TrimPrefix(arg, root+string(filepath.Separator)))
+		case filepath.IsAbs(arg):
+			normalized[i] = filepath.Base(arg)
+		}
+	}
+	return normalized
+}
+
+// replayOutput writes files below root and reports the output of a run
+// with args
+func replayOutput(root string, args []string, files map[string]string, stdout, stderr string, exitCode int, onLine progress.LineFunc) *ExecuteResult {
+	result := &ExecuteResult{Stdout: stdout, Stderr: stderr, ExitCode: exitCode}
+	if onLine != nil {
+		for _, output := range []string{stdout, stderr} {
+			for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+				if line != "" {
+					onLine(line)
+				}
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		target := filepath.Join(root, filepath.FromSlash(path))
+		if !snapshot.Within(root, target) {
+			result.Error = fmt.Errorf("replayed file %s is outside %s", path, root)
+			result.ExitCode = -1
+			return result
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			result.Error = err
+			result.ExitCode = -1
+			return result
+		}
+		if err := os.WriteFile(target, []byte(files[path]), 0644); err != nil {
+			result.Error = err
+			result.ExitCode = -1
+			return result
+		}
+	}
+
+	if exitCode != 0 {
+		result.Error = mcperrors.NewExecutionError("goctl", audit.RedactArgs(args), stdout, stderr, exitCode, fmt.Errorf("exit status %d", exitCode))
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package goctl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordedGoctl writes one handler below the --dir argument of goctl api go
+const recordedGoctl = `#!/bin/sh
+if [ "$1" = "--version" ]; then
+	echo "goctl version 1.6.3 linux/amd64"
+	exit 0
+fi
+mkdir -p "$6/internal/handler"
+printf 'package handler\n' > "$6/internal/handler/hello.go"
+echo "Done."
+`
+
+func TestRecordReplay(t *testing.T) {
+	goctlPath := filepath.Join(t.TempDir(), "goctl")
+	os.WriteFile(goctlPath, []byte(recordedGoctl), 0755)
+	recordings := t.TempDir()
+	ctx := context.Background()
+
+	// Record a run generating into one temporary directory...
+	first := t.TempDir()
+	os.WriteFile(filepath.Join(first, "existing.go"), []byte("package main\n"), 0644)
+	recorder := NewRecorder(&BinaryExecutor{goctlPath: goctlPath}, recordings)
+	caps := recorder.Capabilities(ctx)
+	args := caps.APIGoArgs(filepath.Join(t.TempDir(), "user.api"), first, "go_zero")
+	if result := recorder.Run(ctx, "", nil, args...); result.Error != nil {
+		t.Fatalf("recorded run failed: %v %s", result.Error, result.Stderr)
+	}
+	recorder.Run(ctx, "", nil, caps.ModelArgs("mysql", "root:s3cret@tcp(127.0.0.1:3306)/shop", "users", first, "go_zero")...)
+
+	// ...and replay it into another
+	replay, err := LoadReplay(recordings)
+	if err != nil {
+		t.Fatalf("LoadReplay failed: %v", err)
+	}
+	if got := replay.Capabilities(ctx); !got.Detected || got.Version != (Version{1, 6, 3}) {
+		t.Errorf("replay should report the recorded version, got %+v", got)
+	}
+	second := t.TempDir()
+	var lines []string
+	result := replay.Run(ctx, "", func(line string) { lines = append(lines, line) },
+		caps.APIGoArgs(filepath.Join(t.TempDir(), "user.api"), second, "go_zero")...)
+	if result.Error != nil {
+		t.Fatalf("replay failed: %v", result.Error)
+	}
+	if data, err := os.ReadFile(filepath.Join(second, "internal", "handler", "hello.go")); err != nil || string(data) != "package handler\n" {
+		t.Errorf("replay should write the recorded file, got %q %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(second, "existing.go")); !os.IsNotExist(err) {
+		t.Error("files that existed before the recorded run must not be replayed")
+	}
+	if !reflect.DeepEqual(lines, []string{"Done."}) {
+		t.Errorf("replay should stream the recorded output, got %v", lines)
+	}
+
+	if result := replay.Run(ctx, "", nil, caps.APIGoArgs("user.api", second, "gozero")...); result.Error == nil {
+		t.Error("runs with other arguments must not be replayed")
+	}
+
+	entries, _ := os.ReadDir(recordings)
+	for _, entry := range entries {
+		data, _ := os.ReadFile(filepath.Join(recordings, entry.Name()))
+		if strings.Contains(string(data), "s3cret") || strings.Contains(string(data), first) {
+			t.Errorf("recording %s should be redacted and machine independent:\n%s", entry.Name(), data)
+		}
+	}
+}
+
+func TestFakeExecutor(t *testing.T) {
+	fixtureDir := t.TempDir()
+	os.MkdirAll(filepath.Join(fixtureDir, "{{name}}", "etc"), 0755)
+	os.WriteFile(filepath.Join(fixtureDir, "{{name}}", "{{name}}.go"), []byte("package main // {{name}}\n"), 0644)
+	os.WriteFile(filepath.Join(fixtureDir, "{{name}}", "etc", "{{name}}.yaml"), []byte("Name: {{name}}\n"), 0644)
+	fixture, err := LoadFixture("rpc protoc", fixtureDir)
+	if err != nil {
+		t.Fatalf("LoadFixture failed: %v", err)
+	}
+
+	fake := NewFakeExecutor(Version{1, 3, 4}, fixture)
+	caps := fake.Capabilities(context.Background())
+	args, _ := caps.RPCArgs("order.proto", "go_zero", false)
+	dir := t.TempDir()
+	if result := fake.Run(context.Background(), dir, nil, args...); result.Error != nil {
+		t.Fatalf("fake run failed: %v", result.Error)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "order", "order.go")); string(data) != "package main // order\n" {
+		t.Errorf("unexpected fixture output %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "order", "etc", "order.yaml")); err != nil {
+		t.Errorf("fixture tree not written: %v", err)
+	}
+
+	if result := fake.Run(context.Background(), dir, nil, "api", "new", "user"); result.Error == nil || result.ExitCode != 1 {
+		t.Error("commands without a fixture should fail")
+	}
+	if runs := fake.Runs(); len(runs) != 2 || runs[0][6] != "-style" {
+		t.Errorf("runs should be recorded with the flags of goctl 1.3.4, got %v", runs)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 // Validator validates generated code and project structure
@@ -17,6 +19,10 @@ func NewValidator() *Validator {
 // ValidateServiceProject validates a generated service project
 // Checks for required files and structure
 func (v *Validator) ValidateServiceProject(projectPath string, serviceType string) error {
+	return mcperrors.Wrap(mcperrors.ErrProjectStructure, v.validateServiceProject(projectPath, serviceType))
+}
+
+func (v *Validator) validateServiceProject(projectPath string, serviceType string) error {
 	// Check project directory exists
 	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
 		return fmt.Errorf("project directory does not exist: %s", projectPath)
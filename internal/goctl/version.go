@@ -0,0 +1,229 @@
+package goctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+)
+
+// versionTimeout bounds goctl --version, independent of the goctl step timeout
+const versionTimeout = 10 * time.Second
+
+// Version is a goctl or go-zero release
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion extracts the first major.minor[.patch] from s, e.g. from
+// "goctl version 1.6.3 darwin/amd64", "go1.22" or a go.mod version like "v1.6.3"
+func ParseVersion(s string) (Version, error) {
+	matches := versionPattern.FindStringSubmatch(s)
+	if matches == nil {
+		return Version{}, fmt.Errorf("no version found in %q", strings.TrimSpace(s))
+	}
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+	patch, _ := strconv.Atoi(matches[3])
+	return Version{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is the same release as min or newer
+func (v Version) AtLeast(min Version) bool {
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	if v.Minor != min.Minor {
+		return v.Minor > min.Minor
+	}
+	return v.Patch >= min.Patch
+}
+
+// SameMinor reports whether v and other share major and minor version
+func (v Version) SameMinor(other Version) bool {
+	return v.Major == other.Major && v.Minor == other.Minor
+}
+
+// Capability names
+const (
+	CapLongFlags   = "long_flags"
+	CapAPINewStyle = "api_new_style"
+	CapRPCProtoc   = "rpc_protoc"
+	CapMultiple    = "rpc_multiple"
+)
+
+// Capability is a goctl feature and the first release that has it
+type Capability struct {
+	Name        string
+	Since       Version
+	Description string
+}
+
+// Matrix lists the goctl features the tools adapt their arguments to
+var Matrix = []Capability{
+	{CapAPINewStyle, Version{1, 2, 0}, "goctl api new accepts a style flag"},
+	{CapRPCProtoc, Version{1, 3, 0}, "goctl rpc protoc with --go_out, --go-grpc_out and --zrpc_out; older releases use goctl rpc proto -src"},
+	{CapLongFlags, Version{1, 4, 0}, "flags take two dashes (--style); older releases use -style"},
+	{CapMultiple, Version{1, 4, 0}, "goctl rpc protoc accepts --multiple for multiple services in one proto"},
+}
+
+// Capabilities is what a goctl binary supports
+type Capabilities struct {
+	Version  Version
+	Detected bool   // false when goctl --version failed; every capability is then assumed
+	Error    string // why detection failed
+	has      map[string]bool
+}
+
+// CapabilitiesFor returns the capabilities of a goctl release
+func CapabilitiesFor(v Version) Capabilities {
+	caps := Capabilities{Version: v, Detected: true, has: make(map[string]bool)}
+	for _, capability := range Matrix {
+		caps.has[capability.Name] = v.AtLeast(capability.Since)
+	}
+	return caps
+}
+
+// Has reports whether goctl supports a capability. With an undetected
+// version the current goctl is assumed, so everything is supported.
+func (c Capabilities) Has(name string) bool {
+	if !c.Detected {
+		return true
+	}
+	return c.has[name]
+}
+
+// Flag returns the flag spelling this goctl understands
+func (c Capabilities) Flag(name string) string {
+	if c.Has(CapLongFlags) {
+		return "--" + name
+	}
+	return "-" + name
+}
+
+// APINewArgs builds goctl api new; releases without a style flag use their default style
+func (c Capabilities) APINewArgs(name, style string) []string {
+	args := []string{"api", "new", name}
+	if c.Has(CapAPINewStyle) {
+		args = append(args, c.Flag("style"), style)
+	}
+	return args
+}
+
+// APIGoArgs builds goctl api go
+func (c Capabilities) APIGoArgs(apiFile, dir, style string) []string {
+	return []string{"api", "go", c.Flag("api"), apiFile, c.Flag("dir"), dir, c.Flag("style"), style}
+}
+
+// RPCArgs builds the goctl command generating an RPC service from protoFile
+// into the working directory. multiple needs CapMultiple.
+func (c Capabilities) RPCArgs(protoFile, style string, multiple bool) ([]string, error) {
+	if multiple && !c.Has(CapMultiple) {
+		return nil, fmt.Errorf("goctl %s does not support --multiple (requires %s or newer)", c.Version, since(CapMultiple))
+	}
+	if !c.Has(CapRPCProtoc) {
+		return []string{"rpc", "proto", c.Flag("src"), protoFile, c.Flag("dir"), ".", c.Flag("style"), style}, nil
+	}
+	args := []string{"rpc", "protoc", protoFile, "--go_out=.", "--go-grpc_out=.", "--zrpc_out=.", c.Flag("style"), style}
+	if multiple {
+		args = append(args, c.Flag("multiple"))
+	}
+	return args, nil
+}
+
+// ModelArgs builds goctl model <sourceType> datasource
+func (c Capabilities) ModelArgs(sourceType, url, table, dir, style string) []string {
+	return []string{"model", sourceType, "datasource", c.Flag("url"), url, c.Flag("table"), table, c.Flag("dir"), dir, c.Flag("style"), style}
+}
+
+func since(name string) Version {
+	for _, capability := range Matrix {
+		if capability.Name == name {
+			return capability.Since
+		}
+	}
+	return Version{}
+}
+
+// CompatibilityWarning compares goctl with the go-zero version a project
+// requires and describes a major/minor mismatch, or returns ""
+func (c Capabilities) CompatibilityWarning(goZeroVersion string) string {
+	if !c.Detected || goZeroVersion == "" {
+		return ""
+	}
+	goZero, err := ParseVersion(goZeroVersion)
+	if err != nil || c.Version.SameMinor(goZero) {
+		return ""
+	}
+	return fmt.Sprintf("goctl %s generates code for go-zero %d.%d but the project requires go-zero %s; install goctl %d.%d.x or update go-zero",
+		c.Version, c.Version.Major, c.Version.Minor, goZero, goZero.Major, goZero.Minor)
+}
+
+type versionEntry struct {
+	modTime time.Time
+	size    int64
+	caps    Capabilities
+}
+
+var (
+	versionMu    sync.Mutex
+	versionCache = map[string]versionEntry{}
+)
+
+// DetectCapabilities runs goctl --version and returns what the binary
+// supports. Results are cached until the binary changes.
+func DetectCapabilities(ctx context.Context, goctlPath string) Capabilities {
+	info, statErr := os.Stat(goctlPath)
+	if statErr == nil {
+		versionMu.Lock()
+		entry, ok := versionCache[goctlPath]
+		versionMu.Unlock()
+		if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+			return entry.caps
+		}
+	}
+
+	caps := detect(ctx, goctlPath)
+	if statErr == nil && ctx.Err() == nil {
+		versionMu.Lock()
+		versionCache[goctlPath] = versionEntry{modTime: info.ModTime(), size: info.Size(), caps: caps}
+		versionMu.Unlock()
+	}
+	return caps
+}
+
+func detect(ctx context.Context, goctlPath string) Capabilities {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+
+	var output strings.Builder
+	err := process.Run(ctx, process.Command{
+		Step:   process.StepGoctl,
+		Name:   goctlPath,
+		Args:   []string{"--version"},
+		Stdout: &output,
+		Stderr: &output,
+	})
+	if err != nil {
+		return Capabilities{Error: fmt.Sprintf("goctl --version failed: %v", err)}
+	}
+	version, err := ParseVersion(output.String())
+	if err != nil {
+		return Capabilities{Error: fmt.Sprintf("goctl --version: %v", err)}
+	}
+	return CapabilitiesFor(version)
+}
@@ -0,0 +1,107 @@
+package goctl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{"goctl version 1.6.3 darwin/amd64", Version{1, 6, 3}, false},
+		{"goctl version 1.7.0-beta linux/arm64\n", Version{1, 7, 0}, false},
+		{"v1.5.6", Version{1, 5, 6}, false},
+		{"1.6.4-0.20240101000000-abcdef123456", Version{1, 6, 4}, false},
+		{"unknown flag: --version", Version{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCapabilityArgs(t *testing.T) {
+	current := CapabilitiesFor(Version{1, 6, 3})
+	old := CapabilitiesFor(Version{1, 3, 2})
+	ancient := CapabilitiesFor(Version{1, 1, 10})
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"api new current", current.APINewArgs("user", "go_zero"), []string{"api", "new", "user", "--style", "go_zero"}},
+		{"api new old", old.APINewArgs("user", "go_zero"), []string{"api", "new", "user", "-style", "go_zero"}},
+		{"api new without style", ancient.APINewArgs("user", "go_zero"), []string{"api", "new", "user"}},
+		{"api go current", current.APIGoArgs("u.api", "/out", "gozero"), []string{"api", "go", "--api", "u.api", "--dir", "/out", "--style", "gozero"}},
+		{"api go old", old.APIGoArgs("u.api", "/out", "gozero"), []string{"api", "go", "-api", "u.api", "-dir", "/out", "-style", "gozero"}},
+		{"model old", old.ModelArgs("mysql", "dsn", "users", "/m", "go_zero"), []string{"model", "mysql", "datasource", "-url", "dsn", "-table", "users", "-dir", "/m", "-style", "go_zero"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	args, err := current.RPCArgs("user.proto", "go_zero", true)
+	if err != nil || !reflect.DeepEqual(args, []string{"rpc", "protoc", "user.proto", "--go_out=.", "--go-grpc_out=.", "--zrpc_out=.", "--style", "go_zero", "--multiple"}) {
+		t.Errorf("unexpected rpc args %v (%v)", args, err)
+	}
+	if _, err := old.RPCArgs("user.proto", "go_zero", true); err == nil {
+		t.Error("expected --multiple to be rejected before 1.4.0")
+	}
+	args, _ = ancient.RPCArgs("user.proto", "go_zero", false)
+	if !reflect.DeepEqual(args, []string{"rpc", "proto", "-src", "user.proto", "-dir", ".", "-style", "go_zero"}) {
+		t.Errorf("expected goctl rpc proto before 1.3.0, got %v", args)
+	}
+
+	// An unknown version assumes a current goctl
+	if !(Capabilities{}).Has(CapMultiple) || (Capabilities{}).Flag("style") != "--style" {
+		t.Error("undetected capabilities should assume a current goctl")
+	}
+}
+
+func TestCompatibilityWarning(t *testing.T) {
+	caps := CapabilitiesFor(Version{1, 5, 6})
+	if warning := caps.CompatibilityWarning("1.5.0"); warning != "" {
+		t.Errorf("same minor version should be compatible, got %q", warning)
+	}
+	if warning := caps.CompatibilityWarning("1.6.3"); !strings.Contains(warning, "go-zero 1.6.3") {
+		t.Errorf("expected a mismatch warning, got %q", warning)
+	}
+	if warning := (Capabilities{}).CompatibilityWarning("1.6.3"); warning != "" {
+		t.Errorf("an unknown goctl version can't be compared, got %q", warning)
+	}
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	dir := t.TempDir()
+	goctlPath := filepath.Join(dir, "goctl")
+	os.WriteFile(goctlPath, []byte("#!/bin/sh\necho 'goctl version 1.3.4 linux/amd64'\n"), 0755)
+
+	caps := DetectCapabilities(context.Background(), goctlPath)
+	if !caps.Detected || caps.Version != (Version{1, 3, 4}) {
+		t.Fatalf("unexpected capabilities: %+v", caps)
+	}
+	if caps.Has(CapLongFlags) || !caps.Has(CapRPCProtoc) {
+		t.Errorf("unexpected capabilities for 1.3.4: %+v", caps)
+	}
+
+	broken := filepath.Join(dir, "broken")
+	os.WriteFile(broken, []byte("#!/bin/sh\nexit 1\n"), 0755)
+	if caps := DetectCapabilities(context.Background(), broken); caps.Detected || caps.Error == "" {
+		t.Errorf("expected detection to fail, got %+v", caps)
+	}
+}
@@ -0,0 +1,299 @@
+// Package history keeps a bounded list of the file operations performed by
+// tool calls, with the prior state of every touched file, so an operation
+// can be undone
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// DefaultMaxOperations is the number of operations kept when not configured
+const DefaultMaxOperations = 20
+
+// ErrNotFound is returned for an unknown or evicted operation
+var ErrNotFound = errors.New("operation not found")
+
+// Operation is a tool call that changed files
+type Operation struct {
+	ID        string
+	Tool      string
+	Session   string
+	Time      time.Time
+	Arguments map[string]any
+	Changes   []snapshot.Change
+	UndoneAt  time.Time
+}
+
+// Undone reports whether the operation has been undone
+func (o *Operation) Undone() bool {
+	return !o.UndoneAt.IsZero()
+}
+
+// Restorable reports whether every change can be reverted, i.e. the prior
+// content of all modified and deleted files was captured and no tracked
+// tree was truncated
+func (o *Operation) Restorable() bool {
+	if o.Truncated() {
+		return false
+	}
+	for _, change := range o.Changes {
+		if change.Action != snapshot.ActionCreated && change.Before == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Truncated reports whether a tree the operation wrote exceeded
+// snapshot.MaxFiles, so its recorded changes are incomplete
+func (o *Operation) Truncated() bool {
+	for _, change := range o.Changes {
+		if change.Truncated {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of file changes with the given action
+func (o *Operation) Count(action string) int {
+	count := 0
+	for _, change := range o.Changes {
+		if !change.Dir && change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Store holds the most recent operations
+type Store struct {
+	mu         sync.Mutex
+	max        int
+	next       int
+	operations []*Operation
+}
+
+// NewStore creates a store keeping up to max operations
+func NewStore(max int) *Store {
+	if max <= 0 {
+		max = DefaultMaxOperations
+	}
+	return &Store{max: max}
+}
+
+// SetMax changes the number of operations kept, evicting the oldest
+func (s *Store) SetMax(max int) {
+	if max <= 0 {
+		max = DefaultMaxOperations
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.max = max
+	s.evict()
+}
+
+// Record adds an operation for a tool call; calls without changes are not recorded
+func (s *Store) Record(tool, session string, arguments map[string]any, changes []snapshot.Change) *Operation {
+	if s == nil || len(changes) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	op := &Operation{
+		ID:        fmt.Sprintf("op-%d", s.next),
+		Tool:      tool,
+		Session:   session,
+		Time:      time.Now(),
+		Arguments: arguments,
+		Changes:   changes,
+	}
+	s.operations = append(s.operations, op)
+	s.evict()
+	return op
+}
+
+// evict drops the oldest operations beyond max; must be called with s.mu locked
+func (s *Store) evict() {
+	if extra := len(s.operations) - s.max; extra > 0 {
+		s.operations = append([]*Operation(nil), s.operations[extra:]...)
+	}
+}
+
+// List returns the operations, most recent first
+func (s *Store) List() []*Operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*Operation, len(s.operations))
+	for i, op := range s.operations {
+		list[len(list)-1-i] = op
+	}
+	return list
+}
+
+// Get returns an operation by ID; an empty ID selects the most recent
+// operation that hasn't been undone
+func (s *Store) Get(id string) (*Operation, error) {
+	if id == "" {
+		return s.Latest(nil)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.operations) - 1; i >= 0; i-- {
+		if op := s.operations[i]; op.ID == id {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Latest returns the most recent operation that hasn't been undone and
+// satisfies match; a nil match accepts every operation
+func (s *Store) Latest(match func(*Operation) bool) (*Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.operations) - 1; i >= 0; i-- {
+		op := s.operations[i]
+		if !op.Undone() && (match == nil || match(op)) {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no operation to undo", ErrNotFound)
+}
+
+// Conflict is a file whose current state no longer matches the state the
+// operation left it in, or that can't be restored
+type Conflict struct {
+	Path   string
+	Reason string
+}
+
+// Conflicts checks that every file is still in the state the operation left
+// it in, i.e. nothing changed it since, and that its prior state is known
+func Conflicts(op *Operation) []Conflict {
+	var conflicts []Conflict
+	for _, change := range op.Changes {
+		info, err := os.Lstat(change.Path)
+		exists := err == nil
+
+		switch {
+		case change.Action != snapshot.ActionCreated && change.Before == nil:
+			conflicts = append(conflicts, Conflict{Path: change.Path, Reason: "prior content was not captured (snapshot size limit)"})
+		case change.Action == snapshot.ActionDeleted:
+			if exists {
+				conflicts = append(conflicts, Conflict{Path: change.Path, Reason: "recreated since the operation"})
+			}
+		case change.Dir:
+			if !exists || !info.IsDir() {
+				conflicts = append(conflicts, Conflict{Path: change.Path, Reason: "directory removed since the operation"})
+			}
+		case !exists:
+			conflicts = append(conflicts, Conflict{Path: change.Path, Reason: "deleted since the operation"})
+		default:
+			if hash, err := snapshot.HashFile(change.Path); err != nil || hash != change.AfterHash {
+				conflicts = append(conflicts, Conflict{Path: change.Path, Reason: "modified since the operation"})
+			}
+		}
+	}
+	return conflicts
+}
+
+// Result lists what Undo changed
+type Result struct {
+	Restored []string // modified or deleted files written back
+	Removed  []string // created files and directories removed
+	Skipped  []Conflict
+}
+
+// Undo reverts an operation. It refuses when Conflicts reports files changed
+// since the operation, unless force is set; with force, files whose prior
+// content is unknown are skipped and everything else is restored. Operations
+// with a truncated snapshot are never undone
+func (s *Store) Undo(op *Operation, force bool) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if op.Undone() {
+		return nil, fmt.Errorf("operation %s was already undone at %s", op.ID, op.UndoneAt.Format(time.RFC3339))
+	}
+	if op.Truncated() {
+		// Entries past the limit may show up as created or deleted; undoing
+		// them could remove files the operation never touched
+		return nil, fmt.Errorf("operation %s wrote a tree of more than %d files; its snapshot is partial and can't be undone", op.ID, snapshot.MaxFiles)
+	}
+	conflicts := Conflicts(op)
+	if len(conflicts) > 0 && !force {
+		return &Result{Skipped: conflicts}, fmt.Errorf("operation %s: %d file(s) changed outside the tool since the operation", op.ID, len(conflicts))
+	}
+
+	result := &Result{}
+	unknown := make(map[string]bool)
+	for _, conflict := range conflicts {
+		if change := findChange(op, conflict.Path); change != nil && change.Action != snapshot.ActionCreated && change.Before == nil {
+			unknown[conflict.Path] = true
+			result.Skipped = append(result.Skipped, conflict)
+		}
+	}
+
+	// Directories first so deleted files can be written back into them
+	changes := append([]snapshot.Change(nil), op.Changes...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	for _, change := range changes {
+		if unknown[change.Path] || change.Action != snapshot.ActionDeleted || !change.Dir {
+			continue
+		}
+		if err := os.MkdirAll(change.Path, change.Before.Mode.Perm()); err != nil {
+			return result, fmt.Errorf("failed to restore directory %s: %w", change.Path, err)
+		}
+		result.Restored = append(result.Restored, change.Path)
+	}
+	for _, change := range changes {
+		if unknown[change.Path] || change.Dir || change.Action == snapshot.ActionCreated {
+			continue
+		}
+		if err := os.WriteFile(change.Path, change.Before.Data, change.Before.Mode.Perm()); err != nil {
+			return result, fmt.Errorf("failed to restore %s: %w", change.Path, err)
+		}
+		os.Chmod(change.Path, change.Before.Mode.Perm())
+		result.Restored = append(result.Restored, change.Path)
+	}
+
+	// Created entries deepest first, so directories are empty when removed
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.Action != snapshot.ActionCreated {
+			continue
+		}
+		if change.Dir {
+			// Keep directories that now hold files the operation didn't create
+			if err := os.Remove(change.Path); err != nil && !os.IsNotExist(err) {
+				result.Skipped = append(result.Skipped, Conflict{Path: change.Path, Reason: "directory not empty"})
+				continue
+			}
+		} else if err := os.Remove(change.Path); err != nil && !os.IsNotExist(err) {
+			return result, fmt.Errorf("failed to remove %s: %w", change.Path, err)
+		}
+		result.Removed = append(result.Removed, change.Path)
+	}
+
+	op.UndoneAt = time.Now()
+	return result, nil
+}
+
+func findChange(op *Operation, path string) *snapshot.Change {
+	for i := range op.Changes {
+		if op.Changes[i].Path == path {
+			return &op.Changes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package history_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinguoxing/mcp-gozero/internal/history"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// runOperation tracks dir while change runs and records the result
+func runOperation(t *testing.T, store *history.Store, dir string, budget int64, change func()) *history.Operation {
+	t.Helper()
+	tracker := snapshot.NewTracker(budget)
+	tracker.Track(dir)
+	change()
+	op := store.Record("generate_api_from_spec", "", nil, tracker.Changes())
+	if op == nil {
+		t.Fatal("expected an operation to be recorded")
+	}
+	return op
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestUndoRestoresPriorState(t *testing.T) {
+	store := history.NewStore(5)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "old.go"), []byte("package old\n"), 0600)
+
+	op := runOperation(t, store, dir, snapshot.DefaultContentBudget, func() {
+		os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+		os.Remove(filepath.Join(dir, "old.go"))
+		os.MkdirAll(filepath.Join(dir, "internal", "handler"), 0755)
+		os.WriteFile(filepath.Join(dir, "internal", "handler", "h.go"), []byte("package handler\n"), 0644)
+	})
+	if op.Count(snapshot.ActionCreated) != 1 || op.Count(snapshot.ActionModified) != 1 || op.Count(snapshot.ActionDeleted) != 1 {
+		t.Errorf("unexpected changes: %+v", op.Changes)
+	}
+
+	result, err := store.Undo(op, false)
+	if err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if readFile(t, filepath.Join(dir, "main.go")) != "package main\n" {
+		t.Error("modified file not restored")
+	}
+	if info, err := os.Stat(filepath.Join(dir, "old.go")); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("deleted file not restored with its mode: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "internal")); !os.IsNotExist(err) {
+		t.Error("created directories should be removed")
+	}
+	if len(result.Restored) != 2 || len(result.Removed) != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if _, err := store.Undo(op, false); err == nil {
+		t.Error("an operation can only be undone once")
+	}
+	if _, err := store.Get(""); !errors.Is(err, history.ErrNotFound) {
+		t.Errorf("no operation should be left to undo, got %v", err)
+	}
+}
+
+func TestUndoRefusesOutsideChanges(t *testing.T) {
+	store := history.NewStore(5)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "user.api")
+
+	op := runOperation(t, store, dir, snapshot.DefaultContentBudget, func() {
+		os.WriteFile(path, []byte("syntax = \"v1\"\n"), 0644)
+	})
+
+	// Edited by hand after the operation
+	os.WriteFile(path, []byte("syntax = \"v1\"\n// edited\n"), 0644)
+	result, err := store.Undo(op, false)
+	if err == nil {
+		t.Fatal("expected undo to refuse")
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Path != path {
+		t.Errorf("expected the edited file as conflict, got %+v", result.Skipped)
+	}
+	if readFile(t, path) != "syntax = \"v1\"\n// edited\n" {
+		t.Error("a refused undo must not touch files")
+	}
+
+	if _, err := store.Undo(op, true); err != nil {
+		t.Fatalf("forced undo failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("forced undo should remove the created file")
+	}
+}
+
+func TestUndoWithoutCapturedContent(t *testing.T) {
+	store := history.NewStore(5)
+	dir := t.TempDir()
+	big := filepath.Join(dir, "big.go")
+	os.WriteFile(big, []byte("package big\n"), 0644)
+
+	// A zero budget keeps no prior content
+	op := runOperation(t, store, dir, 0, func() {
+		os.WriteFile(big, []byte("package big\n\nvar x = 1\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "new.go"), []byte("package big\n"), 0644)
+	})
+	if op.Restorable() {
+		t.Error("operation without prior content should not be restorable")
+	}
+	if _, err := store.Undo(op, false); err == nil {
+		t.Fatal("expected undo to refuse")
+	}
+
+	result, err := store.Undo(op, true)
+	if err != nil {
+		t.Fatalf("forced undo failed: %v", err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Path != big {
+		t.Errorf("expected the uncaptured file to be skipped, got %+v", result.Skipped)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.go")); !os.IsNotExist(err) {
+		t.Error("created file should still be removed")
+	}
+}
+
+func TestUndoRefusesTruncatedSnapshot(t *testing.T) {
+	store := history.NewStore(5)
+	dir := t.TempDir()
+	for i := 0; i < snapshot.MaxFiles; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%05d.txt", i)), nil, 0644)
+	}
+
+	tracker := snapshot.NewTracker(snapshot.DefaultContentBudget)
+	tracker.Track(dir)
+	if truncated := tracker.Truncated(); len(truncated) != 1 || truncated[0] != dir {
+		t.Fatalf("expected %s to be truncated, got %v", dir, truncated)
+	}
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("new\n"), 0644)
+	op := store.Record("create_api_service", "", nil, tracker.Changes())
+	if op == nil {
+		t.Fatal("expected an operation to be recorded")
+	}
+	if !op.Truncated() || op.Restorable() {
+		t.Error("operation with a truncated snapshot should not be restorable")
+	}
+	if _, err := store.Undo(op, true); err == nil {
+		t.Fatal("expected undo to refuse a truncated snapshot even with force")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Error("refused undo should leave files alone")
+	}
+}
+
+func TestStoreBounded(t *testing.T) {
+	store := history.NewStore(2)
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		runOperation(t, store, dir, 0, func() {
+			os.WriteFile(filepath.Join(dir, "f.go"), []byte{byte('a' + i)}, 0644)
+		})
+	}
+	list := store.List()
+	if len(list) != 2 || list[0].ID != "op-3" || list[1].ID != "op-2" {
+		t.Errorf("expected the two most recent operations, got %v", list)
+	}
+	if _, err := store.Get("op-1"); !errors.Is(err, history.ErrNotFound) {
+		t.Errorf("evicted operation should not be found, got %v", err)
+	}
+
+	if store.Record("query_docs", "", nil, nil) != nil {
+		t.Error("calls without changes should not be recorded")
+	}
+}
@@ -3,9 +3,15 @@ package logging
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Fields are key/value pairs appended to a log entry as key=value
+type Fields map[string]any
+
 // Logger provides structured logging for MCP tools
 type Logger struct {
 	enabled bool
@@ -55,3 +61,47 @@ func (l *Logger) Warning(tool string, message string, args ...interface{}) {
 	msg := fmt.Sprintf(message, args...)
 	log.Printf("[WARN] %s [%s] %s", timestamp, tool, msg)
 }
+
+// InfoFields logs an informational message with structured fields
+func (l *Logger) InfoFields(tool string, message string, fields Fields) {
+	if !l.enabled {
+		return
+	}
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	log.Printf("[INFO] %s [%s] %s%s", timestamp, tool, message, formatFields(fields))
+}
+
+// ErrorFields logs an error message with structured fields
+func (l *Logger) ErrorFields(tool string, message string, fields Fields) {
+	if !l.enabled {
+		return
+	}
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	log.Printf("[ERROR] %s [%s] %s%s", timestamp, tool, message, formatFields(fields))
+}
+
+// formatFields renders fields sorted by key, quoting values that contain spaces
+func formatFields(fields Fields) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		value := fmt.Sprint(fields[key])
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(" ")
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(value)
+	}
+	return b.String()
+}
@@ -2,32 +2,148 @@ package metrics
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+// DefaultBuckets are the upper bounds of the duration histogram. goctl and
+// go builds take seconds to minutes while analysis tools finish in milliseconds.
+var DefaultBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+}
+
+// Histogram is a fixed-bucket duration histogram with constant memory
+type Histogram struct {
+	bounds []time.Duration
+	counts []int // counts[i] observations <= bounds[i]; last entry is +Inf
+	count  int
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewHistogram creates a histogram with the given ascending bucket bounds
+func NewHistogram(bounds []time.Duration) *Histogram {
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]int, len(bounds)+1),
+	}
+}
+
+// Observe records a duration
+func (h *Histogram) Observe(d time.Duration) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return d <= h.bounds[i] })
+	h.counts[i]++
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+}
+
+// Quantile estimates the q-th quantile (0..1) by interpolating within the
+// bucket that contains it, clamped to the observed min and max
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	rank := q * float64(h.count)
+	cumulative := 0
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		if float64(cumulative+c) >= rank {
+			lower := time.Duration(0)
+			if i > 0 {
+				lower = h.bounds[i-1]
+			}
+			upper := h.max
+			if i < len(h.bounds) && h.bounds[i] < upper {
+				upper = h.bounds[i]
+			}
+			if lower < h.min {
+				lower = h.min
+			}
+			if upper < lower {
+				upper = lower
+			}
+			fraction := (rank - float64(cumulative)) / float64(c)
+			return lower + time.Duration(fraction*float64(upper-lower))
+		}
+		cumulative += c
+	}
+	return h.max
+}
+
+// ToolStats is a point-in-time summary of a tool's calls
+type ToolStats struct {
+	Tool        string
+	Calls       int
+	Errors      int
+	SuccessRate float64
+	ErrorsByCat map[string]int
+	Avg         time.Duration
+	Min         time.Duration
+	Max         time.Duration
+	P50         time.Duration
+	P90         time.Duration
+	P99         time.Duration
+}
+
 // Metrics tracks performance metrics for MCP tools
 type Metrics struct {
-	mu            sync.RWMutex
-	toolCalls     map[string]int
-	toolDurations map[string][]time.Duration
-	toolErrors    map[string]int
-	enabled       bool
+	mu              sync.RWMutex
+	toolCalls       map[string]int
+	toolDurations   map[string]*Histogram
+	toolErrors      map[string]int
+	toolErrorsByCat map[string]map[string]int
+	enabled         bool
 }
 
 // NewMetrics creates a new metrics instance
 func NewMetrics(enabled bool) *Metrics {
 	return &Metrics{
-		toolCalls:     make(map[string]int),
-		toolDurations: make(map[string][]time.Duration),
-		toolErrors:    make(map[string]int),
-		enabled:       enabled,
+		toolCalls:       make(map[string]int),
+		toolDurations:   make(map[string]*Histogram),
+		toolErrors:      make(map[string]int),
+		toolErrorsByCat: make(map[string]map[string]int),
+		enabled:         enabled,
 	}
 }
 
 // RecordToolCall records a tool invocation
 func (m *Metrics) RecordToolCall(toolName string, duration time.Duration, success bool) {
+	category := ""
+	if !success {
+		category = "unknown"
+	}
+	m.RecordToolResult(toolName, duration, category)
+}
+
+// RecordToolResult records a tool invocation; an empty errorCategory means success
+func (m *Metrics) RecordToolResult(toolName string, duration time.Duration, errorCategory string) {
 	if !m.enabled {
 		return
 	}
@@ -36,64 +152,132 @@ func (m *Metrics) RecordToolCall(toolName string, duration time.Duration, succes
 	defer m.mu.Unlock()
 
 	m.toolCalls[toolName]++
-	m.toolDurations[toolName] = append(m.toolDurations[toolName], duration)
+	hist, ok := m.toolDurations[toolName]
+	if !ok {
+		hist = NewHistogram(DefaultBuckets)
+		m.toolDurations[toolName] = hist
+	}
+	hist.Observe(duration)
 
-	if !success {
+	if errorCategory != "" {
 		m.toolErrors[toolName]++
+		if m.toolErrorsByCat[toolName] == nil {
+			m.toolErrorsByCat[toolName] = make(map[string]int)
+		}
+		m.toolErrorsByCat[toolName][errorCategory]++
 	}
 }
 
+// Snapshot returns per-tool statistics sorted by tool name
+func (m *Metrics) Snapshot() []ToolStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make([]ToolStats, 0, len(m.toolCalls))
+	for tool, count := range m.toolCalls {
+		hist := m.toolDurations[tool]
+		if hist == nil || hist.count == 0 {
+			continue
+		}
+
+		errors := m.toolErrors[tool]
+		byCat := make(map[string]int, len(m.toolErrorsByCat[tool]))
+		for cat, n := range m.toolErrorsByCat[tool] {
+			byCat[cat] = n
+		}
+
+		stats = append(stats, ToolStats{
+			Tool:        tool,
+			Calls:       count,
+			Errors:      errors,
+			SuccessRate: float64(count-errors) / float64(count) * 100,
+			ErrorsByCat: byCat,
+			Avg:         hist.sum / time.Duration(hist.count),
+			Min:         hist.min,
+			Max:         hist.max,
+			P50:         hist.Quantile(0.50),
+			P90:         hist.Quantile(0.90),
+			P99:         hist.Quantile(0.99),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Tool < stats[j].Tool })
+	return stats
+}
+
 // GetStats returns formatted statistics
 func (m *Metrics) GetStats() string {
 	if !m.enabled {
 		return "Metrics disabled"
 	}
 
+	var result string
+	result += "=== MCP Tool Metrics ===\n"
+
+	for _, s := range m.Snapshot() {
+		result += fmt.Sprintf("\nTool: %s\n", s.Tool)
+		result += fmt.Sprintf("  Calls: %d\n", s.Calls)
+		result += fmt.Sprintf("  Errors: %d\n", s.Errors)
+		if len(s.ErrorsByCat) > 0 {
+			result += fmt.Sprintf("  Errors by Category: %s\n", formatCategories(s.ErrorsByCat))
+		}
+		result += fmt.Sprintf("  Success Rate: %.1f%%\n", s.SuccessRate)
+		result += fmt.Sprintf("  Avg Duration: %v\n", s.Avg)
+		result += fmt.Sprintf("  Min Duration: %v\n", s.Min)
+		result += fmt.Sprintf("  Max Duration: %v\n", s.Max)
+		result += fmt.Sprintf("  P50/P90/P99: %v / %v / %v\n", s.P50, s.P90, s.P99)
+	}
+
+	return result
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var result string
-	result += "=== MCP Tool Metrics ===\n"
+	tools := make([]string, 0, len(m.toolCalls))
+	for tool := range m.toolCalls {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
 
-	for tool, count := range m.toolCalls {
-		durations := m.toolDurations[tool]
-		errors := m.toolErrors[tool]
+	fmt.Fprintln(w, "# HELP mcp_gozero_tool_calls_total Total number of tool calls.")
+	fmt.Fprintln(w, "# TYPE mcp_gozero_tool_calls_total counter")
+	for _, tool := range tools {
+		fmt.Fprintf(w, "mcp_gozero_tool_calls_total{tool=%q} %d\n", tool, m.toolCalls[tool])
+	}
 
-		if len(durations) == 0 {
-			continue
+	fmt.Fprintln(w, "# HELP mcp_gozero_tool_errors_total Total number of failed tool calls by error category.")
+	fmt.Fprintln(w, "# TYPE mcp_gozero_tool_errors_total counter")
+	for _, tool := range tools {
+		byCat := m.toolErrorsByCat[tool]
+		cats := make([]string, 0, len(byCat))
+		for cat := range byCat {
+			cats = append(cats, cat)
+		}
+		sort.Strings(cats)
+		for _, cat := range cats {
+			fmt.Fprintf(w, "mcp_gozero_tool_errors_total{tool=%q,category=%q} %d\n", tool, cat, byCat[cat])
 		}
+	}
 
-		// Calculate average duration
-		var total time.Duration
-		for _, d := range durations {
-			total += d
+	fmt.Fprintln(w, "# HELP mcp_gozero_tool_duration_seconds Tool call duration.")
+	fmt.Fprintln(w, "# TYPE mcp_gozero_tool_duration_seconds histogram")
+	for _, tool := range tools {
+		hist := m.toolDurations[tool]
+		if hist == nil {
+			continue
 		}
-		avg := total / time.Duration(len(durations))
-
-		// Calculate min/max
-		min := durations[0]
-		max := durations[0]
-		for _, d := range durations {
-			if d < min {
-				min = d
-			}
-			if d > max {
-				max = d
-			}
+		cumulative := 0
+		for i, bound := range hist.bounds {
+			cumulative += hist.counts[i]
+			fmt.Fprintf(w, "mcp_gozero_tool_duration_seconds_bucket{tool=%q,le=%q} %d\n", tool, formatSeconds(bound), cumulative)
 		}
-
-		successRate := float64(count-errors) / float64(count) * 100
-
-		result += fmt.Sprintf("\nTool: %s\n", tool)
-		result += fmt.Sprintf("  Calls: %d\n", count)
-		result += fmt.Sprintf("  Errors: %d\n", errors)
-		result += fmt.Sprintf("  Success Rate: %.1f%%\n", successRate)
-		result += fmt.Sprintf("  Avg Duration: %v\n", avg)
-		result += fmt.Sprintf("  Min Duration: %v\n", min)
-		result += fmt.Sprintf("  Max Duration: %v\n", max)
+		fmt.Fprintf(w, "mcp_gozero_tool_duration_seconds_bucket{tool=%q,le=\"+Inf\"} %d\n", tool, hist.count)
+		fmt.Fprintf(w, "mcp_gozero_tool_duration_seconds_sum{tool=%q} %s\n", tool, formatSeconds(hist.sum))
+		fmt.Fprintf(w, "mcp_gozero_tool_duration_seconds_count{tool=%q} %d\n", tool, hist.count)
 	}
-
-	return result
 }
 
 // LogStats logs the current statistics
@@ -110,6 +294,20 @@ func (m *Metrics) Reset() {
 	defer m.mu.Unlock()
 
 	m.toolCalls = make(map[string]int)
-	m.toolDurations = make(map[string][]time.Duration)
+	m.toolDurations = make(map[string]*Histogram)
 	m.toolErrors = make(map[string]int)
+	m.toolErrorsByCat = make(map[string]map[string]int)
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%g", d.Seconds())
+}
+
+func formatCategories(byCat map[string]int) string {
+	cats := make([]string, 0, len(byCat))
+	for cat, n := range byCat {
+		cats = append(cats, fmt.Sprintf("%s=%d", cat, n))
+	}
+	sort.Strings(cats)
+	return strings.Join(cats, ", ")
 }
@@ -0,0 +1,90 @@
+package metrics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/metrics"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	h := metrics.NewHistogram(metrics.DefaultBuckets)
+	if h.Quantile(0.5) != 0 {
+		t.Error("expected zero quantile for an empty histogram")
+	}
+
+	// 90 fast calls and 10 slow ones
+	for i := 0; i < 90; i++ {
+		h.Observe(3 * time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		h.Observe(20 * time.Second)
+	}
+
+	if p50 := h.Quantile(0.5); p50 > 5*time.Millisecond {
+		t.Errorf("p50 should fall in the fastest bucket, got %s", p50)
+	}
+	if p99 := h.Quantile(0.99); p99 < 10*time.Second || p99 > 20*time.Second {
+		t.Errorf("p99 should fall between 10s and the observed max, got %s", p99)
+	}
+	if max := h.Quantile(1); max != 20*time.Second {
+		t.Errorf("p100 should be the observed max, got %s", max)
+	}
+}
+
+func TestMetricsSnapshot(t *testing.T) {
+	m := metrics.NewMetrics(true)
+	m.RecordToolResult("analyze_project", 10*time.Millisecond, "")
+	m.RecordToolResult("analyze_project", 30*time.Millisecond, "validation")
+	m.RecordToolCall("query_docs", time.Millisecond, true)
+
+	stats := m.Snapshot()
+	if len(stats) != 2 || stats[0].Tool != "analyze_project" {
+		t.Fatalf("unexpected snapshot: %+v", stats)
+	}
+	s := stats[0]
+	if s.Calls != 2 || s.Errors != 1 || s.ErrorsByCat["validation"] != 1 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.SuccessRate != 50 || s.Avg != 20*time.Millisecond || s.Min != 10*time.Millisecond || s.Max != 30*time.Millisecond {
+		t.Errorf("unexpected durations: %+v", s)
+	}
+
+	m.Reset()
+	if len(m.Snapshot()) != 0 {
+		t.Error("expected no stats after reset")
+	}
+
+	disabled := metrics.NewMetrics(false)
+	disabled.RecordToolCall("query_docs", time.Millisecond, true)
+	if len(disabled.Snapshot()) != 0 || disabled.GetStats() != "Metrics disabled" {
+		t.Error("disabled metrics should not record calls")
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	m := metrics.NewMetrics(true)
+	m.RecordToolResult("generate_model", 2*time.Second, "")
+	m.RecordToolResult("generate_model", 200*time.Millisecond, "timeout")
+
+	var buf bytes.Buffer
+	m.WritePrometheus(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE mcp_gozero_tool_duration_seconds histogram",
+		`mcp_gozero_tool_calls_total{tool="generate_model"} 2`,
+		`mcp_gozero_tool_errors_total{tool="generate_model",category="timeout"} 1`,
+		`mcp_gozero_tool_duration_seconds_bucket{tool="generate_model",le="0.25"} 1`,
+		`mcp_gozero_tool_duration_seconds_bucket{tool="generate_model",le="2.5"} 2`,
+		`mcp_gozero_tool_duration_seconds_bucket{tool="generate_model",le="+Inf"} 2`,
+		`mcp_gozero_tool_duration_seconds_sum{tool="generate_model"} 2.2`,
+		`mcp_gozero_tool_duration_seconds_count{tool="generate_model"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
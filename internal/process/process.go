@@ -0,0 +1,151 @@
+// Package process runs goctl and go subprocesses bound to the caller's
+// context, with a timeout per step and process-group kill on cancellation
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+// Step identifies a kind of subprocess for timeout configuration
+type Step string
+
+// Known steps
+const (
+	StepGoctl     Step = "goctl"
+	StepGoModInit Step = "go_mod_init"
+	StepGoModTidy Step = "go_mod_tidy"
+	StepGoBuild   Step = "go_build"
+	StepToolCheck Step = "tool_check" // version and environment probes of the doctor
+)
+
+// waitDelay bounds how long Run waits for output pipes after the process is killed
+const waitDelay = 5 * time.Second
+
+// DefaultTimeouts are used when a step has no configured timeout
+var DefaultTimeouts = map[Step]time.Duration{
+	StepGoctl:     2 * time.Minute,
+	StepGoModInit: 30 * time.Second,
+	StepGoModTidy: 5 * time.Minute,
+	StepGoBuild:   5 * time.Minute,
+	StepToolCheck: 10 * time.Second,
+}
+
+var (
+	mu        sync.RWMutex
+	overrides = map[Step]time.Duration{}
+)
+
+// SetTimeout overrides the timeout of a step; zero restores the default
+func SetTimeout(step Step, timeout time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	if timeout <= 0 {
+		delete(overrides, step)
+		return
+	}
+	overrides[step] = timeout
+}
+
+// Timeout returns the timeout of a step. Explicit overrides win over the
+// MCP_GOZERO_TIMEOUT_<STEP> environment variable (e.g.
+// MCP_GOZERO_TIMEOUT_GO_MOD_TIDY=10m), which wins over the default.
+func Timeout(step Step) time.Duration {
+	mu.RLock()
+	timeout, ok := overrides[step]
+	mu.RUnlock()
+	if ok {
+		return timeout
+	}
+
+	if value := os.Getenv(EnvVar(step)); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+
+	if d, ok := DefaultTimeouts[step]; ok {
+		return d
+	}
+	return DefaultTimeouts[StepGoBuild]
+}
+
+// EnvVar returns the environment variable that configures a step timeout
+func EnvVar(step Step) string {
+	return "MCP_GOZERO_TIMEOUT_" + strings.ToUpper(string(step))
+}
+
+// Command describes a subprocess to run
+type Command struct {
+	Step   Step
+	Name   string
+	Args   []string
+	Dir    string
+	Env    []string // added to the server's environment
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Run executes the command until it exits, ctx is cancelled or the step
+// timeout elapses. The whole process group is killed on cancellation so
+// children such as the compiler spawned by go build do not linger.
+// A timeout is reported as *errors.TimeoutError; cancellation returns an
+// error wrapping ctx.Err(). The run is recorded by the audit recorder in ctx.
+func Run(ctx context.Context, c Command) error {
+	start := time.Now()
+	err := run(ctx, c)
+	audit.FromContext(ctx).Command(string(c.Step), c.Name, c.Args, c.Dir, ExitCode(err), time.Since(start), err)
+	return err
+}
+
+func run(ctx context.Context, c Command) error {
+	timeout := Timeout(c.Step)
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(stepCtx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s cancelled: %w", c.Step, ctx.Err())
+	}
+	if errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return mcperrors.NewTimeoutError(string(c.Step), c.Name, c.Args, timeout)
+	}
+	return err
+}
+
+// ExitCode returns the exit code of a failed Run, or -1 when the process
+// did not exit normally
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
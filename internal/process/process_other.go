@@ -0,0 +1,9 @@
+//go:build !unix
+
+package process
+
+import "os/exec"
+
+// setProcessGroup falls back to killing only the direct child, which is
+// the default behaviour of exec.CommandContext
+func setProcessGroup(cmd *exec.Cmd) {}
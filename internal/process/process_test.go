@@ -0,0 +1,137 @@
+package process_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+)
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+}
+
+func TestRunOutput(t *testing.T) {
+	requireShell(t)
+
+	var stdout bytes.Buffer
+	err := process.Run(context.Background(), process.Command{
+		Step:   process.StepGoctl,
+		Name:   "sh",
+		Args:   []string{"-c", "echo hello; exit 3"},
+		Stdout: &stdout,
+	})
+	if strings.TrimSpace(stdout.String()) != "hello" {
+		t.Errorf("unexpected stdout %q", stdout.String())
+	}
+	if code := process.ExitCode(err); code != 3 {
+		t.Errorf("expected exit code 3, got %d (%v)", code, err)
+	}
+	if mcperrors.IsTimeout(err) {
+		t.Error("a failing command must not be reported as a timeout")
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	requireShell(t)
+
+	process.SetTimeout(process.StepGoModTidy, 200*time.Millisecond)
+	defer process.SetTimeout(process.StepGoModTidy, 0)
+
+	start := time.Now()
+	err := process.Run(context.Background(), process.Command{
+		Step: process.StepGoModTidy,
+		Name: "sh",
+		Args: []string{"-c", "sleep 30"},
+	})
+	if time.Since(start) > 10*time.Second {
+		t.Fatalf("command was not killed on timeout")
+	}
+
+	var timeoutErr *mcperrors.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+	if timeoutErr.Step != string(process.StepGoModTidy) || timeoutErr.Timeout != 200*time.Millisecond {
+		t.Errorf("unexpected timeout error %+v", timeoutErr)
+	}
+	if !mcperrors.IsTimeout(err) {
+		t.Error("IsTimeout should report true")
+	}
+}
+
+func TestRunCancelKillsProcessGroup(t *testing.T) {
+	requireShell(t)
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+
+	// The grandchild keeps the output pipe open; only a group kill stops it
+	err := process.Run(ctx, process.Command{
+		Step:   process.StepGoBuild,
+		Name:   "sh",
+		Args:   []string{"-c", "sleep 30 & echo $! > " + pidFile + "; wait"},
+		Stdout: &bytes.Buffer{},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+	if mcperrors.IsTimeout(err) {
+		t.Error("cancellation must not be reported as a timeout")
+	}
+
+	data, readErr := os.ReadFile(pidFile)
+	if readErr != nil {
+		t.Fatalf("child pid not written: %v", readErr)
+	}
+	if _, statErr := os.Stat("/proc"); statErr != nil {
+		return
+	}
+	proc := filepath.Join("/proc", strings.TrimSpace(string(data)))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status, err := os.ReadFile(filepath.Join(proc, "stat"))
+		// Gone, or a zombie waiting to be reaped by init
+		if err != nil || strings.Contains(string(status), ") Z ") {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("grandchild process survived cancellation")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestTimeoutConfiguration(t *testing.T) {
+	step := process.StepGoctl
+	if got := process.Timeout(step); got != process.DefaultTimeouts[step] {
+		t.Errorf("expected default timeout, got %s", got)
+	}
+
+	t.Setenv(process.EnvVar(step), "45s")
+	if got := process.Timeout(step); got != 45*time.Second {
+		t.Errorf("expected env timeout 45s, got %s", got)
+	}
+
+	process.SetTimeout(step, time.Minute)
+	defer process.SetTimeout(step, 0)
+	if got := process.Timeout(step); got != time.Minute {
+		t.Errorf("expected override to win over env, got %s", got)
+	}
+
+	if process.EnvVar(process.StepGoModTidy) != "MCP_GOZERO_TIMEOUT_GO_MOD_TIDY" {
+		t.Errorf("unexpected env var name %s", process.EnvVar(process.StepGoModTidy))
+	}
+}
@@ -0,0 +1,170 @@
+// Package progress reports the steps of long-running tools to the MCP client
+// as progress notifications and streams command output as log messages
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// LineFunc receives one line of command output, without the trailing newline
+type LineFunc func(line string)
+
+// Reporter sends progress notifications for a single tool call. A zero or
+// nil Reporter, or one built from a request without a session, does nothing,
+// so tools can report unconditionally.
+type Reporter struct {
+	ctx     context.Context
+	session *mcp.ServerSession
+	token   any
+	total   int
+
+	mu      sync.Mutex
+	current int
+}
+
+// NewReporter creates a reporter for a tool call made up of total steps.
+// Progress notifications are only sent when the client supplied a progress
+// token; log messages are sent whenever the client has set a log level.
+func NewReporter(ctx context.Context, req *mcp.CallToolRequest, total int) *Reporter {
+	r := &Reporter{ctx: ctx, total: total}
+	if req == nil || req.Session == nil {
+		return r
+	}
+	r.session = req.Session
+	if req.Params != nil {
+		r.token = req.Params.GetProgressToken()
+	}
+	return r
+}
+
+// Step announces that the next step has started
+func (r *Reporter) Step(message string) {
+	if r == nil || r.session == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.current++
+	current := r.current
+	r.mu.Unlock()
+
+	if r.token != nil {
+		r.session.NotifyProgress(r.ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: r.token,
+			Message:       message,
+			Progress:      float64(current),
+			Total:         float64(r.total),
+		})
+	}
+	r.Log(mcp.LoggingLevel("info"), "progress", message)
+}
+
+// Done marks every step as complete
+func (r *Reporter) Done(message string) {
+	if r == nil || r.session == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.current = r.total
+	r.mu.Unlock()
+
+	if r.token != nil {
+		r.session.NotifyProgress(r.ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: r.token,
+			Message:       message,
+			Progress:      float64(r.total),
+			Total:         float64(r.total),
+		})
+	}
+}
+
+// Log sends a log message to the client
+func (r *Reporter) Log(level mcp.LoggingLevel, logger, message string) {
+	if r == nil || r.session == nil {
+		return
+	}
+	r.session.Log(r.ctx, &mcp.LoggingMessageParams{
+		Level:  level,
+		Logger: logger,
+		Data:   message,
+	})
+}
+
+// Output returns a LineFunc that streams command output under the given logger name
+func (r *Reporter) Output(logger string) LineFunc {
+	if r == nil || r.session == nil {
+		return nil
+	}
+	return func(line string) {
+		r.Log(mcp.LoggingLevel("info"), logger, line)
+	}
+}
+
+// LineWriter is an io.Writer that calls a LineFunc for every complete line
+// written to it, while keeping the full output for error messages
+type LineWriter struct {
+	fn LineFunc
+
+	mu      sync.Mutex
+	output  bytes.Buffer
+	partial []byte
+}
+
+// NewLineWriter creates a LineWriter; fn may be nil to only collect output
+func NewLineWriter(fn LineFunc) *LineWriter {
+	return &LineWriter{fn: fn}
+}
+
+// Write implements io.Writer
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.output.Write(p)
+	if w.fn == nil {
+		return len(p), nil
+	}
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits any trailing output that did not end with a newline
+func (w *LineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.fn != nil && len(w.partial) > 0 {
+		w.emit(string(w.partial))
+		w.partial = nil
+	}
+}
+
+// String returns everything written so far
+func (w *LineWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.output.String()
+}
+
+func (w *LineWriter) emit(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	w.fn(line)
+}
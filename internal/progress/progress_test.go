@@ -0,0 +1,45 @@
+package progress_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jinguoxing/mcp-gozero/internal/progress"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := progress.NewLineWriter(func(line string) {
+		lines = append(lines, line)
+	})
+
+	fmt.Fprint(w, "go: finding module ")
+	fmt.Fprint(w, "for package x\r\n\ngo: downloading y\npartial")
+	if want := []string{"go: finding module for package x", "go: downloading y"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("before flush got %q, want %q", lines, want)
+	}
+
+	w.Flush()
+	if lines[len(lines)-1] != "partial" {
+		t.Errorf("expected flush to emit trailing output, got %q", lines)
+	}
+	if w.String() != "go: finding module for package x\r\n\ngo: downloading y\npartial" {
+		t.Errorf("unexpected collected output %q", w.String())
+	}
+}
+
+func TestReporterWithoutSession(t *testing.T) {
+	// Tools are also called directly without a client session; reporting must be a no-op
+	r := progress.NewReporter(context.Background(), nil, 3)
+	r.Step("step")
+	r.Log("info", "go", "line")
+	r.Done("done")
+	if r.Output("go") != nil {
+		t.Error("expected no output handler without a session")
+	}
+
+	var nilReporter *progress.Reporter
+	nilReporter.Step("step")
+}
@@ -1,10 +1,13 @@
 package responses
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 func FormatSuccess(message string) (*mcp.CallToolResult, any, error) {
@@ -13,39 +16,124 @@ func FormatSuccess(message string) (*mcp.CallToolResult, any, error) {
 	}, nil, nil
 }
 
+// FormatSuccessWithData returns message as the human-readable text and data as
+// the structured result; data should match the tool's declared output schema
 func FormatSuccessWithData(message string, data any) (*mcp.CallToolResult, any, error) {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return FormatError(fmt.Sprintf("failed to serialize data: %v", err))
-	}
-	fullMessage := message
-	if len(jsonData) > 0 {
-		fullMessage = fmt.Sprintf("%s\n\n%s", message, string(jsonData))
-	}
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: fullMessage}},
+		Content: []mcp.Content{&mcp.TextContent{Text: message}},
 	}, data, nil
 }
 
 func FormatError(message string) (*mcp.CallToolResult, any, error) {
+	return FormatErrorWithCause(message, nil)
+}
+
+// FormatErrorWithCause is FormatError for failures with an underlying error;
+// the returned error keeps the cause so callers can classify it, and the
+// result's _meta reports the cause's code, category and details
+func FormatErrorWithCause(message string, cause error) (*mcp.CallToolResult, any, error) {
+	return errorResult(fmt.Sprintf("Error: %s", message), message, &causeError{message: message, cause: cause}, nil)
+}
+
+type causeError struct {
+	message string
+	cause   error
+}
+
+func (e *causeError) Error() string { return e.message }
+
+func (e *causeError) Unwrap() error { return e.cause }
+
+// ErrorMetaKey is the _meta key holding the ErrorContent of a failed tool
+// call. It isn't the structured content, which must match the tool's output
+// schema for successful results
+const ErrorMetaKey = "error"
+
+// ErrorContent describes a failed tool call in the result's _meta. Code is
+// stable and machine-readable (see the Code constants of internal/errors),
+// so clients can branch on the failure instead of parsing the text
+type ErrorContent struct {
+	Error       string                       `json:"error"`
+	Code        string                       `json:"code"`
+	Category    string                       `json:"category"`
+	Retryable   bool                         `json:"retryable"`
+	Details     map[string]any               `json:"details,omitempty"`
+	Diagnostics []*mcperrors.DiagnosticError `json:"diagnostics,omitempty"`
+}
+
+// errorResult returns a failed result with text as content and the
+// classification of err in _meta
+func errorResult(text, summary string, err error, diagnostics []*mcperrors.DiagnosticError) (*mcp.CallToolResult, any, error) {
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error: %s", message)}},
+		Meta: mcp.Meta{ErrorMetaKey: &ErrorContent{
+			Error:       summary,
+			Code:        mcperrors.Code(err),
+			Category:    mcperrors.Category(err),
+			Retryable:   mcperrors.Retryable(err),
+			Details:     mcperrors.Details(err),
+			Diagnostics: diagnostics,
+		}},
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
 		IsError: true,
-	}, nil, fmt.Errorf("%s", message)
+	}, nil, err
+}
+
+// FormatDiagnostics is FormatErrorWithCause for a failed command whose output
+// was recognized; the diagnostics are listed after message and returned in
+// the error's _meta, and the first one is the cause
+func FormatDiagnostics(message string, diagnostics []*mcperrors.DiagnosticError) (*mcp.CallToolResult, any, error) {
+	var b strings.Builder
+	b.WriteString(message)
+	b.WriteString("\n\nDiagnostics:\n")
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(&b, "  [%s] %s\n", diagnostic.Kind, diagnostic.Error())
+		fmt.Fprintf(&b, "    Suggestion: %s\n", diagnostic.Remedy)
+	}
+	message = strings.TrimRight(b.String(), "\n")
+
+	return errorResult(fmt.Sprintf("Error: %s", message), message, &causeError{message: message, cause: diagnostics[0]}, diagnostics)
 }
 
 func FormatValidationError(field, value, reason, suggestion string) (*mcp.CallToolResult, any, error) {
+	return formatValidationError(field, value, reason, suggestion, mcperrors.ErrValidationFailed)
+}
+
+// FormatValidationErrorWithCause is FormatValidationError for an error
+// returned by validation; its message is the reason and its sentinel, such
+// as ErrInvalidPort, sets the code
+func FormatValidationErrorWithCause(field, value string, cause error, suggestion string) (*mcp.CallToolResult, any, error) {
+	return formatValidationError(field, value, reason(cause), suggestion, cause)
+}
+
+func formatValidationError(field, value, reason, suggestion string, cause error) (*mcp.CallToolResult, any, error) {
 	message := fmt.Sprintf("Validation Error\n\nField: %s\nValue: %s\nReason: %s", field, value, reason)
 	if suggestion != "" {
 		message += fmt.Sprintf("\nSuggestion: %s", suggestion)
 	}
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: message}},
-		IsError: true,
-	}, nil, fmt.Errorf("validation failed for %s: %s", field, reason)
+	// Errors of the validation package already name the field
+	err, ok := cause.(*mcperrors.ValidationError)
+	if !ok {
+		err = mcperrors.NewValidationError(field, value, reason, cause)
+	}
+	return errorResult(message, reason, err, nil)
+}
+
+// reason is the message of a typed error without its field or path
+func reason(err error) string {
+	var valErr *mcperrors.ValidationError
+	if errors.As(err, &valErr) {
+		return valErr.Message
+	}
+	var pathErr *mcperrors.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Message
+	}
+	return err.Error()
 }
 
-func FormatServiceCreated(serviceType, serviceName, outputDir string, additionalInfo map[string]string) (*mcp.CallToolResult, any, error) {
+// FormatServiceCreated renders the service created message; data is returned
+// as the structured result
+func FormatServiceCreated(serviceType, serviceName, outputDir string, additionalInfo map[string]string, data any) (*mcp.CallToolResult, any, error) {
 	message := fmt.Sprintf("Successfully created %s service '%s'\n\nOutput directory: %s\n", serviceType, serviceName, outputDir)
 	if len(additionalInfo) > 0 {
 		message += "\nAdditional Information:\n"
@@ -57,11 +145,5 @@ func FormatServiceCreated(serviceType, serviceName, outputDir string, additional
 	message += fmt.Sprintf("  1. cd %s\n", outputDir)
 	message += "  2. go mod tidy\n"
 	message += "  3. go run .\n"
-	data := map[string]any{
-		"service_type":    serviceType,
-		"service_name":    serviceName,
-		"output_dir":      outputDir,
-		"additional_info": additionalInfo,
-	}
 	return FormatSuccessWithData(message, data)
 }
@@ -0,0 +1,271 @@
+// Package sandbox restricts the files tools may read and write to the MCP
+// client's roots and a configured allow-list
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Access is the kind of file operation a tool performs on a path
+type Access string
+
+const (
+	Read  Access = "read"
+	Write Access = "write"
+)
+
+// RootsTimeout bounds the roots/list request sent to the client
+const RootsTimeout = 5 * time.Second
+
+// EnvAllowedRoots lists additional allowed directories, separated like PATH
+const EnvAllowedRoots = "MCP_GOZERO_ALLOWED_ROOTS"
+
+// ErrOutsideRoots is wrapped by every PathError
+var ErrOutsideRoots = errors.New("path is outside the permitted roots")
+
+// PathError reports a path rejected by a Scope
+type PathError struct {
+	Path   string
+	Access Access
+	Roots  []string
+	Reason string // set when the path itself could not be resolved
+}
+
+func (e *PathError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s access to %s denied: %s", e.Access, e.Path, e.Reason)
+	}
+	if len(e.Roots) == 0 {
+		return fmt.Sprintf("%s access to %s denied: no permitted roots", e.Access, e.Path)
+	}
+	return fmt.Sprintf("%s access to %s denied: path is outside the permitted roots (%s)",
+		e.Access, e.Path, strings.Join(e.Roots, ", "))
+}
+
+func (e *PathError) Unwrap() error { return ErrOutsideRoots }
+
+// Policy holds the configured allow-list and builds a Scope for each tool call
+type Policy struct {
+	mu        sync.RWMutex
+	allowList []string
+	roots     map[*mcp.ServerSession][]string // client roots cached per session
+}
+
+// NewPolicy creates a policy with an empty allow-list
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// SetAllowList replaces the allow-list; relative entries are resolved against
+// the working directory
+func (p *Policy) SetAllowList(dirs []string) error {
+	allowList := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("invalid allowed root %q: %w", dir, err)
+		}
+		allowList = append(allowList, abs)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allowList = allowList
+	return nil
+}
+
+// AllowList returns the configured allow-list
+func (p *Policy) AllowList() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]string(nil), p.allowList...)
+}
+
+// Scope returns the roots a tool call may access: the client's MCP roots plus
+// the allow-list, or the working directory when both are empty
+func (p *Policy) Scope(ctx context.Context, session *mcp.ServerSession) *Scope {
+	roots := append(p.clientRoots(ctx, session), p.AllowList()...)
+	if len(roots) == 0 {
+		if cwd, err := os.Getwd(); err == nil {
+			roots = append(roots, cwd)
+		}
+	}
+	return NewScope(roots...)
+}
+
+// RootsChanged drops the cached roots of a session whose client sent
+// notifications/roots/list_changed; use it as the server's
+// RootsListChangedHandler
+func (p *Policy) RootsChanged(ctx context.Context, req *mcp.RootsListChangedRequest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.roots, req.Session)
+}
+
+// clientRoots returns the session's roots, listing them once per session
+// until the client reports a change. Timeouts aren't cached so a slow client
+// is asked again on the next call
+func (p *Policy) clientRoots(ctx context.Context, session *mcp.ServerSession) []string {
+	if session == nil {
+		return nil
+	}
+	p.mu.RLock()
+	roots, ok := p.roots[session]
+	p.mu.RUnlock()
+	if ok {
+		return append([]string(nil), roots...)
+	}
+
+	roots, err := listRoots(ctx, session)
+	if err != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)) {
+		return roots
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.roots == nil {
+		p.roots = make(map[*mcp.ServerSession][]string)
+	}
+	if _, cached := p.roots[session]; !cached {
+		// Forget the session once it closes
+		go func() {
+			session.Wait()
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			delete(p.roots, session)
+		}()
+	}
+	p.roots[session] = roots
+	return append([]string(nil), roots...)
+}
+
+// ClientRoots lists the client's file roots; clients without roots support,
+// or that don't answer within RootsTimeout, have none
+func ClientRoots(ctx context.Context, session *mcp.ServerSession) []string {
+	roots, _ := listRoots(ctx, session)
+	return roots
+}
+
+// listRoots sends roots/list to the client and returns its file roots
+func listRoots(ctx context.Context, session *mcp.ServerSession) ([]string, error) {
+	if session == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, RootsTimeout)
+	defer cancel()
+	result, err := session.ListRoots(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []string
+	for _, root := range result.Roots {
+		u, err := url.Parse(root.URI)
+		if err != nil || u.Scheme != "file" || u.Path == "" {
+			continue
+		}
+		roots = append(roots, filepath.Clean(filepath.FromSlash(u.Path)))
+	}
+	return roots, nil
+}
+
+// Scope is the set of directories a single tool call may access
+type Scope struct {
+	roots   []string
+	onWrite func(path string)
+}
+
+// NewScope creates a scope permitting the given directories and everything below them
+func NewScope(roots ...string) *Scope {
+	return &Scope{roots: roots}
+}
+
+// OnWrite sets a function called with every path resolved for write access,
+// e.g. to snapshot it for the audit log
+func (s *Scope) OnWrite(fn func(path string)) *Scope {
+	s.onWrite = fn
+	return s
+}
+
+// Roots returns the permitted directories
+func (s *Scope) Roots() []string {
+	return append([]string(nil), s.roots...)
+}
+
+// Resolve makes path absolute and checks it lies inside a permitted root once
+// symlinks are resolved, so neither ".." nor a link can escape the roots.
+// The returned path is absolute and clean but keeps the caller's symlinks
+func (s *Scope) Resolve(path string, access Access) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", &PathError{Path: path, Access: access, Reason: err.Error()}
+	}
+
+	resolved, err := resolveExisting(abs)
+	if err != nil {
+		return "", &PathError{Path: abs, Access: access, Reason: err.Error()}
+	}
+
+	for _, root := range s.roots {
+		realRoot, err := resolveExisting(root)
+		if err != nil {
+			continue
+		}
+		if within(realRoot, resolved) {
+			if access == Write && s.onWrite != nil {
+				s.onWrite(abs)
+			}
+			return abs, nil
+		}
+	}
+	return "", &PathError{Path: abs, Access: access, Roots: s.Roots()}
+}
+
+// resolveExisting resolves symlinks in the longest existing prefix of path;
+// the part that doesn't exist yet can't contain links and is appended as-is
+func resolveExisting(path string) (string, error) {
+	var missing []string
+	current := path
+	for {
+		if _, err := os.Lstat(current); err == nil {
+			resolved, err := filepath.EvalSymlinks(current)
+			if err != nil {
+				return "", fmt.Errorf("cannot resolve symlink %s: %w", current, err)
+			}
+			for i := len(missing) - 1; i >= 0; i-- {
+				resolved = filepath.Join(resolved, missing[i])
+			}
+			return resolved, nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return path, nil
+		}
+		missing = append(missing, filepath.Base(current))
+		current = parent
+	}
+}
+
+// within reports whether path is root or below it
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel))
+}
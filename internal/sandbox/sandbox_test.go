@@ -0,0 +1,105 @@
+package sandbox_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+)
+
+func TestScopeResolve(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "etc"), 0755)
+	os.WriteFile(filepath.Join(outside, "secret.yaml"), []byte("Pass: x\n"), 0644)
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	os.Symlink(filepath.Join(root, "etc"), filepath.Join(root, "etc-link"))
+	os.Symlink(filepath.Join(outside, "missing"), filepath.Join(root, "dangling"))
+
+	scope := sandbox.NewScope(root)
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"root itself", root, false},
+		{"existing file below root", filepath.Join(root, "etc"), false},
+		{"new file in new directory", filepath.Join(root, "a", "b", "c.go"), false},
+		{"symlink within root", filepath.Join(root, "etc-link", "config.yaml"), false},
+		{"cleaned traversal staying inside", filepath.Join(root, "a") + "/../etc", false},
+		{"traversal out of root", root + "/../" + filepath.Base(outside) + "/secret.yaml", true},
+		{"absolute path outside", filepath.Join(outside, "secret.yaml"), true},
+		{"symlink escaping root", filepath.Join(root, "escape", "secret.yaml"), true},
+		{"new file below escaping symlink", filepath.Join(root, "escape", "new", "file.go"), true},
+		{"dangling symlink", filepath.Join(root, "dangling"), true},
+		{"sibling with root as prefix", root + "-other/file", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := scope.Resolve(tt.path, sandbox.Write)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, sandbox.ErrOutsideRoots) {
+					t.Errorf("expected ErrOutsideRoots, got %v", err)
+				}
+				return
+			}
+			if !filepath.IsAbs(resolved) || resolved != filepath.Clean(resolved) {
+				t.Errorf("expected a clean absolute path, got %q", resolved)
+			}
+		})
+	}
+}
+
+func TestScopeResolveRelative(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := sandbox.NewScope(cwd).Resolve("testdata/out.yaml", sandbox.Read)
+	if err != nil {
+		t.Fatalf("relative path inside the working directory should be allowed: %v", err)
+	}
+	if resolved != filepath.Join(cwd, "testdata", "out.yaml") {
+		t.Errorf("unexpected resolved path %q", resolved)
+	}
+
+	if _, err := sandbox.NewScope().Resolve(cwd, sandbox.Read); err == nil {
+		t.Error("a scope without roots should reject every path")
+	}
+}
+
+func TestPolicyScope(t *testing.T) {
+	allowed := t.TempDir()
+	policy := sandbox.NewPolicy()
+
+	// Without client roots or an allow-list only the working directory is permitted
+	cwd, _ := os.Getwd()
+	if roots := policy.Scope(context.Background(), nil).Roots(); len(roots) != 1 || roots[0] != cwd {
+		t.Errorf("expected the working directory as the only root, got %v", roots)
+	}
+
+	if err := policy.SetAllowList([]string{allowed, ""}); err != nil {
+		t.Fatalf("SetAllowList failed: %v", err)
+	}
+	scope := policy.Scope(context.Background(), nil)
+	if roots := scope.Roots(); len(roots) != 1 || roots[0] != allowed {
+		t.Errorf("expected only the allow-list, got %v", roots)
+	}
+	if _, err := scope.Resolve(filepath.Join(allowed, "x.api"), sandbox.Write); err != nil {
+		t.Errorf("path in allow-list rejected: %v", err)
+	}
+	if _, err := scope.Resolve(filepath.Join(cwd, "x.api"), sandbox.Write); err == nil {
+		t.Error("working directory should not be permitted once an allow-list is set")
+	}
+}
@@ -0,0 +1,448 @@
+// Package serverconfig loads mcp-gozero.yaml, the team-wide defaults of the
+// MCP server: module prefix, code style, ports, output layout, tool paths,
+// enabled tools, allowed roots, cache, audit log, operation history and
+// subprocess timeouts
+package serverconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// FileName is the config file looked up in the project root and user config dir
+const FileName = "mcp-gozero.yaml"
+
+// EnvConfig points to a config file, like the --config flag
+const EnvConfig = "MCP_GOZERO_CONFIG"
+
+// Config holds the server defaults
+type Config struct {
+	ModulePrefix string                   `yaml:"module_prefix"` // module path prefix of generated services
+	Style        string                   `yaml:"style"`         // go_zero, gozero or goZero
+	Ports        Ports                    `yaml:"ports"`
+	Layout       Layout                   `yaml:"layout"`
+	Goctl        Goctl                    `yaml:"goctl"`
+	Protoc       Protoc                   `yaml:"protoc"`
+	Tools        Tools                    `yaml:"tools"`
+	AllowedRoots []string                 `yaml:"allowed_roots"` // relative entries are resolved against the config file
+	Cache        Cache                    `yaml:"cache"`
+	Audit        Audit                    `yaml:"audit"`
+	History      History                  `yaml:"history"`
+	Timeouts     map[string]time.Duration `yaml:"timeouts"` // per subprocess step, e.g. go_mod_tidy: 10m
+
+	// Sources lists the files the config was loaded from, in load order
+	Sources []string `yaml:"-"`
+	// Warnings lists keys ignored while loading, e.g. trusted keys set in
+	// the project root config
+	Warnings []string `yaml:"-"`
+}
+
+// Ports holds the port ranges of API and RPC services
+type Ports struct {
+	API PortRange `yaml:"api"`
+	RPC PortRange `yaml:"rpc"`
+}
+
+// PortRange is the default port and the range ports must fall in
+type PortRange struct {
+	Default int `yaml:"default"`
+	Min     int `yaml:"min"`
+	Max     int `yaml:"max"`
+}
+
+// Contains reports whether port is inside the range
+func (r PortRange) Contains(port int) bool {
+	return port >= r.Min && port <= r.Max
+}
+
+// Layout holds the default output locations, relative to the working directory
+type Layout struct {
+	ModelDir  string `yaml:"model_dir"`  // generate_model output
+	ConfigDir string `yaml:"config_dir"` // generate_config_template output
+}
+
+// Goctl configures goctl discovery
+type Goctl struct {
+	Path        string   `yaml:"path"`         // used instead of discovery when set
+	SearchPaths []string `yaml:"search_paths"` // checked before the built-in locations
+}
+
+// Protoc configures the protoc used by goctl rpc
+type Protoc struct {
+	Path string `yaml:"path"` // its directory is put first on PATH for goctl
+}
+
+// Tools selects the registered tools; at most one of the lists may be set
+type Tools struct {
+	Enabled  []string `yaml:"enabled"`
+	Disabled []string `yaml:"disabled"`
+}
+
+// Cache configures the project analysis cache
+type Cache struct {
+	TTL        time.Duration `yaml:"ttl"`
+	MaxEntries int           `yaml:"max_entries"`
+}
+
+// Audit configures the audit log of file writes and subprocesses
+type Audit struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // JSONL file, DefaultAuditPath when empty
+}
+
+// History configures the operation history used by undo_operation
+type History struct {
+	MaxOperations    int   `yaml:"max_operations"`     // operations kept, oldest evicted first
+	MaxSnapshotBytes int64 `yaml:"max_snapshot_bytes"` // prior file content kept per operation
+}
+
+// Default returns the built-in defaults
+func Default() *Config {
+	return &Config{
+		ModulePrefix: "github.com/example",
+		Style:        "go_zero",
+		Ports: Ports{
+			API: PortRange{Default: 8888, Min: 1024, Max: 65535},
+			RPC: PortRange{Default: 9090, Min: 1024, Max: 65535},
+		},
+		Layout: Layout{
+			ModelDir:  "./model",
+			ConfigDir: "etc",
+		},
+		Cache: Cache{
+			TTL:        5 * time.Minute,
+			MaxEntries: 100,
+		},
+		Audit: Audit{
+			Enabled: true,
+		},
+		History: History{
+			MaxOperations:    20,
+			MaxSnapshotBytes: snapshot.DefaultContentBudget,
+		},
+	}
+}
+
+// DefaultAuditPath returns the audit log in the user config dir, e.g.
+// ~/.config/mcp-gozero/audit.jsonl on Linux
+func DefaultAuditPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "mcp-gozero-audit.jsonl"
+	}
+	return filepath.Join(dir, "mcp-gozero", "audit.jsonl")
+}
+
+// AuditPath returns the audit log file, or "" when auditing is disabled
+func (c *Config) AuditPath() string {
+	if !c.Audit.Enabled {
+		return ""
+	}
+	if c.Audit.Path == "" {
+		return DefaultAuditPath()
+	}
+	return c.Audit.Path
+}
+
+// UserPath returns the config file in the user config dir, e.g.
+// ~/.config/mcp-gozero/mcp-gozero.yaml on Linux
+func UserPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mcp-gozero", FileName)
+}
+
+// trustedKeys are the keys that widen what the server may access or run, or
+// that turn off the audit log or undo. A project root config comes with the
+// checked-out project, so it may not set them; only the user config,
+// --config and flags can
+var trustedKeys = []string{
+	"allowed_roots",
+	"goctl.path",
+	"goctl.search_paths",
+	"protoc.path",
+	"audit.enabled",
+	"audit.path",
+	"history.max_operations",
+	"history.max_snapshot_bytes",
+}
+
+// Load builds the config from the defaults. An explicit path is the only
+// file read and must exist; otherwise the user config is applied first and
+// the project root config on top of it, each only if present. Trusted keys
+// in the project root config are ignored with a warning.
+func Load(explicitPath, projectRoot string) (*Config, error) {
+	cfg := Default()
+
+	if explicitPath != "" {
+		if err := cfg.apply(explicitPath, true); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, file := range []struct {
+			path    string
+			trusted bool
+		}{{UserPath(), true}, {filepath.Join(projectRoot, FileName), false}} {
+			if file.path == "" {
+				continue
+			}
+			if _, err := os.Stat(file.path); err != nil {
+				continue
+			}
+			if err := cfg.apply(file.path, file.trusted); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid server config %s: %w", strings.Join(cfg.Sources, ", "), err)
+	}
+	return cfg, nil
+}
+
+// apply layers a config file over cfg; keys missing from the file keep their
+// value, and so do trusted keys unless the file is trusted
+func (c *Config) apply(path string, trusted bool) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve server config path: %w", err)
+	}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read server config: %w", err)
+	}
+
+	var file Config
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse server config %s: %w", absPath, err)
+	}
+
+	// Decode again onto the current values so only keys in the file override them
+	kept := *c
+	decoder = yaml.NewDecoder(bytes.NewReader(content))
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse server config %s: %w", absPath, err)
+	}
+
+	if !trusted {
+		if ignored := trustedKeysSet(content); len(ignored) > 0 {
+			c.AllowedRoots = kept.AllowedRoots
+			c.Goctl = kept.Goctl
+			c.Protoc.Path = kept.Protoc.Path
+			c.Audit = kept.Audit
+			c.History = kept.History
+			c.Warnings = append(c.Warnings, fmt.Sprintf("%s: ignored %s; set them in %s, with --config or with flags",
+				absPath, strings.Join(ignored, ", "), valueOrDefault(UserPath(), "the user config")))
+			file.AllowedRoots, file.Goctl, file.Protoc.Path, file.Audit.Path = nil, Goctl{}, "", ""
+		}
+	}
+
+	// Paths in a config file are relative to the file
+	dir := filepath.Dir(absPath)
+	if file.AllowedRoots != nil {
+		c.AllowedRoots = resolvePaths(dir, file.AllowedRoots)
+	}
+	if file.Goctl.Path != "" {
+		c.Goctl.Path = resolvePath(dir, file.Goctl.Path)
+	}
+	if file.Goctl.SearchPaths != nil {
+		c.Goctl.SearchPaths = resolvePaths(dir, file.Goctl.SearchPaths)
+	}
+	if file.Protoc.Path != "" {
+		c.Protoc.Path = resolvePath(dir, file.Protoc.Path)
+	}
+	if file.Audit.Path != "" {
+		c.Audit.Path = resolvePath(dir, file.Audit.Path)
+	}
+
+	c.Sources = append(c.Sources, absPath)
+	return nil
+}
+
+// trustedKeysSet returns the trusted keys a config file sets. Keys are
+// looked up in the raw document so that values equal to the zero value,
+// such as audit.enabled: false, are found too
+func trustedKeysSet(content []byte) []string {
+	var raw map[string]any
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil
+	}
+	var keys []string
+	for _, key := range trustedKeys {
+		if hasKey(raw, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// hasKey reports whether a dotted key such as audit.enabled is set in a raw document
+func hasKey(raw map[string]any, key string) bool {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := raw[part].(map[string]any)
+		if !ok {
+			return false
+		}
+		raw = nested
+	}
+	value, ok := raw[parts[len(parts)-1]]
+	return ok && value != nil
+}
+
+// Validate checks the config for values the tools can't work with
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	c.ModulePrefix = strings.TrimSuffix(strings.TrimSpace(c.ModulePrefix), "/")
+	if c.ModulePrefix == "" || strings.ContainsAny(c.ModulePrefix, " \t\\") || strings.HasPrefix(c.ModulePrefix, "/") {
+		add("module_prefix %q is not a valid module path prefix", c.ModulePrefix)
+	}
+
+	if c.Style != "go_zero" && c.Style != "gozero" && c.Style != "goZero" {
+		add("style %q must be go_zero, gozero or goZero", c.Style)
+	}
+
+	for name, r := range map[string]PortRange{"ports.api": c.Ports.API, "ports.rpc": c.Ports.RPC} {
+		if r.Min < 1024 || r.Max > 65535 || r.Min > r.Max {
+			add("%s range %d-%d must lie within 1024-65535", name, r.Min, r.Max)
+		} else if !r.Contains(r.Default) {
+			add("%s default %d is outside %d-%d", name, r.Default, r.Min, r.Max)
+		}
+	}
+
+	if c.Layout.ModelDir == "" {
+		add("layout.model_dir must not be empty")
+	}
+	if c.Layout.ConfigDir == "" {
+		add("layout.config_dir must not be empty")
+	}
+
+	for name, path := range map[string]string{"goctl.path": c.Goctl.Path, "protoc.path": c.Protoc.Path} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			add("%s %s is not an executable file", name, path)
+		}
+	}
+
+	if len(c.Tools.Enabled) > 0 && len(c.Tools.Disabled) > 0 {
+		add("set either tools.enabled or tools.disabled, not both")
+	}
+
+	if c.Cache.TTL <= 0 {
+		add("cache.ttl must be positive")
+	}
+	if c.Cache.MaxEntries <= 0 {
+		add("cache.max_entries must be positive")
+	}
+
+	if c.History.MaxOperations <= 0 {
+		add("history.max_operations must be positive")
+	}
+	if c.History.MaxSnapshotBytes < 0 {
+		add("history.max_snapshot_bytes must not be negative")
+	}
+
+	for step, timeout := range c.Timeouts {
+		if _, ok := process.DefaultTimeouts[process.Step(step)]; !ok {
+			add("timeouts.%s is not a known step (use %s)", step, strings.Join(Steps(), ", "))
+		} else if timeout <= 0 {
+			add("timeouts.%s must be positive", step)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// ToolEnabled reports whether a tool should be registered
+func (c *Config) ToolEnabled(name string) bool {
+	if len(c.Tools.Enabled) > 0 {
+		return contains(c.Tools.Enabled, name)
+	}
+	return !contains(c.Tools.Disabled, name)
+}
+
+// ToolNames returns the tool names referenced by tools.enabled and tools.disabled
+func (c *Config) ToolNames() []string {
+	return append(append([]string(nil), c.Tools.Enabled...), c.Tools.Disabled...)
+}
+
+// PortRange returns the port range for "api" or "rpc" services
+func (c *Config) PortRange(serviceType string) PortRange {
+	if serviceType == "rpc" {
+		return c.Ports.RPC
+	}
+	return c.Ports.API
+}
+
+// ModulePath returns the module path of a generated service
+func (c *Config) ModulePath(serviceName string) string {
+	return c.ModulePrefix + "/" + serviceName
+}
+
+// Steps returns the subprocess steps that accept a timeout
+func Steps() []string {
+	steps := make([]string, 0, len(process.DefaultTimeouts))
+	for step := range process.DefaultTimeouts {
+		steps = append(steps, string(step))
+	}
+	sort.Strings(steps)
+	return steps
+}
+
+func resolvePaths(dir string, paths []string) []string {
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		resolved = append(resolved, resolvePath(dir, path))
+	}
+	return resolved
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func contains(list []string, item string) bool {
+	for _, s := range list {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package serverconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinguoxing/mcp-gozero/internal/serverconfig"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, serverconfig.FileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := serverconfig.Load("", t.TempDir())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Sources) != 0 {
+		t.Errorf("expected no sources, got %v", cfg.Sources)
+	}
+	if cfg.ModulePath("user-api") != "github.com/example/user-api" || cfg.Style != "go_zero" {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+	if cfg.Ports.API.Default != 8888 || cfg.Ports.RPC.Default != 9090 || cfg.Layout.ModelDir != "./model" {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+	if cfg.Cache.TTL != 5*time.Minute || cfg.Cache.MaxEntries != 100 {
+		t.Errorf("unexpected cache defaults: %+v", cfg.Cache)
+	}
+}
+
+func TestLoadLayering(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", t.TempDir())
+	if serverconfig.UserPath() != filepath.Join(configHome, "mcp-gozero", serverconfig.FileName) {
+		t.Skipf("user config dir is not taken from XDG_CONFIG_HOME on this platform: %s", serverconfig.UserPath())
+	}
+
+	userPath := writeConfig(t, filepath.Join(configHome, "mcp-gozero"), `
+module_prefix: github.com/acme/
+style: gozero
+allowed_roots: [shared, /opt/specs]
+cache:
+  ttl: 1m
+`)
+	project := t.TempDir()
+	projectPath := writeConfig(t, project, `
+module_prefix: gitlab.acme.io/platform
+ports:
+  api: {default: 8100, min: 8000, max: 8999}
+timeouts:
+  go_mod_tidy: 10m
+`)
+
+	cfg, err := serverconfig.Load("", project)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Sources) != 2 || cfg.Sources[0] != userPath || cfg.Sources[1] != projectPath {
+		t.Errorf("expected user then project source, got %v", cfg.Sources)
+	}
+	if cfg.ModulePath("order") != "gitlab.acme.io/platform/order" {
+		t.Errorf("project config should override the module prefix, got %s", cfg.ModulePrefix)
+	}
+	if cfg.Style != "gozero" || cfg.Cache.TTL != time.Minute || cfg.Cache.MaxEntries != 100 {
+		t.Errorf("user values and defaults should be kept when not overridden: %+v", cfg)
+	}
+	if cfg.Ports.API.Default != 8100 || cfg.Ports.RPC.Default != 9090 {
+		t.Errorf("unexpected ports: %+v", cfg.Ports)
+	}
+	if cfg.AllowedRoots[0] != filepath.Join(configHome, "mcp-gozero", "shared") || cfg.AllowedRoots[1] != "/opt/specs" {
+		t.Errorf("relative roots should resolve against the config file: %v", cfg.AllowedRoots)
+	}
+	if cfg.Timeouts["go_mod_tidy"] != 10*time.Minute {
+		t.Errorf("unexpected timeouts: %v", cfg.Timeouts)
+	}
+
+	// An explicit file replaces both discovered files
+	explicit := writeConfig(t, t.TempDir(), "style: go_zero\n")
+	cfg, err = serverconfig.Load(explicit, project)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Sources) != 1 || cfg.ModulePrefix != "github.com/example" || cfg.Style != "go_zero" {
+		t.Errorf("explicit config should be the only source: %+v", cfg)
+	}
+
+	if _, err := serverconfig.Load(filepath.Join(project, "missing.yaml"), project); err == nil {
+		t.Error("expected an error for a missing explicit config")
+	}
+}
+
+func TestLoadProjectConfigCannotWidenTrust(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", t.TempDir())
+	if serverconfig.UserPath() != filepath.Join(configHome, "mcp-gozero", serverconfig.FileName) {
+		t.Skipf("user config dir is not taken from XDG_CONFIG_HOME on this platform: %s", serverconfig.UserPath())
+	}
+
+	writeConfig(t, filepath.Join(configHome, "mcp-gozero"), "allowed_roots: [/srv/specs]\n")
+	project := t.TempDir()
+	projectConfig := `
+style: gozero
+allowed_roots: [/]
+goctl:
+  search_paths: [./bin]
+protoc:
+  path: ./bin/protoc
+audit:
+  enabled: false
+  path: /dev/null
+history:
+  max_snapshot_bytes: 0
+`
+	writeConfig(t, project, projectConfig)
+
+	cfg, err := serverconfig.Load("", project)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Style != "gozero" {
+		t.Errorf("untrusted keys should not stop other project values, got style %s", cfg.Style)
+	}
+	if len(cfg.AllowedRoots) != 1 || cfg.AllowedRoots[0] != "/srv/specs" {
+		t.Errorf("project config should not change allowed roots: %v", cfg.AllowedRoots)
+	}
+	if cfg.Goctl.SearchPaths != nil || cfg.Protoc.Path != "" || cfg.Audit.Path != "" {
+		t.Errorf("project config should not set tool or audit paths: %+v %+v %+v", cfg.Goctl, cfg.Protoc, cfg.Audit)
+	}
+	if !cfg.Audit.Enabled || cfg.History != serverconfig.Default().History {
+		t.Errorf("project config should not disable the audit log or undo: %+v %+v", cfg.Audit, cfg.History)
+	}
+	if len(cfg.Warnings) != 1 {
+		t.Fatalf("expected one warning, got %v", cfg.Warnings)
+	}
+	for _, key := range []string{"allowed_roots", "goctl.search_paths", "protoc.path", "audit.enabled", "audit.path", "history.max_snapshot_bytes"} {
+		if !strings.Contains(cfg.Warnings[0], key) {
+			t.Errorf("warning should name %s: %s", key, cfg.Warnings[0])
+		}
+	}
+
+	// The same file passed explicitly is trusted
+	explicit := writeConfig(t, t.TempDir(), "allowed_roots: [/]\n")
+	cfg, err = serverconfig.Load(explicit, project)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.AllowedRoots) != 1 || cfg.AllowedRoots[0] != "/" || len(cfg.Warnings) != 0 {
+		t.Errorf("explicit config should set allowed roots: %v %v", cfg.AllowedRoots, cfg.Warnings)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "modul_prefix: x\n", "field modul_prefix not found"},
+		{"bad style", "style: camel\n", "style \"camel\""},
+		{"default outside range", "ports:\n  rpc: {default: 80}\n", "ports.rpc default 80"},
+		{"privileged range", "ports:\n  api: {min: 80}\n", "ports.api range 80-65535"},
+		{"empty layout", "layout:\n  model_dir: \"\"\n", "layout.model_dir"},
+		{"missing goctl", "goctl:\n  path: ./no-goctl\n", "goctl.path"},
+		{"enabled and disabled", "tools:\n  enabled: [query_docs]\n  disabled: [server_stats]\n", "not both"},
+		{"cache ttl", "cache:\n  ttl: 0s\n", "cache.ttl"},
+		{"unknown timeout step", "timeouts:\n  goctl_api: 1m\n", "timeouts.goctl_api is not a known step"},
+		{"module prefix", "module_prefix: \"/abs\"\n", "module_prefix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, t.TempDir(), tt.content)
+			_, err := serverconfig.Load(path, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToolEnabled(t *testing.T) {
+	cfg := serverconfig.Default()
+	if !cfg.ToolEnabled("query_docs") {
+		t.Error("every tool is enabled by default")
+	}
+
+	cfg.Tools.Disabled = []string{"generate_model"}
+	if cfg.ToolEnabled("generate_model") || !cfg.ToolEnabled("query_docs") {
+		t.Error("disabled list should only remove the listed tools")
+	}
+
+	cfg.Tools = serverconfig.Tools{Enabled: []string{"query_docs"}}
+	if !cfg.ToolEnabled("query_docs") || cfg.ToolEnabled("generate_model") {
+		t.Error("enabled list should only keep the listed tools")
+	}
+}
@@ -0,0 +1,260 @@
+// Package snapshot detects the files a tool call creates, modifies or
+// deletes by comparing the paths it writes before and after the call, and
+// keeps the prior content of changed files so they can be restored
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Change actions
+const (
+	ActionCreated  = "created"
+	ActionModified = "modified"
+	ActionDeleted  = "deleted"
+)
+
+// MaxFiles bounds the entries recorded per tracked path, so pointing a tool
+// at a huge tree doesn't stall the call. Changes beyond it are missed, so the
+// changes of a truncated path are flagged and can't be undone
+const MaxFiles = 20000
+
+// DefaultContentBudget is the prior file content a Tracker keeps per call
+const DefaultContentBudget = 32 << 20
+
+// skippedDirs are never tracked
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// Content is the state of a file or directory before the call
+type Content struct {
+	Data []byte
+	Mode fs.FileMode
+}
+
+// Change is a file or directory changed by the call
+type Change struct {
+	Path   string
+	Action string // created, modified or deleted
+	Dir    bool
+	// Before is the prior state of modified and deleted entries; nil when the
+	// content exceeded the budget and can't be restored
+	Before *Content
+	// AfterHash is the SHA-256 of the file content after the call, empty for
+	// directories and deleted files
+	AfterHash string
+	// Truncated is set when the tracked path holding the change had more
+	// than MaxFiles entries: its changes are incomplete and not restorable
+	Truncated bool
+}
+
+type entry struct {
+	dir     bool
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+	data    []byte
+	hasData bool
+}
+
+func (e entry) sameState(other entry) bool {
+	return e.dir == other.dir && e.size == other.size && e.modTime.Equal(other.modTime) && e.mode == other.mode
+}
+
+type tree map[string]entry
+
+type root struct {
+	path      string
+	entries   tree
+	truncated bool
+}
+
+// Tracker records the state of the paths a tool call writes. A nil
+// *Tracker tracks nothing
+type Tracker struct {
+	mu     sync.Mutex
+	roots  []root
+	budget int64
+}
+
+// NewTracker creates a tracker keeping up to budget bytes of prior content
+func NewTracker(budget int64) *Tracker {
+	return &Tracker{budget: budget}
+}
+
+// Track records the current state of a file or directory and everything
+// below it; paths inside an already tracked path are ignored
+func (t *Tracker) Track(path string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range t.roots {
+		if Within(r.path, path) {
+			return
+		}
+	}
+	entries, truncated := walk(path, &t.budget)
+	t.roots = append(t.roots, root{path: path, entries: entries, truncated: truncated})
+}
+
+// Truncated returns the tracked paths with more than MaxFiles entries
+func (t *Tracker) Truncated() []string {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var paths []string
+	for _, r := range t.roots {
+		if r.truncated {
+			paths = append(paths, r.path)
+		}
+	}
+	return paths
+}
+
+// Changes compares the tracked paths with their current state. Where
+// tracked paths overlap, the earliest recorded state wins
+func (t *Tracker) Changes() []Change {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changes []Change
+	seen := make(map[string]bool)
+	add := func(c Change) {
+		if !seen[c.Path] {
+			seen[c.Path] = true
+			changes = append(changes, c)
+		}
+	}
+	for _, r := range t.roots {
+		before := r.entries
+		after, truncated := walk(r.path, nil)
+		truncated = truncated || r.truncated
+		add := func(c Change) {
+			c.Truncated = truncated
+			add(c)
+		}
+		for path, state := range after {
+			previous, existed := before[path]
+			switch {
+			case !existed:
+				add(Change{Path: path, Action: ActionCreated, Dir: state.dir, AfterHash: fileHash(path, state)})
+			case !state.dir && !previous.sameState(state):
+				add(Change{Path: path, Action: ActionModified, Before: previous.content(), AfterHash: fileHash(path, state)})
+			default:
+				seen[path] = true
+			}
+		}
+		for path, previous := range before {
+			if _, exists := after[path]; !exists {
+				add(Change{Path: path, Action: ActionDeleted, Dir: previous.dir, Before: previous.content()})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func (e entry) content() *Content {
+	if e.dir {
+		return &Content{Mode: e.mode}
+	}
+	if !e.hasData {
+		return nil
+	}
+	return &Content{Data: e.data, Mode: e.mode}
+}
+
+// walk records the entries at or below path, reporting whether it stopped at
+// MaxFiles; with a budget, file content is kept while it lasts
+func walk(path string, budget *int64) (tree, bool) {
+	entries := make(tree)
+	truncated := false
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && p != path && skippedDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if len(entries) >= MaxFiles {
+			truncated = true
+			return filepath.SkipAll
+		}
+		info, err := d.Info()
+		if err != nil || !(info.Mode().IsRegular() || info.IsDir()) {
+			return nil
+		}
+		e := entry{dir: info.IsDir(), size: info.Size(), modTime: info.ModTime(), mode: info.Mode()}
+		if !e.dir && budget != nil && e.size <= *budget {
+			if data, err := os.ReadFile(p); err == nil {
+				e.data, e.hasData = data, true
+				*budget -= int64(len(data))
+			}
+		}
+		entries[p] = e
+		return nil
+	})
+	return entries, truncated
+}
+
+func fileHash(path string, e entry) string {
+	if e.dir {
+		return ""
+	}
+	hash, _ := HashFile(path)
+	return hash
+}
+
+// HashFile returns the SHA-256 of a file's content
+func HashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Within reports whether path is root or below it
+func Within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel))
+}
+
+type trackerKey struct{}
+
+// WithTracker returns a context carrying the tracker of the current tool call
+func WithTracker(ctx context.Context, t *Tracker) context.Context {
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, trackerKey{}, t)
+}
+
+// FromContext returns the tracker of the current tool call, or nil
+func FromContext(ctx context.Context) *Tracker {
+	t, _ := ctx.Value(trackerKey{}).(*Tracker)
+	return t
+}
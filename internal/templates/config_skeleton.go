@@ -0,0 +1,247 @@
+package templates
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
+)
+
+// ConfigSkeletonOverrides returns environment-specific values layered on top of struct defaults.
+// Host only exists in rest.RestConf; RPC services bind through ListenOn instead.
+func ConfigSkeletonOverrides(serviceType, environment string) map[string]interface{} {
+	switch environment {
+	case "production", "prod":
+		return map[string]interface{}{
+			"Mode": "pro",
+			"Log": map[string]interface{}{
+				"Mode":     "file",
+				"Level":    "error",
+				"Encoding": "json",
+				"KeepDays": 7,
+				"Compress": true,
+			},
+		}
+	case "test":
+		values := map[string]interface{}{
+			"Mode": "test",
+			"Log": map[string]interface{}{
+				"Level":    "debug",
+				"Encoding": "plain",
+			},
+		}
+		if serviceType == "api" {
+			values["Host"] = "127.0.0.1"
+		}
+		return values
+	default:
+		return map[string]interface{}{
+			"Mode": "dev",
+			"Log": map[string]interface{}{
+				"Encoding": "plain",
+			},
+		}
+	}
+}
+
+// MergeConfigValues deep-merges override values into base, returning base
+func MergeConfigValues(base, override map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = make(map[string]interface{})
+	}
+	for key, value := range override {
+		if overrideMap, ok := value.(map[string]interface{}); ok {
+			if baseMap, ok := base[key].(map[string]interface{}); ok {
+				base[key] = MergeConfigValues(baseMap, overrideMap)
+				continue
+			}
+		}
+		base[key] = value
+	}
+	return base
+}
+
+// RenderConfigSkeleton renders a commented YAML config containing every struct field.
+// Values are taken from values when present, otherwise from the field default.
+// It returns the rendered YAML and the override keys that matched no field.
+func RenderConfigSkeleton(fields []analyzer.ConfigStructField, values map[string]interface{}) (string, []string) {
+	var sb strings.Builder
+	unknown := renderFields(&sb, fields, values, 0, "")
+	sort.Strings(unknown)
+	return sb.String(), unknown
+}
+
+func renderFields(sb *strings.Builder, fields []analyzer.ConfigStructField, values map[string]interface{}, indent int, prefix string) []string {
+	pad := strings.Repeat("  ", indent)
+	used := make(map[string]bool)
+	var unknown []string
+
+	for _, field := range fields {
+		value, hasValue := lookupValue(values, field.Key)
+		if hasValue {
+			used[strings.ToLower(field.Key)] = true
+		}
+
+		if comment := fieldAnnotation(field); comment != "" {
+			sb.WriteString(fmt.Sprintf("%s# %s\n", pad, comment))
+		}
+
+		switch field.Kind {
+		case "struct":
+			if len(field.Fields) == 0 {
+				sb.WriteString(fmt.Sprintf("%s%s: {}\n", pad, field.Key))
+				continue
+			}
+			nested, _ := value.(map[string]interface{})
+			sb.WriteString(fmt.Sprintf("%s%s:\n", pad, field.Key))
+			unknown = append(unknown, renderFields(sb, field.Fields, nested, indent+1, joinKey(prefix, field.Key))...)
+		case "list":
+			if hasValue {
+				writeYAMLValue(sb, pad, field.Key, value)
+				continue
+			}
+			if len(field.Fields) == 0 {
+				sb.WriteString(fmt.Sprintf("%s%s: []\n", pad, field.Key))
+				continue
+			}
+			// Emit one element so the expected item shape is visible
+			sb.WriteString(fmt.Sprintf("%s%s:\n", pad, field.Key))
+			var item strings.Builder
+			renderFields(&item, field.Fields, nil, indent+2, joinKey(prefix, field.Key))
+			itemPad := strings.Repeat("  ", indent+2)
+			marked := false
+			for _, line := range strings.SplitAfter(item.String(), "\n") {
+				if !marked && strings.HasPrefix(line, itemPad) && !strings.HasPrefix(strings.TrimSpace(line), "#") {
+					line = pad + "  - " + strings.TrimPrefix(line, itemPad)
+					marked = true
+				}
+				sb.WriteString(line)
+			}
+		case "map":
+			if hasValue {
+				writeYAMLValue(sb, pad, field.Key, value)
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("%s%s: {}\n", pad, field.Key))
+		default:
+			if !hasValue {
+				value = defaultScalar(field)
+			}
+			writeYAMLValue(sb, pad, field.Key, value)
+		}
+	}
+
+	for key := range values {
+		if !used[strings.ToLower(key)] {
+			unknown = append(unknown, joinKey(prefix, key))
+		}
+	}
+
+	return unknown
+}
+
+// lookupValue finds a key case-insensitively, matching go-zero's config loading
+func lookupValue(values map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := values[key]; ok {
+		return value, true
+	}
+	for k, value := range values {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// fieldAnnotation describes defaults, options and optionality as a YAML comment
+func fieldAnnotation(field analyzer.ConfigStructField) string {
+	var parts []string
+	if field.Comment != "" {
+		parts = append(parts, field.Comment)
+	}
+	if field.Kind == "scalar" || field.Kind == "list" {
+		parts = append(parts, field.Type)
+	}
+	if field.HasDefault {
+		parts = append(parts, fmt.Sprintf("default: %s", field.Default))
+	}
+	if len(field.Options) > 0 {
+		parts = append(parts, fmt.Sprintf("options: %s", strings.Join(field.Options, " | ")))
+	}
+	if field.Range != "" {
+		parts = append(parts, fmt.Sprintf("range: %s", field.Range))
+	}
+	if field.Env != "" {
+		parts = append(parts, fmt.Sprintf("env: %s", field.Env))
+	}
+	if field.Optional {
+		parts = append(parts, "optional")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// defaultScalar returns the tag default converted to the field type, or its zero value
+func defaultScalar(field analyzer.ConfigStructField) interface{} {
+	typ := strings.TrimPrefix(field.Type, "*")
+
+	if field.HasDefault {
+		switch {
+		case typ == "bool":
+			if b, err := strconv.ParseBool(field.Default); err == nil {
+				return b
+			}
+		case strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint"):
+			if n, err := strconv.ParseInt(field.Default, 10, 64); err == nil {
+				return n
+			}
+		case strings.HasPrefix(typ, "float"):
+			if f, err := strconv.ParseFloat(field.Default, 64); err == nil {
+				return f
+			}
+		}
+		return field.Default
+	}
+
+	switch {
+	case typ == "bool":
+		return false
+	case strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint") || strings.HasPrefix(typ, "float"):
+		return 0
+	case typ == "time.Duration":
+		return "0s"
+	default:
+		return ""
+	}
+}
+
+func writeYAMLValue(sb *strings.Builder, pad, key string, value interface{}) {
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("%s%s: %v\n", pad, key, value))
+		return
+	}
+
+	text := strings.TrimRight(string(out), "\n")
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, []string:
+		if len(strings.Split(text, "\n")) > 1 || !strings.HasPrefix(text, "[") && !strings.HasPrefix(text, "{") {
+			sb.WriteString(fmt.Sprintf("%s%s:\n", pad, key))
+			for _, line := range strings.Split(text, "\n") {
+				sb.WriteString(pad + "  " + line + "\n")
+			}
+			return
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%s%s: %s\n", pad, key, text))
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -17,6 +17,8 @@ type ConfigError struct {
 	Field   string
 	Message string
 	Value   interface{}
+	Line    int // 1-based, 0 if unknown
+	Column  int
 }
 
 // ConfigWarning represents a configuration warning
@@ -24,6 +26,8 @@ type ConfigWarning struct {
 	Field      string
 	Message    string
 	Suggestion string
+	Line       int // 1-based, 0 if unknown
+	Column     int
 }
 
 // ValidateAPIConfig validates go-zero API service configuration
@@ -0,0 +1,336 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// minAccessExpireSeconds is the shortest Auth.AccessExpire considered safe for production
+const minAccessExpireSeconds = 300
+
+// EnvironmentConfig is a parsed config file tagged with its environment
+type EnvironmentConfig struct {
+	Path        string
+	Environment string // "development", "test" or "production"
+	Values      map[string]interface{}
+}
+
+// ConfigChange describes a key whose value differs between two configs
+type ConfigChange struct {
+	Key  string
+	From interface{}
+	To   interface{}
+}
+
+// ConfigDiff contains the differences between a base config and a target config
+type ConfigDiff struct {
+	BasePath          string
+	TargetPath        string
+	BaseEnvironment   string
+	TargetEnvironment string
+	Added             []string
+	Removed           []string
+	Changed           []ConfigChange
+}
+
+// PromotionRisk is a config value that is unsafe to promote to production
+type PromotionRisk struct {
+	Path       string
+	Field      string
+	Value      interface{}
+	Message    string
+	Suggestion string
+}
+
+// ConfigDiffReport is the result of comparing configs across environments
+type ConfigDiffReport struct {
+	Configs []EnvironmentConfig
+	Diffs   []ConfigDiff
+	Risks   []PromotionRisk
+	// MissingInProduction lists keys present in a development config but absent in production,
+	// keyed by production config path
+	MissingInProduction map[string][]string
+}
+
+// DetectEnvironment infers the environment from a go-zero config file name
+// (etc/user-api.yaml, etc/user-api-test.yaml, etc/user-api-production.yaml)
+func DetectEnvironment(path string) string {
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	switch {
+	case strings.HasSuffix(name, "-production"), strings.HasSuffix(name, "-prod"),
+		strings.HasSuffix(name, "-pro"), strings.HasSuffix(name, ".prod"):
+		return "production"
+	case strings.HasSuffix(name, "-test"), strings.HasSuffix(name, ".test"):
+		return "test"
+	default:
+		return "development"
+	}
+}
+
+// NormalizeEnvironment maps environment aliases (dev, prod, pro, ...) to the
+// names DetectEnvironment returns
+func NormalizeEnvironment(env string) string {
+	switch strings.ToLower(env) {
+	case "prod", "pro", "production":
+		return "production"
+	case "test":
+		return "test"
+	default:
+		return "development"
+	}
+}
+
+// ConfigServiceName strips the directory, extension and environment suffix
+// from a config file name (etc/user-api-production.yaml -> user-api)
+func ConfigServiceName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	lower := strings.ToLower(name)
+	for _, suffix := range []string{"-production", "-prod", "-pro", ".prod", "-test", ".test", "-dev", ".dev"} {
+		if strings.HasSuffix(lower, suffix) {
+			return name[:len(name)-len(suffix)]
+		}
+	}
+	return name
+}
+
+// DiffConfigs compares environment configs of the same service
+// The first config is the base every other config is compared against
+func DiffConfigs(configs []EnvironmentConfig) *ConfigDiffReport {
+	report := &ConfigDiffReport{
+		Configs:             configs,
+		Diffs:               []ConfigDiff{},
+		Risks:               []PromotionRisk{},
+		MissingInProduction: make(map[string][]string),
+	}
+
+	if len(configs) == 0 {
+		return report
+	}
+
+	flattened := make([]map[string]interface{}, len(configs))
+	for i, cfg := range configs {
+		flattened[i] = FlattenConfig(cfg.Values)
+	}
+
+	base := configs[0]
+	for i := 1; i < len(configs); i++ {
+		report.Diffs = append(report.Diffs, diffFlattened(base, configs[i], flattened[0], flattened[i]))
+	}
+
+	for i, cfg := range configs {
+		if cfg.Environment != "production" {
+			continue
+		}
+
+		report.Risks = append(report.Risks, checkPromotionRisks(cfg.Path, flattened[i])...)
+
+		// Keys defined for development that production silently drops
+		var missing []string
+		for j, dev := range configs {
+			if dev.Environment != "development" {
+				continue
+			}
+			for key := range flattened[j] {
+				if _, ok := flattened[i][key]; !ok && !contains(missing, key) {
+					missing = append(missing, key)
+				}
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			report.MissingInProduction[cfg.Path] = missing
+		}
+	}
+
+	return report
+}
+
+// FlattenConfig converts nested config maps into dotted keys
+// Lists are kept as leaf values so they are compared as a whole
+func FlattenConfig(config map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	flattenInto(flat, "", config)
+	return flat
+}
+
+func flattenInto(flat map[string]interface{}, prefix string, value interface{}) {
+	if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+		for key, item := range m {
+			flattenInto(flat, joinField(prefix, key), item)
+		}
+		return
+	}
+	if prefix != "" {
+		flat[prefix] = value
+	}
+}
+
+func diffFlattened(base, target EnvironmentConfig, baseFlat, targetFlat map[string]interface{}) ConfigDiff {
+	diff := ConfigDiff{
+		BasePath:          base.Path,
+		TargetPath:        target.Path,
+		BaseEnvironment:   base.Environment,
+		TargetEnvironment: target.Environment,
+		Added:             []string{},
+		Removed:           []string{},
+		Changed:           []ConfigChange{},
+	}
+
+	for key, targetValue := range targetFlat {
+		baseValue, ok := baseFlat[key]
+		if !ok {
+			diff.Added = append(diff.Added, key)
+		} else if !configValuesEqual(baseValue, targetValue) {
+			diff.Changed = append(diff.Changed, ConfigChange{Key: key, From: baseValue, To: targetValue})
+		}
+	}
+	for key := range baseFlat {
+		if _, ok := targetFlat[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Key < diff.Changed[j].Key })
+
+	return diff
+}
+
+// checkPromotionRisks flags values that are typical development leftovers in production
+func checkPromotionRisks(path string, flat map[string]interface{}) []PromotionRisk {
+	var risks []PromotionRisk
+
+	if mode, ok := flat["Mode"].(string); ok && mode == "dev" {
+		risks = append(risks, PromotionRisk{
+			Path:       path,
+			Field:      "Mode",
+			Value:      mode,
+			Message:    "development mode in production config",
+			Suggestion: "Set Mode to 'pro' for production",
+		})
+	}
+
+	if level, ok := flat["Log.Level"].(string); ok && strings.EqualFold(level, "debug") {
+		risks = append(risks, PromotionRisk{
+			Path:       path,
+			Field:      "Log.Level",
+			Value:      level,
+			Message:    "debug logging in production config",
+			Suggestion: "Use 'info' or 'error' level for production",
+		})
+	}
+
+	if host, ok := flat["Host"].(string); ok && isLoopbackHost(host) {
+		risks = append(risks, PromotionRisk{
+			Path:       path,
+			Field:      "Host",
+			Value:      host,
+			Message:    "service only listens on loopback in production config",
+			Suggestion: "Use 0.0.0.0 or the instance address",
+		})
+	}
+	if listenOn, ok := flat["ListenOn"].(string); ok {
+		if host, _, found := strings.Cut(listenOn, ":"); found && isLoopbackHost(host) {
+			risks = append(risks, PromotionRisk{
+				Path:       path,
+				Field:      "ListenOn",
+				Value:      listenOn,
+				Message:    "RPC server only listens on loopback in production config",
+				Suggestion: "Use 0.0.0.0:<port> or the instance address",
+			})
+		}
+	}
+
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	// An Etcd section without Hosts configures no cluster, same as an empty list
+	for _, section := range etcdSections(keys) {
+		if _, ok := flat[section+".Hosts"]; !ok {
+			keys = append(keys, section+".Hosts")
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := flat[key]
+
+		if key == "Etcd.Hosts" || strings.HasSuffix(key, ".Etcd.Hosts") {
+			if hosts, ok := value.([]interface{}); !ok || len(hosts) == 0 {
+				risks = append(risks, PromotionRisk{
+					Path:       path,
+					Field:      key,
+					Value:      value,
+					Message:    "etcd hosts list is empty in production config",
+					Suggestion: "Provide the production etcd cluster for service discovery",
+				})
+			}
+		}
+
+		if strings.HasSuffix(key, "AccessExpire") {
+			if seconds, ok := toFloat(value); ok && seconds < minAccessExpireSeconds {
+				risks = append(risks, PromotionRisk{
+					Path:       path,
+					Field:      key,
+					Value:      value,
+					Message:    fmt.Sprintf("access token expires after %vs, shorter than %ds", value, minAccessExpireSeconds),
+					Suggestion: "Use a production token lifetime (e.g., 7200 or 86400 seconds)",
+				})
+			}
+		}
+	}
+
+	return risks
+}
+
+// etcdSections returns the distinct Etcd sections, e.g. Etcd or UserRpc.Etcd,
+// that the flattened keys belong to
+func etcdSections(keys []string) []string {
+	seen := make(map[string]bool)
+	var sections []string
+	for _, key := range keys {
+		parts := strings.Split(key, ".")
+		for i := len(parts) - 1; i >= 0; i-- {
+			if parts[i] != "Etcd" {
+				continue
+			}
+			if section := strings.Join(parts[:i+1], "."); !seen[section] {
+				seen[section] = true
+				sections = append(sections, section)
+			}
+			break
+		}
+	}
+	return sections
+}
+
+func isLoopbackHost(host string) bool {
+	return host == "127.0.0.1" || host == "localhost" || host == "::1"
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func configValuesEqual(a, b interface{}) bool {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return af == bf
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
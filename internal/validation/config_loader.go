@@ -0,0 +1,296 @@
+package validation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvReference records a ${VAR} placeholder found in a config value
+type EnvReference struct {
+	Field    string // dotted path of the config field containing the placeholder
+	Variable string
+	Defined  bool
+}
+
+// ConfigLoadOptions controls how a config file is loaded
+type ConfigLoadOptions struct {
+	ExpandEnv bool              // expand ${VAR} placeholders like go-zero's conf.UseEnv()
+	Env       map[string]string // explicit variables, highest priority
+	EnvFile   string            // optional .env file, overridden by Env
+}
+
+// LoadedConfig is a parsed config file together with env expansion details
+type LoadedConfig struct {
+	Path       string
+	Format     string                 // "yaml", "json" or "toml"
+	Content    []byte                 // file content after env expansion (if enabled)
+	Values     map[string]interface{} // values after env expansion (if enabled)
+	Raw        map[string]interface{} // values as written in the file, nil if unparseable before expansion
+	Positions  ConfigPositions        // line/column of every key in the file
+	References []EnvReference
+}
+
+// UndefinedVariables returns the sorted, de-duplicated names of undefined variables
+func (c *LoadedConfig) UndefinedVariables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, ref := range c.References {
+		if !ref.Defined && !seen[ref.Variable] {
+			seen[ref.Variable] = true
+			names = append(names, ref.Variable)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// envPlaceholderPattern matches $VAR and ${VAR} the same way os.Expand does
+var envPlaceholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ConfigFormat returns the config format for a file extension
+func ConfigFormat(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml":
+		return "yaml", nil
+	case ".json":
+		return "json", nil
+	case ".toml":
+		return "toml", nil
+	default:
+		return "", fmt.Errorf("unsupported config file format: %s (use .yaml, .yml, .json, or .toml)", ext)
+	}
+}
+
+// LoadConfigFile reads and parses a go-zero config file
+// When ExpandEnv is set, placeholders are expanded before parsing, matching conf.UseEnv()
+func LoadConfigFile(path string, opts ConfigLoadOptions) (*LoadedConfig, error) {
+	format, err := ConfigFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	loaded := &LoadedConfig{
+		Path:    path,
+		Format:  format,
+		Content: content,
+	}
+
+	if !opts.ExpandEnv {
+		values, err := ParseConfigContent(content, format)
+		if err != nil {
+			return nil, err
+		}
+		loaded.Values = values
+		loaded.Raw = values
+		loaded.Positions, _ = ParseConfigPositions(content, format)
+		return loaded, nil
+	}
+
+	env, err := buildEnv(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the raw content first so placeholders can be attributed to fields.
+	// Unquoted placeholders (e.g. TOML "Port = ${PORT}") may not parse before
+	// expansion, in which case references are collected without field paths.
+	if raw, err := ParseConfigContent(content, format); err == nil {
+		loaded.Raw = raw
+		loaded.References = collectEnvReferences("", raw, env)
+	} else {
+		loaded.References = collectEnvReferences("", string(content), env)
+	}
+
+	expanded := os.Expand(string(content), func(name string) string {
+		return env[name]
+	})
+	loaded.Content = []byte(expanded)
+
+	values, err := ParseConfigContent(loaded.Content, format)
+	if err != nil {
+		return nil, fmt.Errorf("config is invalid after environment expansion: %w", err)
+	}
+	loaded.Values = values
+
+	// Positions refer to the file as written; fall back to the expanded content
+	// when placeholders make the raw file unparseable
+	if positions, err := ParseConfigPositions(content, format); err == nil {
+		loaded.Positions = positions
+	} else {
+		loaded.Positions, _ = ParseConfigPositions(loaded.Content, format)
+	}
+
+	return loaded, nil
+}
+
+// ParseConfigContent parses config content in the given format into a generic map
+func ParseConfigContent(content []byte, format string) (map[string]interface{}, error) {
+	var config map[string]interface{}
+
+	switch format {
+	case "yaml":
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+	case "json":
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+		}
+	case "toml":
+		if err := toml.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML config: %w", err)
+		}
+		// go-toml decodes integers as int64; validators expect int like yaml.v3
+		normalizeTOMLValues(config)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+
+	return config, nil
+}
+
+// LoadEnvFile parses a .env file of KEY=VALUE lines
+// Blank lines, comments and an optional "export " prefix are supported
+func LoadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid env file line %d: expected KEY=VALUE", lineNum)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	return env, nil
+}
+
+// buildEnv layers process environment, env file and explicit variables
+func buildEnv(opts ConfigLoadOptions) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, found := strings.Cut(kv, "="); found {
+			env[key] = value
+		}
+	}
+
+	if opts.EnvFile != "" {
+		fileEnv, err := LoadEnvFile(opts.EnvFile)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range fileEnv {
+			env[key] = value
+		}
+	}
+
+	for key, value := range opts.Env {
+		env[key] = value
+	}
+
+	return env, nil
+}
+
+// collectEnvReferences walks parsed config values and records every placeholder
+func collectEnvReferences(prefix string, value interface{}, env map[string]string) []EnvReference {
+	var refs []EnvReference
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			refs = append(refs, collectEnvReferences(joinField(prefix, key), v[key], env)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			refs = append(refs, collectEnvReferences(fmt.Sprintf("%s[%d]", prefix, i), item, env)...)
+		}
+	case string:
+		for _, match := range envPlaceholderPattern.FindAllStringSubmatch(v, -1) {
+			name := match[1]
+			if name == "" {
+				name = match[2]
+			}
+			_, defined := env[name]
+			refs = append(refs, EnvReference{
+				Field:    prefix,
+				Variable: name,
+				Defined:  defined,
+			})
+		}
+	}
+
+	return refs
+}
+
+// normalizeTOMLValues converts int64 values produced by go-toml into int
+func normalizeTOMLValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeTOMLValues(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeTOMLValues(item)
+		}
+		return v
+	case int64:
+		return int(v)
+	default:
+		return v
+	}
+}
+
+func joinField(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
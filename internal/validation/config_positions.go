@@ -0,0 +1,139 @@
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a 1-based line and column in a config file
+type Position struct {
+	Line   int
+	Column int
+}
+
+// ConfigPositions maps dotted field paths (e.g. "Etcd.Hosts[0]") to their location
+type ConfigPositions map[string]Position
+
+// ParseConfigPositions records the location of every key in config content.
+// YAML and JSON are parsed into yaml.Node; TOML keys are located line by line.
+func ParseConfigPositions(content []byte, format string) (ConfigPositions, error) {
+	positions := make(ConfigPositions)
+
+	switch format {
+	case "yaml", "json":
+		// yaml.v3 also accepts JSON and reports its positions
+		var root yaml.Node
+		if err := yaml.Unmarshal(content, &root); err != nil {
+			return nil, fmt.Errorf("failed to parse %s config: %w", strings.ToUpper(format), err)
+		}
+		if len(root.Content) > 0 {
+			collectNodePositions("", root.Content[0], positions)
+		}
+	case "toml":
+		collectTOMLPositions(content, positions)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+
+	return positions, nil
+}
+
+// Lookup returns the position of a field, falling back to its closest parent.
+// Missing fields therefore point at the section they belong in; unknown
+// top-level fields return false.
+func (p ConfigPositions) Lookup(field string) (Position, bool) {
+	for field != "" {
+		if pos, ok := p[field]; ok {
+			return pos, true
+		}
+		if idx := strings.LastIndexAny(field, ".["); idx >= 0 {
+			field = field[:idx]
+		} else {
+			field = ""
+		}
+	}
+	return Position{}, false
+}
+
+// Locate fills in Line and Column for every error and warning in the result
+func (r *ConfigValidationResult) Locate(positions ConfigPositions) {
+	for i := range r.Errors {
+		if pos, ok := positions.Lookup(r.Errors[i].Field); ok {
+			r.Errors[i].Line, r.Errors[i].Column = pos.Line, pos.Column
+		}
+	}
+	for i := range r.Warnings {
+		if pos, ok := positions.Lookup(r.Warnings[i].Field); ok {
+			r.Warnings[i].Line, r.Warnings[i].Column = pos.Line, pos.Column
+		}
+	}
+}
+
+func collectNodePositions(prefix string, node *yaml.Node, positions ConfigPositions) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field := joinField(prefix, key.Value)
+			positions[field] = Position{Line: key.Line, Column: key.Column}
+			collectNodePositions(field, value, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			field := fmt.Sprintf("%s[%d]", prefix, i)
+			positions[field] = Position{Line: item.Line, Column: item.Column}
+			collectNodePositions(field, item, positions)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			collectNodePositions(prefix, node.Alias, positions)
+		}
+	}
+}
+
+// collectTOMLPositions handles [table] headers and key = value lines,
+// which covers the configs go-zero services use
+func collectTOMLPositions(content []byte, positions ConfigPositions) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	table := ""
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		column := len(line) - len(strings.TrimLeft(line, " \t")) + 1
+
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "[[") && strings.Contains(trimmed, "]]"):
+			table = tomlKey(trimmed[2:strings.Index(trimmed, "]]")])
+			if _, ok := positions[table]; !ok {
+				positions[table] = Position{Line: lineNum, Column: column}
+			}
+		case strings.HasPrefix(trimmed, "[") && strings.Contains(trimmed, "]"):
+			table = tomlKey(trimmed[1:strings.Index(trimmed, "]")])
+			positions[table] = Position{Line: lineNum, Column: column}
+		default:
+			key, _, found := strings.Cut(trimmed, "=")
+			if !found {
+				continue
+			}
+			positions[joinField(table, tomlKey(key))] = Position{Line: lineNum, Column: column}
+		}
+	}
+}
+
+// tomlKey converts a possibly dotted or quoted TOML key to a field path
+func tomlKey(key string) string {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
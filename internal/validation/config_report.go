@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Diagnostic rule IDs for config validation results
+const (
+	RuleConfigError   = "config-error"
+	RuleConfigWarning = "config-warning"
+)
+
+// ConfigDiagnostic is a located validation finding suitable for editors and CI
+type ConfigDiagnostic struct {
+	RuleID     string `json:"rule_id"`
+	Severity   string `json:"severity"` // "error", "warning" or "note"
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+}
+
+// Diagnostics converts errors and warnings into diagnostics, errors first
+func (r *ConfigValidationResult) Diagnostics() []ConfigDiagnostic {
+	diagnostics := make([]ConfigDiagnostic, 0, len(r.Errors)+len(r.Warnings))
+	for _, err := range r.Errors {
+		diagnostics = append(diagnostics, ConfigDiagnostic{
+			RuleID:   RuleConfigError,
+			Severity: "error",
+			Field:    err.Field,
+			Message:  err.Message,
+			Line:     err.Line,
+			Column:   err.Column,
+		})
+	}
+	for _, warn := range r.Warnings {
+		diagnostics = append(diagnostics, ConfigDiagnostic{
+			RuleID:     RuleConfigWarning,
+			Severity:   "warning",
+			Field:      warn.Field,
+			Message:    warn.Message,
+			Suggestion: warn.Suggestion,
+			Line:       warn.Line,
+			Column:     warn.Column,
+		})
+	}
+	return diagnostics
+}
+
+// SARIF 2.1.0 log, limited to the properties code scanning uses
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// FormatSARIF renders diagnostics for one config file as a SARIF 2.1.0 log.
+// uri should be relative to the repository root so code scanning can map it.
+func FormatSARIF(uri string, diagnostics []ConfigDiagnostic) ([]byte, error) {
+	ruleSet := make(map[string]string)
+	results := make([]sarifResult, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		if _, ok := ruleSet[d.RuleID]; !ok {
+			ruleSet[d.RuleID] = ruleDescription(d)
+		}
+
+		text := d.Field + ": " + d.Message
+		if d.Suggestion != "" {
+			text += ". " + d.Suggestion
+		}
+
+		// SARIF regions are 1-based; diagnostics without a position point at the file start
+		line := d.Line
+		if line < 1 {
+			line = 1
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  d.RuleID,
+			Level:   d.Severity,
+			Message: sarifMessage{Text: text},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: uri},
+					Region:           sarifRegion{StartLine: line, StartColumn: d.Column},
+				},
+			}},
+		})
+	}
+
+	rules := make([]sarifRule, 0, len(ruleSet))
+	for id, description := range ruleSet {
+		rules = append(rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: description}})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "mcp-gozero",
+				InformationURI: "https://github.com/jinguoxing/mcp-gozero",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+func ruleDescription(d ConfigDiagnostic) string {
+	switch d.RuleID {
+	case RuleConfigError:
+		return "go-zero configuration error"
+	case RuleConfigWarning:
+		return "go-zero configuration warning"
+	default:
+		return d.Message
+	}
+}
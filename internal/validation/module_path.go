@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+)
+
+// modulePathElement matches one slash-separated element of a module path
+var modulePathElement = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~-]*$`)
+
+// ValidateModulePath validates a Go module path such as
+// github.com/acme/user: slash-separated elements of letters, digits and
+// ".", "_", "~", "-", without leading, trailing or doubled slashes
+func ValidateModulePath(field, path string) error {
+	if path == "" {
+		return mcperrors.NewValidationError(field, path, "module path cannot be empty", mcperrors.ErrValidationFailed)
+	}
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return mcperrors.NewValidationError(field, path, "module path cannot start or end with '/'", mcperrors.ErrValidationFailed)
+	}
+
+	for _, element := range strings.Split(path, "/") {
+		if !modulePathElement.MatchString(element) || strings.HasSuffix(element, ".") {
+			return mcperrors.NewValidationError(field, path, "module path elements may only contain letters, digits, '.', '_', '~' and '-' and cannot be empty", mcperrors.ErrValidationFailed)
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 // ValidatePath validates a file/directory path
@@ -11,42 +13,38 @@ import (
 func ValidatePath(path string) error {
 	// Check if path is absolute
 	if !filepath.IsAbs(path) {
-		return fmt.Errorf("path must be absolute, got: %s", path)
+		return mcperrors.NewPathError(path, "validate", "path must be absolute", mcperrors.ErrInvalidPath)
 	}
 
 	// Check if parent directory exists and is writable
 	parentDir := filepath.Dir(path)
 	if err := checkWritable(parentDir); err != nil {
-		return fmt.Errorf("parent directory %s is not writable: %w", parentDir, err)
+		return err
 	}
 
 	return nil
 }
 
 // ValidateOutputDir validates an output directory
-// Creates it if it doesn't exist
+// The directory must already exist; it is never created
 func ValidateOutputDir(dir string) error {
 	// Check if absolute
 	if !filepath.IsAbs(dir) {
-		return fmt.Errorf("output directory must be absolute path, got: %s", dir)
+		return mcperrors.NewPathError(dir, "validate", "output directory must be absolute path", mcperrors.ErrInvalidPath)
 	}
 
 	// Check if directory exists
 	info, err := os.Stat(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			// Try to create directory
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return fmt.Errorf("failed to create output directory: %w", err)
-			}
-			return nil
+			return mcperrors.NewPathError(dir, "stat", "output directory does not exist", mcperrors.Wrap(mcperrors.ErrInvalidPath, err))
 		}
-		return fmt.Errorf("failed to check output directory: %w", err)
+		return mcperrors.NewPathError(dir, "stat", "failed to check output directory", mcperrors.Wrap(mcperrors.ErrInvalidPath, err))
 	}
 
 	// Check if it's a directory
 	if !info.IsDir() {
-		return fmt.Errorf("output path exists but is not a directory: %s", dir)
+		return mcperrors.NewPathError(dir, "validate", "output path exists but is not a directory", mcperrors.ErrInvalidPath)
 	}
 
 	// Check if writable
@@ -58,7 +56,7 @@ func checkWritable(dir string) error {
 	// Try to create a temp file
 	tempFile, err := os.CreateTemp(dir, ".mcp-zero-test-*")
 	if err != nil {
-		return fmt.Errorf("directory is not writable")
+		return mcperrors.NewPathError(dir, "write", "directory is not writable", mcperrors.Wrap(mcperrors.ErrInvalidPath, err))
 	}
 
 	// Clean up
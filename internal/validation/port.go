@@ -3,6 +3,9 @@ package validation
 import (
 	"fmt"
 	"net"
+	"strconv"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 )
 
 // ValidatePort validates a port number
@@ -10,12 +13,12 @@ import (
 func ValidatePort(port int) error {
 	// Check valid range (avoid privileged ports)
 	if port < 1024 || port > 65535 {
-		return fmt.Errorf("port must be between 1024 and 65535, got %d", port)
+		return mcperrors.NewValidationError("port", strconv.Itoa(port), fmt.Sprintf("port must be between 1024 and 65535, got %d", port), mcperrors.ErrInvalidPort)
 	}
 
 	// Check if port is already in use
 	if isPortInUse(port) {
-		return fmt.Errorf("port %d is already in use", port)
+		return mcperrors.NewValidationError("port", strconv.Itoa(port), fmt.Sprintf("port %d is already in use", port), mcperrors.ErrInvalidPort)
 	}
 
 	return nil
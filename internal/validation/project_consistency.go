@@ -0,0 +1,427 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Consistency issue kinds
+const (
+	IssueDuplicateListener     = "duplicate_listener"
+	IssueUnknownEtcdKey        = "unknown_etcd_key"
+	IssueDanglingEndpoint      = "dangling_endpoint"
+	IssueInconsistentEtcdHosts = "inconsistent_etcd_hosts"
+	IssueInconsistentRedis     = "inconsistent_redis"
+)
+
+// ConfigLocation points at a field in one of the project's config files
+type ConfigLocation struct {
+	Path  string
+	Field string
+	Value string
+}
+
+// ConsistencyIssue is a problem that only shows up when service configs are compared
+type ConsistencyIssue struct {
+	Kind        string
+	Severity    string // "error" or "warning"
+	Environment string
+	Message     string
+	Locations   []ConfigLocation
+	Suggestion  string
+}
+
+// listener is a server address declared by Port (API) or ListenOn (RPC)
+type listener struct {
+	service string
+	host    string
+	port    int
+	loc     ConfigLocation
+}
+
+// rpcClient is a zrpc.RpcClientConf found inside a service config
+type rpcClient struct {
+	etcdKey   string
+	endpoints []string
+	loc       ConfigLocation
+}
+
+// hostSet is a set of hosts (etcd or redis) declared at one location
+type hostSet struct {
+	hosts []string
+	loc   ConfigLocation
+}
+
+// CheckProjectConsistency compares the configs of all services in a project.
+// Configs are grouped by environment, since dev and production variants of the
+// same service are never deployed together.
+func CheckProjectConsistency(configs []EnvironmentConfig) []ConsistencyIssue {
+	byEnv := make(map[string][]EnvironmentConfig)
+	var envs []string
+	for _, cfg := range configs {
+		if !isServiceConfig(cfg.Values) {
+			continue
+		}
+		if _, ok := byEnv[cfg.Environment]; !ok {
+			envs = append(envs, cfg.Environment)
+		}
+		byEnv[cfg.Environment] = append(byEnv[cfg.Environment], cfg)
+	}
+	sort.Strings(envs)
+
+	var issues []ConsistencyIssue
+	for _, env := range envs {
+		issues = append(issues, checkEnvironment(env, byEnv[env])...)
+	}
+	return issues
+}
+
+func checkEnvironment(env string, configs []EnvironmentConfig) []ConsistencyIssue {
+	var (
+		listeners  []listener
+		clients    []rpcClient
+		serverKeys = make(map[string]ConfigLocation)
+		etcdHosts  []hostSet
+		redisHosts = make(map[string][]hostSet)
+	)
+
+	for _, cfg := range configs {
+		service := serviceName(cfg)
+
+		if l, ok := collectListener(cfg, service); ok {
+			listeners = append(listeners, l)
+		}
+
+		// A top-level Etcd block with ListenOn is the RPC server registration
+		if _, isServer := cfg.Values["ListenOn"]; isServer {
+			if etcd, ok := cfg.Values["Etcd"].(map[string]interface{}); ok {
+				if key, ok := etcd["Key"].(string); ok && key != "" {
+					serverKeys[key] = ConfigLocation{Path: cfg.Path, Field: "Etcd.Key", Value: key}
+				}
+			}
+		}
+
+		walkConfig(cfg.Values, "", func(field string, m map[string]interface{}) {
+			if field != "" {
+				if client, ok := asRPCClient(cfg.Path, field, m); ok {
+					clients = append(clients, client)
+				}
+			}
+			if etcd, ok := m["Etcd"].(map[string]interface{}); ok {
+				if hosts := stringList(etcd["Hosts"]); len(hosts) > 0 {
+					etcdHosts = append(etcdHosts, hostSet{
+						hosts: hosts,
+						loc:   ConfigLocation{Path: cfg.Path, Field: joinField(field, "Etcd.Hosts"), Value: strings.Join(hosts, ",")},
+					})
+				}
+			}
+			for key, value := range m {
+				if !strings.Contains(strings.ToLower(key), "redis") {
+					continue
+				}
+				if hosts := redisHostList(value); len(hosts) > 0 {
+					redisHosts[key] = append(redisHosts[key], hostSet{
+						hosts: hosts,
+						loc:   ConfigLocation{Path: cfg.Path, Field: joinField(field, key), Value: strings.Join(hosts, ",")},
+					})
+				}
+			}
+		})
+	}
+
+	var issues []ConsistencyIssue
+	issues = append(issues, checkDuplicateListeners(env, listeners)...)
+	issues = append(issues, checkRPCClients(env, clients, serverKeys, listeners)...)
+
+	if issue, ok := checkSharedHosts(env, etcdHosts, IssueInconsistentEtcdHosts,
+		"services use different etcd clusters",
+		"Point every service at the same Etcd.Hosts so RPC servers and clients can discover each other"); ok {
+		issues = append(issues, issue)
+	}
+
+	redisKeys := make([]string, 0, len(redisHosts))
+	for key := range redisHosts {
+		redisKeys = append(redisKeys, key)
+	}
+	sort.Strings(redisKeys)
+	for _, key := range redisKeys {
+		if issue, ok := checkSharedHosts(env, redisHosts[key], IssueInconsistentRedis,
+			fmt.Sprintf("services configure different %s clusters", key),
+			fmt.Sprintf("Use the same %s hosts everywhere, or rename the field if the clusters are intentionally separate", key)); ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// checkDuplicateListeners flags two services that bind the same port on overlapping hosts
+func checkDuplicateListeners(env string, listeners []listener) []ConsistencyIssue {
+	var issues []ConsistencyIssue
+	reported := make(map[string]bool)
+
+	for i := 0; i < len(listeners); i++ {
+		for j := i + 1; j < len(listeners); j++ {
+			a, b := listeners[i], listeners[j]
+			if a.port != b.port || a.service == b.service || !hostsOverlap(a.host, b.host) {
+				continue
+			}
+			id := fmt.Sprintf("%d|%s|%s", a.port, a.loc.Path, b.loc.Path)
+			if reported[id] {
+				continue
+			}
+			reported[id] = true
+			issues = append(issues, ConsistencyIssue{
+				Kind:        IssueDuplicateListener,
+				Severity:    "error",
+				Environment: env,
+				Message:     fmt.Sprintf("services '%s' and '%s' both listen on port %d", a.service, b.service, a.port),
+				Locations:   []ConfigLocation{a.loc, b.loc},
+				Suggestion:  "Give each service a unique port",
+			})
+		}
+	}
+
+	return issues
+}
+
+// checkRPCClients flags client confs that cannot reach any server in the project
+func checkRPCClients(env string, clients []rpcClient, serverKeys map[string]ConfigLocation, listeners []listener) []ConsistencyIssue {
+	var issues []ConsistencyIssue
+
+	knownKeys := make([]string, 0, len(serverKeys))
+	for key := range serverKeys {
+		knownKeys = append(knownKeys, key)
+	}
+	sort.Strings(knownKeys)
+
+	for _, client := range clients {
+		if client.etcdKey != "" {
+			if _, ok := serverKeys[client.etcdKey]; !ok {
+				suggestion := "No RPC server in this project registers this key; check for a typo or an external service"
+				if len(knownKeys) > 0 {
+					suggestion = fmt.Sprintf("Registered keys: %s", strings.Join(knownKeys, ", "))
+				}
+				issues = append(issues, ConsistencyIssue{
+					Kind:        IssueUnknownEtcdKey,
+					Severity:    "warning",
+					Environment: env,
+					Message:     fmt.Sprintf("RPC client Etcd.Key '%s' does not match any RPC server", client.etcdKey),
+					Locations:   []ConfigLocation{client.loc},
+					Suggestion:  suggestion,
+				})
+			}
+		}
+
+		for _, endpoint := range client.endpoints {
+			port, ok := endpointPort(endpoint)
+			if !ok || portInUse(port, listeners) {
+				continue
+			}
+			issues = append(issues, ConsistencyIssue{
+				Kind:        IssueDanglingEndpoint,
+				Severity:    "warning",
+				Environment: env,
+				Message:     fmt.Sprintf("RPC client endpoint '%s' points to port %d, which no service listens on", endpoint, port),
+				Locations:   []ConfigLocation{{Path: client.loc.Path, Field: joinField(client.loc.Field, "Endpoints"), Value: endpoint}},
+				Suggestion:  "Update Endpoints to the server's ListenOn port, or use Etcd discovery",
+			})
+		}
+	}
+
+	return issues
+}
+
+// checkSharedHosts reports when the same kind of cluster is configured with different hosts
+func checkSharedHosts(env string, sets []hostSet, kind, message, suggestion string) (ConsistencyIssue, bool) {
+	variants := make(map[string]bool)
+	for _, set := range sets {
+		variants[hostSetKey(set.hosts)] = true
+	}
+	if len(variants) < 2 {
+		return ConsistencyIssue{}, false
+	}
+
+	locations := make([]ConfigLocation, 0, len(sets))
+	for _, set := range sets {
+		locations = append(locations, set.loc)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].Value != locations[j].Value {
+			return locations[i].Value < locations[j].Value
+		}
+		return locations[i].Path < locations[j].Path
+	})
+
+	return ConsistencyIssue{
+		Kind:        kind,
+		Severity:    "warning",
+		Environment: env,
+		Message:     fmt.Sprintf("%s (%d variants)", message, len(variants)),
+		Locations:   locations,
+		Suggestion:  suggestion,
+	}, true
+}
+
+func collectListener(cfg EnvironmentConfig, service string) (listener, bool) {
+	if listenOn, ok := cfg.Values["ListenOn"].(string); ok {
+		host, portStr, err := net.SplitHostPort(listenOn)
+		if err != nil {
+			return listener{}, false
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return listener{}, false
+		}
+		return listener{
+			service: service,
+			host:    host,
+			port:    port,
+			loc:     ConfigLocation{Path: cfg.Path, Field: "ListenOn", Value: listenOn},
+		}, true
+	}
+
+	if port, ok := toFloat(cfg.Values["Port"]); ok {
+		host, _ := cfg.Values["Host"].(string)
+		return listener{
+			service: service,
+			host:    host,
+			port:    int(port),
+			loc:     ConfigLocation{Path: cfg.Path, Field: "Port", Value: strconv.Itoa(int(port))},
+		}, true
+	}
+
+	return listener{}, false
+}
+
+// asRPCClient recognizes zrpc.RpcClientConf blocks by their Etcd.Key, Endpoints or Target
+func asRPCClient(path, field string, m map[string]interface{}) (rpcClient, bool) {
+	client := rpcClient{loc: ConfigLocation{Path: path, Field: field}}
+
+	if etcd, ok := m["Etcd"].(map[string]interface{}); ok {
+		client.etcdKey, _ = etcd["Key"].(string)
+	}
+	client.endpoints = stringList(m["Endpoints"])
+
+	if client.etcdKey == "" && len(client.endpoints) == 0 {
+		return rpcClient{}, false
+	}
+	if client.etcdKey != "" {
+		client.loc.Field = joinField(field, "Etcd.Key")
+		client.loc.Value = client.etcdKey
+	}
+	return client, true
+}
+
+// walkConfig calls fn for every map in the config, including the root
+func walkConfig(value interface{}, field string, fn func(field string, m map[string]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fn(field, v)
+		for key, item := range v {
+			walkConfig(item, joinField(field, key), fn)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walkConfig(item, fmt.Sprintf("%s[%d]", field, i), fn)
+		}
+	}
+}
+
+// redisHostList reads redis.RedisConf (Host) and cache.CacheConf (list of nodes)
+func redisHostList(value interface{}) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if host, ok := v["Host"].(string); ok && host != "" {
+			return []string{host}
+		}
+	case []interface{}:
+		var hosts []string
+		for _, item := range v {
+			if node, ok := item.(map[string]interface{}); ok {
+				if host, ok := node["Host"].(string); ok && host != "" {
+					hosts = append(hosts, host)
+				}
+			}
+		}
+		return hosts
+	}
+	return nil
+}
+
+func isServiceConfig(values map[string]interface{}) bool {
+	_, hasName := values["Name"]
+	_, hasPort := values["Port"]
+	_, hasListenOn := values["ListenOn"]
+	return hasName || hasPort || hasListenOn
+}
+
+func serviceName(cfg EnvironmentConfig) string {
+	if name, ok := cfg.Values["Name"].(string); ok && name != "" {
+		return name
+	}
+	return strings.TrimSuffix(filepath.Base(cfg.Path), filepath.Ext(cfg.Path))
+}
+
+func stringList(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// hostsOverlap reports whether two bind addresses can collide on one machine
+func hostsOverlap(a, b string) bool {
+	a, b = normalizeBindHost(a), normalizeBindHost(b)
+	return a == "" || b == "" || a == b
+}
+
+// normalizeBindHost returns "" for wildcard addresses
+func normalizeBindHost(host string) string {
+	switch host {
+	case "", "0.0.0.0", "::", "[::]":
+		return ""
+	case "localhost", "::1":
+		return "127.0.0.1"
+	default:
+		return host
+	}
+}
+
+func endpointPort(endpoint string) (int, bool) {
+	if idx := strings.Index(endpoint, "://"); idx >= 0 {
+		endpoint = endpoint[idx+3:]
+	}
+	_, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return 0, false
+	}
+	port, err := strconv.Atoi(portStr)
+	return port, err == nil
+}
+
+func portInUse(port int, listeners []listener) bool {
+	for _, l := range listeners {
+		if l.port == port {
+			return true
+		}
+	}
+	return false
+}
+
+func hostSetKey(hosts []string) string {
+	sorted := append([]string(nil), hosts...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
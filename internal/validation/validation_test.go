@@ -3,6 +3,7 @@ package validation_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jinguoxing/mcp-gozero/internal/validation"
@@ -98,6 +99,33 @@ func TestValidatePort(t *testing.T) {
 	}
 }
 
+func TestValidateModulePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"hosted", "github.com/acme/user", false},
+		{"single element", "user", false},
+		{"dashes and dots", "gitlab.acme.io/platform/user-api.v2", false},
+		{"empty", "", true},
+		{"leading slash", "/github.com/acme/user", true},
+		{"trailing slash", "github.com/acme/user/", true},
+		{"double slash", "github.com//user", true},
+		{"space", "github.com/acme/my user", true},
+		{"trailing dot", "github.com/acme/user.", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validation.ValidateModulePath("new_module", tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateModulePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestSuggestAvailablePort(t *testing.T) {
 	// Test that it returns a port in the expected range
 	port, err := validation.SuggestAvailablePort(9000)
@@ -160,11 +188,11 @@ func TestValidateOutputDir(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "non-existent directory (should create)",
+			name: "non-existent directory",
 			setup: func() string {
 				return filepath.Join(tmpDir, "newdir")
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
 			name: "relative path",
@@ -192,10 +220,10 @@ func TestValidateOutputDir(t *testing.T) {
 				t.Errorf("ValidateOutputDir(%q) error = %v, wantErr %v", dir, err, tt.wantErr)
 			}
 
-			// If successful and directory was created, verify it exists
-			if err == nil && tt.name == "non-existent directory (should create)" {
-				if _, err := os.Stat(dir); os.IsNotExist(err) {
-					t.Errorf("ValidateOutputDir should have created directory %q", dir)
+			// Validation must never create the directory
+			if tt.name == "non-existent directory" {
+				if _, err := os.Stat(dir); !os.IsNotExist(err) {
+					t.Errorf("ValidateOutputDir must not create directory %q", dir)
 				}
 			}
 		})
@@ -261,3 +289,314 @@ func TestEnsureDirectoryExists(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tomlPath := filepath.Join(tmpDir, "user-api.toml")
+	tomlContent := "Name = \"user-api\"\nHost = \"0.0.0.0\"\nPort = 8888\n\n[Log]\nMode = \"console\"\n"
+	if err := os.WriteFile(tomlPath, []byte(tomlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := validation.LoadConfigFile(tomlPath, validation.ConfigLoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadConfigFile() failed: %v", err)
+	}
+	if loaded.Format != "toml" {
+		t.Errorf("Format = %q, want toml", loaded.Format)
+	}
+	if port, ok := loaded.Values["Port"].(int); !ok || port != 8888 {
+		t.Errorf("Port = %#v, want int 8888", loaded.Values["Port"])
+	}
+	if result := validation.ValidateAPIConfig(loaded.Values); !result.Valid {
+		t.Errorf("TOML config should be valid, got errors: %v", result.Errors)
+	}
+
+	if _, err := validation.LoadConfigFile(filepath.Join(tmpDir, "config.ini"), validation.ConfigLoadOptions{}); err == nil {
+		t.Error("LoadConfigFile() should reject unsupported formats")
+	}
+}
+
+func TestLoadConfigFileExpandEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configPath := filepath.Join(tmpDir, "user-api.yaml")
+	configContent := "Name: user-api\nHost: 0.0.0.0\nPort: ${API_PORT}\nMysql:\n  DataSource: ${MYSQL_DSN}\nRedis:\n  Pass: ${MCP_ZERO_UNDEFINED_PASS}\n"
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	envPath := filepath.Join(tmpDir, ".env")
+	envContent := "# local overrides\nexport API_PORT=9000\nMYSQL_DSN=\"root:pass@tcp(127.0.0.1:3306)/user\"\n"
+	if err := os.WriteFile(envPath, []byte(envContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := validation.LoadConfigFile(configPath, validation.ConfigLoadOptions{
+		ExpandEnv: true,
+		Env:       map[string]string{"API_PORT": "8888"},
+		EnvFile:   envPath,
+	})
+	if err != nil {
+		t.Fatalf("LoadConfigFile() failed: %v", err)
+	}
+
+	// Explicit variables take priority over the env file
+	if port, ok := loaded.Values["Port"].(int); !ok || port != 8888 {
+		t.Errorf("Port = %#v, want int 8888", loaded.Values["Port"])
+	}
+
+	mysql, _ := loaded.Values["Mysql"].(map[string]interface{})
+	if dsn := mysql["DataSource"]; dsn != "root:pass@tcp(127.0.0.1:3306)/user" {
+		t.Errorf("Mysql.DataSource = %v, want value from env file", dsn)
+	}
+
+	undefined := loaded.UndefinedVariables()
+	if len(undefined) != 1 || undefined[0] != "MCP_ZERO_UNDEFINED_PASS" {
+		t.Errorf("UndefinedVariables() = %v, want [MCP_ZERO_UNDEFINED_PASS]", undefined)
+	}
+
+	for _, ref := range loaded.References {
+		if ref.Variable == "MCP_ZERO_UNDEFINED_PASS" && ref.Field != "Redis.Pass" {
+			t.Errorf("undefined reference field = %q, want Redis.Pass", ref.Field)
+		}
+	}
+}
+
+func TestDetectEnvironment(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"etc/user-api.yaml", "development"},
+		{"etc/user-api-test.yaml", "test"},
+		{"etc/user-api-production.yaml", "production"},
+		{"etc/user-api-prod.toml", "production"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := validation.DetectEnvironment(tt.path); got != tt.want {
+				t.Errorf("DetectEnvironment(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffConfigs(t *testing.T) {
+	dev := validation.EnvironmentConfig{
+		Path:        "etc/user-api.yaml",
+		Environment: "development",
+		Values: map[string]interface{}{
+			"Name": "user-api",
+			"Host": "0.0.0.0",
+			"Port": 8888,
+			"Log":  map[string]interface{}{"Level": "debug"},
+			"Auth": map[string]interface{}{"AccessSecret": "dev", "AccessExpire": 86400},
+		},
+	}
+	prod := validation.EnvironmentConfig{
+		Path:        "etc/user-api-production.yaml",
+		Environment: "production",
+		Values: map[string]interface{}{
+			"Name": "user-api",
+			"Host": "127.0.0.1",
+			"Port": 8888,
+			"Mode": "dev",
+			"Log":  map[string]interface{}{"Level": "debug"},
+			"Auth": map[string]interface{}{"AccessExpire": 60},
+			"Etcd": map[string]interface{}{"Hosts": []interface{}{}, "Key": "user.rpc"},
+		},
+	}
+
+	report := validation.DiffConfigs([]validation.EnvironmentConfig{dev, prod})
+
+	if len(report.Diffs) != 1 {
+		t.Fatalf("expected 1 diff, got %d", len(report.Diffs))
+	}
+	diff := report.Diffs[0]
+
+	if strings.Join(diff.Added, ",") != "Etcd.Hosts,Etcd.Key,Mode" {
+		t.Errorf("Added = %v", diff.Added)
+	}
+	if strings.Join(diff.Removed, ",") != "Auth.AccessSecret" {
+		t.Errorf("Removed = %v", diff.Removed)
+	}
+	if len(diff.Changed) != 2 || diff.Changed[0].Key != "Auth.AccessExpire" || diff.Changed[1].Key != "Host" {
+		t.Errorf("Changed = %v", diff.Changed)
+	}
+
+	riskFields := make(map[string]bool)
+	for _, risk := range report.Risks {
+		riskFields[risk.Field] = true
+	}
+	for _, field := range []string{"Mode", "Log.Level", "Host", "Etcd.Hosts", "Auth.AccessExpire"} {
+		if !riskFields[field] {
+			t.Errorf("expected promotion risk for %s, got %v", field, report.Risks)
+		}
+	}
+
+	missing := report.MissingInProduction[prod.Path]
+	if len(missing) != 1 || missing[0] != "Auth.AccessSecret" {
+		t.Errorf("MissingInProduction = %v, want [Auth.AccessSecret]", missing)
+	}
+}
+
+func TestDiffConfigsMissingEtcdHosts(t *testing.T) {
+	dev := validation.EnvironmentConfig{
+		Path:        "etc/user-rpc.yaml",
+		Environment: "development",
+		Values: map[string]interface{}{
+			"Name":     "user.rpc",
+			"ListenOn": "0.0.0.0:9090",
+			"Etcd":     map[string]interface{}{"Hosts": []interface{}{"127.0.0.1:2379"}, "Key": "user.rpc"},
+		},
+	}
+	prod := validation.EnvironmentConfig{
+		Path:        "etc/user-rpc-production.yaml",
+		Environment: "production",
+		Values: map[string]interface{}{
+			"Name":     "user.rpc",
+			"Mode":     "pro",
+			"ListenOn": "0.0.0.0:9090",
+			"Etcd":     map[string]interface{}{"Key": "user.rpc"},
+			"OrderRpc": map[string]interface{}{"Etcd": map[string]interface{}{"Key": "order.rpc"}},
+		},
+	}
+
+	report := validation.DiffConfigs([]validation.EnvironmentConfig{dev, prod})
+
+	riskFields := make(map[string]bool)
+	for _, risk := range report.Risks {
+		riskFields[risk.Field] = true
+	}
+	for _, field := range []string{"Etcd.Hosts", "OrderRpc.Etcd.Hosts"} {
+		if !riskFields[field] {
+			t.Errorf("expected promotion risk for missing %s, got %v", field, report.Risks)
+		}
+	}
+	if riskFields["Hosts"] {
+		t.Errorf("only Etcd sections should be checked, got %v", report.Risks)
+	}
+}
+
+func TestParseConfigPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		content  string
+		field    string
+		wantLine int
+		wantCol  int
+	}{
+		{"yaml nested", "yaml", "Name: api\nLog:\n  Mode: stdout\n", "Log.Mode", 3, 3},
+		{"yaml list item", "yaml", "Etcd:\n  Hosts:\n    - 127.0.0.1:2379\n", "Etcd.Hosts[0]", 3, 7},
+		{"json", "json", "{\n  \"Name\": \"api\",\n  \"Port\": 0\n}\n", "Port", 3, 3},
+		{"toml table", "toml", "Name = \"api\"\n\n[Log]\n  Mode = \"stdout\"\n", "Log.Mode", 4, 3},
+		{"missing field falls back to parent", "yaml", "Name: api\nLog:\n  Level: info\n", "Log.Mode", 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions, err := validation.ParseConfigPositions([]byte(tt.content), tt.format)
+			if err != nil {
+				t.Fatalf("ParseConfigPositions() error = %v", err)
+			}
+			pos, ok := positions.Lookup(tt.field)
+			if !ok || pos.Line != tt.wantLine || pos.Column != tt.wantCol {
+				t.Errorf("Lookup(%q) = %+v, %v; want line %d column %d", tt.field, pos, ok, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestFormatSARIF(t *testing.T) {
+	content := []byte("Name: api\nHost: 0.0.0.0\nPort: 70000\nLog:\n  Mode: stdout\n")
+	values, _ := validation.ParseConfigContent(content, "yaml")
+	positions, _ := validation.ParseConfigPositions(content, "yaml")
+
+	result := validation.ValidateAPIConfig(values)
+	result.Locate(positions)
+
+	diagnostics := result.Diagnostics()
+	if len(diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %+v", diagnostics)
+	}
+	if diagnostics[0].Field != "Port" || diagnostics[0].Line != 3 || diagnostics[0].Severity != "error" {
+		t.Errorf("unexpected port diagnostic: %+v", diagnostics[0])
+	}
+	if diagnostics[1].Field != "Log.Mode" || diagnostics[1].Line != 5 || diagnostics[1].Column != 3 {
+		t.Errorf("unexpected log diagnostic: %+v", diagnostics[1])
+	}
+
+	report, err := validation.FormatSARIF("etc/api.yaml", diagnostics)
+	if err != nil {
+		t.Fatalf("FormatSARIF() error = %v", err)
+	}
+	for _, want := range []string{`"version": "2.1.0"`, `"uri": "etc/api.yaml"`, `"startLine": 3`, `"ruleId": "config-error"`} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("SARIF report missing %s:\n%s", want, report)
+		}
+	}
+}
+
+func TestCheckProjectConsistency(t *testing.T) {
+	userRPC := validation.EnvironmentConfig{
+		Path:        "user/rpc/etc/user.yaml",
+		Environment: "development",
+		Values: map[string]interface{}{
+			"Name":     "user.rpc",
+			"ListenOn": "0.0.0.0:8080",
+			"Etcd":     map[string]interface{}{"Hosts": []interface{}{"127.0.0.1:2379"}, "Key": "user.rpc"},
+			"CacheRedis": []interface{}{
+				map[string]interface{}{"Host": "127.0.0.1:6379", "Type": "node"},
+			},
+		},
+	}
+	orderAPI := validation.EnvironmentConfig{
+		Path:        "order/api/etc/order-api.yaml",
+		Environment: "development",
+		Values: map[string]interface{}{
+			"Name": "order-api",
+			"Host": "0.0.0.0",
+			"Port": 8080,
+			"UserRpc": map[string]interface{}{
+				"Etcd": map[string]interface{}{"Hosts": []interface{}{"127.0.0.1:2380"}, "Key": "users.rpc"},
+			},
+			"PayRpc": map[string]interface{}{
+				"Endpoints": []interface{}{"127.0.0.1:9090"},
+			},
+			"CacheRedis": []interface{}{
+				map[string]interface{}{"Host": "127.0.0.1:6380", "Type": "node"},
+			},
+		},
+	}
+	// Production variant of the same port must not clash with development configs
+	orderProd := validation.EnvironmentConfig{
+		Path:        "order/api/etc/order-api-production.yaml",
+		Environment: "production",
+		Values:      map[string]interface{}{"Name": "order-api", "Host": "0.0.0.0", "Port": 8080},
+	}
+
+	issues := validation.CheckProjectConsistency([]validation.EnvironmentConfig{userRPC, orderAPI, orderProd})
+
+	kinds := make(map[string]int)
+	for _, issue := range issues {
+		kinds[issue.Kind]++
+		if issue.Environment != "development" {
+			t.Errorf("unexpected issue in %s: %+v", issue.Environment, issue)
+		}
+	}
+	for _, kind := range []string{
+		validation.IssueDuplicateListener,
+		validation.IssueUnknownEtcdKey,
+		validation.IssueDanglingEndpoint,
+		validation.IssueInconsistentEtcdHosts,
+		validation.IssueInconsistentRedis,
+	} {
+		if kinds[kind] != 1 {
+			t.Errorf("expected one %s issue, got %d (%+v)", kind, kinds[kind], issues)
+		}
+	}
+}
@@ -6,8 +6,18 @@ import (
 	"fmt"
 	"log"
 	"os"
-
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	"github.com/jinguoxing/mcp-gozero/internal/doctor"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/serverconfig"
+	"github.com/jinguoxing/mcp-gozero/prompts"
+	"github.com/jinguoxing/mcp-gozero/resources"
 	"github.com/jinguoxing/mcp-gozero/tools"
+	"github.com/jinguoxing/mcp-gozero/transport"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -19,6 +29,15 @@ const (
 func main() {
 	// Define command line flags
 	version := flag.Bool("version", false, "Print version information")
+	runDoctor := flag.Bool("doctor", false, "Check the toolchain and environment, print a pass/fail checklist with fixes and exit (status 1 on failures)")
+	transportName := flag.String("transport", transport.Stdio, "Transport to serve MCP over: stdio or http")
+	listen := flag.String("listen", transport.DefaultListenAddr, "Listen address for the http transport")
+	authToken := flag.String("auth-token", "", "Bearer token required by the http transport (default $MCP_GOZERO_AUTH_TOKEN)")
+	metricsListen := flag.String("metrics-listen", "", "Serve Prometheus metrics on this address (e.g. 127.0.0.1:9090); disabled when empty")
+	sessionTimeout := flag.Duration("session-timeout", transport.DefaultSessionTimeout, "Close idle http sessions after this duration (0 disables)")
+	configPath := flag.String("config", "", "Server config file (default $"+serverconfig.EnvConfig+", else ./"+serverconfig.FileName+" over the user config dir)")
+	auditLog := flag.String("audit-log", "", "Append file writes and commands of every tool call to this JSONL file (default audit.path in "+serverconfig.FileName+")")
+	allowedRoots := flag.String("allowed-roots", "", "Directories tools may access besides the client's MCP roots, separated by '"+string(filepath.ListSeparator)+"' (default $"+sandbox.EnvAllowedRoots+")")
 	flag.Parse()
 
 	// Handle version flag
@@ -27,74 +46,116 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Create MCP server
+	if err := transport.ValidateTransport(*transportName); err != nil {
+		log.Fatal(err)
+	}
+	if *authToken == "" {
+		*authToken = os.Getenv("MCP_GOZERO_AUTH_TOKEN")
+	}
+
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("Failed to resolve working directory: %v", err)
+	}
+
+	// Load team-wide defaults; --allowed-roots replaces the configured allow-list
+	if *configPath == "" {
+		*configPath = os.Getenv(serverconfig.EnvConfig)
+	}
+	cfg, err := serverconfig.Load(*configPath, projectRoot)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, warning := range cfg.Warnings {
+		log.Printf("Warning: %s", warning)
+	}
+	if err := tools.ApplyConfig(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Check the environment with the configured goctl and protoc paths
+	if *runDoctor {
+		checks := doctor.Run(context.Background(), doctor.Options{Dirs: []string{projectRoot}})
+		fmt.Print(doctor.Format(checks))
+		if doctor.Failed(checks) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if *auditLog == "" {
+		*auditLog = cfg.AuditPath()
+	}
+	if *auditLog != "" {
+		auditFile, err := audit.Open(*auditLog)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer auditFile.Close()
+		tools.Audit = auditFile
+	}
+	if *allowedRoots == "" {
+		*allowedRoots = os.Getenv(sandbox.EnvAllowedRoots)
+	}
+	if *allowedRoots != "" {
+		if err := tools.Sandbox.SetAllowList(filepath.SplitList(*allowedRoots)); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Create MCP server; resource subscriptions enable file change notifications
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    appName,
 		Version: appVersion,
-	}, nil)
-
-	// Register create_api_service tool (T034 - User Story 1)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "create_api_service",
-		Description: "Create a new go-zero API service with proper structure and configuration",
-	}, tools.CreateAPIService)
-
-	// Register generate_api_from_spec tool (T047 - User Story 2)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "generate_api_from_spec",
-		Description: "Generate go-zero API code from API specification file",
-	}, tools.GenerateAPIFromSpec)
-
-	// Register create_rpc_service tool (T058 - User Story 3)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "create_rpc_service",
-		Description: "Create a new go-zero RPC service with protobuf definition",
-	}, tools.CreateRPCService)
-
-	// Register generate_model tool (T071 - User Story 4)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "generate_model",
-		Description: "Generate go-zero database model from table schema",
-	}, tools.GenerateModel)
-
-	// Register create_api_spec tool (T081 - User Story 5)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "create_api_spec",
-		Description: "Create a sample API specification file for go-zero. IMPORTANT: Always define concrete types for request and response - do NOT use 'any' type in .api files as it's not supported by go-zero",
-	}, tools.CreateAPISpec)
-
-	// Register analyze_project tool (T097 - User Story 6)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "analyze_project",
-		Description: "Analyze existing go-zero project structure and dependencies",
-	}, tools.AnalyzeProject)
-
-	// Register validate_config tool (T109 - User Story 7)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "validate_config",
-		Description: "Validate go-zero service configuration file",
-	}, tools.ValidateConfig)
-
-	// Register generate_config_template tool (T109 - User Story 7)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "generate_config_template",
-		Description: "Generate configuration template for go-zero service",
-	}, tools.GenerateConfigTemplate)
-
-	// Register generate_template tool (T123 - User Story 8)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "generate_template",
-		Description: "Generate common code templates (middleware, error handlers, deployment configs)",
-	}, tools.GenerateTemplate)
-
-	// Register query_docs tool (T134 - User Story 9)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "query_docs",
-		Description: "Query go-zero framework documentation and migration guides",
-	}, tools.QueryDocs)
+	}, &mcp.ServerOptions{
+		SubscribeHandler:        resources.Subscribe,
+		UnsubscribeHandler:      resources.Unsubscribe,
+		RootsListChangedHandler: tools.Sandbox.RootsChanged,
+	})
+
+	// Register tools; each declares an output schema for its structured result
+	if err := tools.Register(server); err != nil {
+		log.Fatal(err)
+	}
+
+	// Register project resources and watch the working directory for changes;
+	// SIGINT and SIGTERM cancel the context to shut down gracefully
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	registry := resources.Register(server, projectRoot, tools.Sandbox)
+	go registry.Watch(ctx, resources.DefaultWatchInterval)
+
+	// Register workflow prompts
+	prompts.Register(server, projectRoot, tools.Sandbox)
+
+	// Expose tool metrics to Prometheus when requested
+	if *metricsListen != "" {
+		go func() {
+			if err := transport.ServeMetrics(ctx, *metricsListen, tools.MetricsHandler()); err != nil {
+				log.Printf("Metrics server error: %v", err)
+			}
+		}()
+	}
+
+	if *transportName == transport.HTTP {
+		if *authToken == "" {
+			log.Printf("Warning: http transport running without authentication; set --auth-token or MCP_GOZERO_AUTH_TOKEN")
+		}
+		log.Printf("Serving MCP on http://%s%s", *listen, transport.MCPPath)
+		err := transport.ServeHTTP(ctx, server, transport.HTTPOptions{
+			Addr:           *listen,
+			AuthToken:      *authToken,
+			SessionTimeout: *sessionTimeout,
+			Version:        appVersion,
+		})
+		if err != nil {
+			log.Fatalf("Server error: %v", err)
+		}
+		return
+	}
 
 	// Run the server over stdin/stdout using the StdioTransport
-	if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil && ctx.Err() == nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
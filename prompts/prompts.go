@@ -0,0 +1,296 @@
+// Package prompts registers MCP prompts for guided go-zero workflows
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
+	"github.com/jinguoxing/mcp-gozero/internal/docs"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+)
+
+// projectPathArgument is accepted by every prompt to override the server's project root
+var projectPathArgument = &mcp.PromptArgument{
+	Name:        "project_path",
+	Description: "Project directory to analyze (default: the server's working directory)",
+}
+
+// Register adds the workflow prompts to the server; projects are only
+// analyzed in the directories policy permits to the session
+func Register(server *mcp.Server, projectRoot string, policy *sandbox.Policy) {
+	addPrompt(server, projectRoot, policy, &mcp.Prompt{
+		Name:        "scaffold_crud_service",
+		Title:       "Scaffold CRUD microservice from a table",
+		Description: "Generate an API service with create/read/update/delete/list endpoints backed by a go-zero model",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "table", Description: "Database table name, e.g. user", Required: true},
+			{Name: "service_name", Description: "Name of the API service, e.g. user-api", Required: true},
+			{Name: "source_type", Description: "Model source: mysql, postgresql or ddl (default: ddl)"},
+			{Name: "source", Description: "DSN or DDL file path for the model"},
+			projectPathArgument,
+		},
+	}, scaffoldCRUDService)
+
+	addPrompt(server, projectRoot, policy, &mcp.Prompt{
+		Name:        "migrate_gin_handler",
+		Title:       "Migrate a Gin handler to go-zero",
+		Description: "Convert a Gin handler into an .api route, request/response types and a logic method",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "handler_code", Description: "Source code of the Gin handler and its route registration", Required: true},
+			{Name: "service_name", Description: "Target go-zero API service (default: inferred from the project)"},
+			projectPathArgument,
+		},
+	}, migrateGinHandler)
+
+	addPrompt(server, projectRoot, policy, &mcp.Prompt{
+		Name:        "add_jwt_auth",
+		Title:       "Add JWT auth to an API service",
+		Description: "Protect routes of an API service with go-zero's built-in JWT middleware",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "service_name", Description: "API service to protect", Required: true},
+			{Name: "routes", Description: "Comma-separated routes to protect (default: all routes)"},
+			projectPathArgument,
+		},
+	}, addJWTAuth)
+
+	addPrompt(server, projectRoot, policy, &mcp.Prompt{
+		Name:        "split_monolith",
+		Title:       "Split a monolith API into API + RPC",
+		Description: "Move business domains of an API service into RPC services called through zrpc clients",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "service_name", Description: "Monolith API service to split", Required: true},
+			{Name: "domains", Description: "Comma-separated domains to extract, e.g. user,order (default: inferred from routes)"},
+			projectPathArgument,
+		},
+	}, splitMonolith)
+}
+
+// promptBuilder renders the prompt text for validated arguments and the project analysis
+type promptBuilder func(args map[string]string, project string) (description, text string)
+
+// addPrompt registers a prompt whose handler validates required arguments
+// and wraps the builder output in a single user message
+func addPrompt(server *mcp.Server, projectRoot string, policy *sandbox.Policy, prompt *mcp.Prompt, build promptBuilder) {
+	server.AddPrompt(prompt, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := req.Params.Arguments
+		if args == nil {
+			args = map[string]string{}
+		}
+
+		for _, arg := range prompt.Arguments {
+			if arg.Required && strings.TrimSpace(args[arg.Name]) == "" {
+				return nil, fmt.Errorf("prompt %s: argument '%s' is required", prompt.Name, arg.Name)
+			}
+		}
+
+		projectPath := args["project_path"]
+		if projectPath == "" {
+			projectPath = projectRoot
+		}
+		projectPath, err := policy.Scope(ctx, req.Session).Resolve(projectPath, sandbox.Read)
+		if err != nil {
+			return nil, fmt.Errorf("prompt %s: project_path: %w", prompt.Name, err)
+		}
+
+		description, text := build(args, projectContext(projectPath))
+		return &mcp.GetPromptResult{
+			Description: description,
+			Messages: []*mcp.PromptMessage{{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: text},
+			}},
+		}, nil
+	})
+}
+
+func scaffoldCRUDService(args map[string]string, project string) (string, string) {
+	table := args["table"]
+	service := args["service_name"]
+	sourceType := args["source_type"]
+	if sourceType == "" {
+		sourceType = "ddl"
+	}
+	source := args["source"]
+	if source == "" {
+		source = fmt.Sprintf("<DSN or path to %s.sql>", table)
+	}
+	entity := exportedName(table)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Scaffold a go-zero CRUD microservice named '%s' for the '%s' table.\n\n", service, table))
+	sb.WriteString(project)
+	sb.WriteString(conceptSection("api-definition", "model", "service-context", "cache"))
+	sb.WriteString("## Tool Sequence\n\n")
+	sb.WriteString(fmt.Sprintf("1. `create_api_spec` with service_name=%q and endpoints_json for:\n", service))
+	sb.WriteString(fmt.Sprintf("   - POST /%s (Create%s)\n", table, entity))
+	sb.WriteString(fmt.Sprintf("   - GET /%s/:id (Get%s)\n", table, entity))
+	sb.WriteString(fmt.Sprintf("   - PUT /%s/:id (Update%s)\n", table, entity))
+	sb.WriteString(fmt.Sprintf("   - DELETE /%s/:id (Delete%s)\n", table, entity))
+	sb.WriteString(fmt.Sprintf("   - GET /%s (List%s, with page/size query parameters)\n", table, entity))
+	sb.WriteString("   Define concrete request/response types; never use `any`.\n")
+	sb.WriteString(fmt.Sprintf("2. `generate_api_from_spec` with the generated .api file and output_dir=%q.\n", service))
+	sb.WriteString(fmt.Sprintf("3. `generate_model` with source_type=%q, source=%q, table=%q, output_dir=%q.\n",
+		sourceType, source, table, service+"/model"))
+	sb.WriteString(fmt.Sprintf("4. Add the model to ServiceContext (New%sModel with the Mysql.DataSource and CacheRedis config) and implement each logic method with it.\n", entity))
+	sb.WriteString(fmt.Sprintf("5. `generate_config_template` with service_name=%q, service_type=\"api\", from_struct=true to get a complete config.\n", service))
+	sb.WriteString("6. `validate_config` and `scan_config_secrets` on the result; keep the DSN in ${ENV} variables.\n")
+
+	return fmt.Sprintf("Scaffold CRUD service %s for table %s", service, table), sb.String()
+}
+
+func migrateGinHandler(args map[string]string, project string) (string, string) {
+	service := args["service_name"]
+	if service == "" {
+		service = "<existing or new API service>"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Migrate the following Gin handler to the go-zero API service '%s'.\n\n", service))
+	sb.WriteString("```go\n" + strings.TrimSpace(args["handler_code"]) + "\n```\n\n")
+	sb.WriteString(project)
+	if guide := docs.GetMigrationGuide("gin"); guide != nil {
+		sb.WriteString("## Migration Guide\n\n")
+		sb.WriteString(demoteHeadings(guide.Markdown()))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(conceptSection("api-definition", "error-handling"))
+	sb.WriteString("## Tool Sequence\n\n")
+	sb.WriteString("1. Derive the route, method, request and response types from the handler (c.ShouldBind*, c.Param, c.JSON).\n")
+	sb.WriteString("2. If the service has no .api file yet, `create_api_spec`; otherwise add the route to its existing .api file.\n")
+	sb.WriteString("3. `generate_api_from_spec` to regenerate handlers, logic and types.\n")
+	sb.WriteString("4. Move the handler body into the generated logic method: replace gin.Context with the typed request, return (resp, error) instead of c.JSON, and move dependencies into ServiceContext.\n")
+	sb.WriteString("5. Port Gin middleware on the route to a go-zero middleware (`generate_template` with template_type=\"middleware\").\n")
+
+	return "Migrate a Gin handler to go-zero", sb.String()
+}
+
+func addJWTAuth(args map[string]string, project string) (string, string) {
+	service := args["service_name"]
+	routes := args["routes"]
+	if routes == "" {
+		routes = "all routes"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Add JWT authentication to the go-zero API service '%s' for %s.\n\n", service, routes))
+	sb.WriteString(project)
+	sb.WriteString(conceptSection("jwt", "middleware", "configuration"))
+	sb.WriteString("## Tool Sequence\n\n")
+	sb.WriteString(fmt.Sprintf("1. In the .api file of '%s', move the protected routes into a `@server(jwt: Auth)` block; keep login/public routes outside it.\n", service))
+	sb.WriteString("2. `generate_api_from_spec` to regenerate routes with the JWT middleware and the Auth config struct.\n")
+	sb.WriteString("3. Add to the config:\n")
+	sb.WriteString("   ```yaml\n   Auth:\n     AccessSecret: ${AUTH_ACCESS_SECRET}\n     AccessExpire: 86400\n   ```\n")
+	sb.WriteString("   The secret must be at least 32 random bytes and loaded with conf.UseEnv().\n")
+	sb.WriteString("4. Implement token issuing in the login logic (jwt.NewWithClaims with exp, iat and user claims) and read claims via ctx.Value in protected logic.\n")
+	sb.WriteString("5. `validate_config` with expand_env=true and `scan_config_secrets` to ensure no secret is hard-coded.\n")
+
+	return fmt.Sprintf("Add JWT auth to %s", service), sb.String()
+}
+
+func splitMonolith(args map[string]string, project string) (string, string) {
+	service := args["service_name"]
+	domains := args["domains"]
+	if domains == "" {
+		domains = "the domains inferred from the route prefixes"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Split the monolith API service '%s' into an API gateway plus RPC services for %s.\n\n", service, domains))
+	sb.WriteString(project)
+	sb.WriteString(conceptSection("rpc", "service-context", "configuration"))
+	sb.WriteString("## Tool Sequence\n\n")
+	sb.WriteString(fmt.Sprintf("1. `analyze_project` to list the endpoints of '%s' and group them by domain.\n", service))
+	sb.WriteString("2. For each domain, write a .proto with one rpc per logic method and `create_rpc_service` (service_name=<domain>).\n")
+	sb.WriteString("3. Move the domain's models and logic into the RPC service; the API logic becomes a thin call through the generated zrpc client.\n")
+	sb.WriteString("4. Add a `<Domain>Rpc` zrpc.RpcClientConf to the API Config struct and ServiceContext, with Etcd.Key matching the RPC server's Etcd.Key.\n")
+	sb.WriteString("5. `generate_config_template` with from_struct=true for every service, then `check_config_consistency` to catch port conflicts and unmatched Etcd keys.\n")
+	sb.WriteString("6. `validate_config` for each config before deploying.\n")
+
+	return fmt.Sprintf("Split %s into API + RPC", service), sb.String()
+}
+
+// projectContext summarizes the current project analysis for a prompt
+func projectContext(projectPath string) string {
+	var sb strings.Builder
+	sb.WriteString("## Current Project\n\n")
+
+	analysis, err := analyzer.ScanProject(projectPath)
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("No project could be analyzed at %s (%v). Create new services there.\n\n", projectPath, err))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Path: %s\n", analysis.ProjectPath))
+	if analysis.Summary.GoZeroVersion != "" {
+		sb.WriteString(fmt.Sprintf("go-zero: %s\n", analysis.Summary.GoZeroVersion))
+	}
+	if len(analysis.Services) == 0 {
+		sb.WriteString("Services: none yet\n\n")
+		return sb.String()
+	}
+
+	sb.WriteString("Services:\n")
+	for _, service := range analysis.Services {
+		relPath, _ := filepath.Rel(analysis.ProjectPath, service.SpecFile)
+		switch service.Type {
+		case "api":
+			sb.WriteString(fmt.Sprintf("- %s (api, %s, %d endpoints)\n", service.Name, relPath, len(service.Endpoints)))
+			for _, endpoint := range service.Endpoints {
+				sb.WriteString(fmt.Sprintf("  - %s %s → %s\n", strings.ToUpper(endpoint.Method), endpoint.Path, endpoint.Handler))
+			}
+		case "rpc":
+			sb.WriteString(fmt.Sprintf("- %s (rpc, %s, %d methods)\n", service.Name, relPath, len(service.RPCMethods)))
+		}
+	}
+	if len(analysis.Configs) > 0 {
+		sb.WriteString("Configs:\n")
+		for _, cfg := range analysis.Configs {
+			relPath, _ := filepath.Rel(analysis.ProjectPath, cfg.Path)
+			sb.WriteString(fmt.Sprintf("- %s\n", relPath))
+		}
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// conceptSection embeds concept docs, demoting their headings below the prompt's own
+func conceptSection(names ...string) string {
+	var sb strings.Builder
+	sb.WriteString("## Reference\n\n")
+	for _, name := range names {
+		if concept := docs.GetConceptByName(name); concept != nil {
+			sb.WriteString(demoteHeadings(concept.Markdown()))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func demoteHeadings(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+		if !inCode && strings.HasPrefix(line, "#") {
+			lines[i] = "##" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// exportedName converts a table name like user_order to UserOrder
+func exportedName(table string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(table, func(r rune) bool { return r == '_' || r == '-' }) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
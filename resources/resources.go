@@ -0,0 +1,397 @@
+// Package resources exposes go-zero project artifacts, docs and templates as MCP resources
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
+	"github.com/jinguoxing/mcp-gozero/internal/docs"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/security"
+	"github.com/jinguoxing/mcp-gozero/internal/templates"
+	"github.com/jinguoxing/mcp-gozero/internal/validation"
+)
+
+// Scheme is the URI scheme of every resource served by mcp-gozero
+const Scheme = "gozero://"
+
+// AnalysisURI is the project analysis resource
+const AnalysisURI = Scheme + "project/analysis"
+
+// Resource templates
+const (
+	APITemplate       = Scheme + "api/{service}"
+	ProtoTemplate     = Scheme + "proto/{service}"
+	ConfigTemplate    = Scheme + "config/{service}/{env}"
+	ConceptTemplate   = Scheme + "docs/concept/{name}"
+	MigrationTemplate = Scheme + "docs/migration/{framework}"
+	CodeTemplate      = Scheme + "templates/{type}/{name}"
+)
+
+// Registry serves project resources and keeps the list of concrete
+// spec and config resources in sync with the files on disk
+type Registry struct {
+	server      *mcp.Server
+	projectRoot string
+	policy      *sandbox.Policy
+
+	mu          sync.Mutex
+	files       map[string]string // concrete resource URI -> file path
+	dirs        []string          // service and config directories the watcher polls
+	lastRefresh time.Time
+}
+
+// MissRescanInterval is the minimum time between rescans triggered by
+// reading a resource that isn't known yet
+const MissRescanInterval = 5 * time.Second
+
+// Register adds the project resources and resource templates to the server;
+// files are only served from the directories policy permits to the session
+func Register(server *mcp.Server, projectRoot string, policy *sandbox.Policy) *Registry {
+	r := &Registry{
+		server:      server,
+		projectRoot: projectRoot,
+		policy:      policy,
+		files:       make(map[string]string),
+	}
+
+	server.AddResource(&mcp.Resource{
+		URI:         AnalysisURI,
+		Name:        "project-analysis",
+		Description: "go-zero project analysis (services, endpoints, configs, dependencies)",
+		MIMEType:    "application/json",
+	}, r.readAnalysis)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: APITemplate,
+		Name:        "api-spec",
+		Description: "API specification (.api) of a service",
+		MIMEType:    "text/plain",
+	}, r.readFile)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: ProtoTemplate,
+		Name:        "proto-spec",
+		Description: "Protobuf definition of an RPC service",
+		MIMEType:    "text/plain",
+	}, r.readFile)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: ConfigTemplate,
+		Name:        "service-config",
+		Description: "Service config for an environment (development, test or production)",
+		MIMEType:    "text/plain",
+	}, r.readConfig)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: ConceptTemplate,
+		Name:        "concept-doc",
+		Description: "go-zero concept documentation",
+		MIMEType:    "text/markdown",
+	}, readConcept)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: MigrationTemplate,
+		Name:        "migration-guide",
+		Description: "Migration guide from another framework to go-zero",
+		MIMEType:    "text/markdown",
+	}, readMigration)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: CodeTemplate,
+		Name:        "code-template",
+		Description: "Code template (middleware, error_handler or deployment)",
+		MIMEType:    "text/plain",
+	}, readTemplate)
+
+	r.Refresh()
+
+	return r
+}
+
+// Subscribe accepts subscriptions to gozero:// resources
+func Subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	if !strings.HasPrefix(req.Params.URI, Scheme) {
+		return mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	return nil
+}
+
+// Unsubscribe accepts unsubscribe requests; the server tracks subscriptions itself
+func Unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	return nil
+}
+
+// Refresh rescans the project and registers a concrete resource for every
+// discovered spec and config, removing resources whose files are gone
+func (r *Registry) Refresh() {
+	files, dirs := discoverResourceFiles(r.projectRoot)
+
+	r.mu.Lock()
+	r.dirs = dirs
+	r.lastRefresh = time.Now()
+	var added, removed []string
+	for uri := range files {
+		if _, ok := r.files[uri]; !ok {
+			added = append(added, uri)
+		}
+	}
+	for uri := range r.files {
+		if _, ok := files[uri]; !ok {
+			removed = append(removed, uri)
+		}
+	}
+	r.files = files
+	r.mu.Unlock()
+
+	sort.Strings(added)
+	for _, uri := range added {
+		r.server.AddResource(&mcp.Resource{
+			URI:      uri,
+			Name:     strings.TrimPrefix(uri, Scheme),
+			MIMEType: "text/plain",
+		}, r.readFile)
+	}
+	if len(removed) > 0 {
+		r.server.RemoveResources(removed...)
+	}
+}
+
+// URIsForFile returns the concrete resource URIs backed by a file
+func (r *Registry) URIsForFile(path string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var uris []string
+	for uri, file := range r.files {
+		if file == path {
+			uris = append(uris, uri)
+		}
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+func (r *Registry) readAnalysis(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	projectRoot, err := r.policy.Scope(ctx, req.Session).Resolve(r.projectRoot, sandbox.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := analyzer.ScanProject(projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze project: %w", err)
+	}
+
+	data, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode project analysis: %w", err)
+	}
+
+	return textResult(req.Params.URI, "application/json", string(data)), nil
+}
+
+func (r *Registry) readConfig(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	// Accept environment aliases such as dev or prod
+	uri := req.Params.URI
+	parts := strings.Split(strings.TrimPrefix(uri, Scheme+"config/"), "/")
+	if len(parts) != 2 {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	path, ok := r.lookupFile(configURI(parts[0], validation.NormalizeEnvironment(parts[1])))
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return r.readFileResult(ctx, req, path)
+}
+
+// readFile serves a concrete spec or config resource
+func (r *Registry) readFile(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	path, ok := r.lookupFile(uri)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return r.readFileResult(ctx, req, path)
+}
+
+// lookupFile resolves a URI case-insensitively, rescanning on a miss so
+// files created since the last refresh are found without the watcher; misses
+// rescan at most once per MissRescanInterval
+func (r *Registry) lookupFile(uri string) (string, bool) {
+	find := func() (string, bool) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if path, ok := r.files[uri]; ok {
+			return path, true
+		}
+		for candidate, path := range r.files {
+			if strings.EqualFold(candidate, uri) {
+				return path, true
+			}
+		}
+		return "", false
+	}
+
+	if path, ok := find(); ok {
+		return path, true
+	}
+	r.mu.Lock()
+	recent := time.Since(r.lastRefresh) < MissRescanInterval
+	r.mu.Unlock()
+	if recent {
+		return "", false
+	}
+	r.Refresh()
+	return find()
+}
+
+func readConcept(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	concept := docs.GetConceptByName(strings.TrimPrefix(uri, Scheme+"docs/concept/"))
+	if concept == nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	return textResult(uri, "text/markdown", concept.Markdown()), nil
+}
+
+func readMigration(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	guide := docs.GetMigrationGuide(strings.TrimPrefix(uri, Scheme+"docs/migration/"))
+	if guide == nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	return textResult(uri, "text/markdown", guide.Markdown()), nil
+}
+
+func readTemplate(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	parts := strings.Split(strings.TrimPrefix(uri, Scheme+"templates/"), "/")
+	if len(parts) != 2 {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	tmpl, err := templates.GetTemplate(parts[0], parts[1])
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	return textResult(uri, "text/plain", tmpl.Content), nil
+}
+
+// discoverResourceFiles maps resource URIs to the project's spec and config
+// files and returns the directories holding them
+func discoverResourceFiles(projectRoot string) (map[string]string, []string) {
+	files := make(map[string]string)
+
+	analysis, err := analyzer.ScanProject(projectRoot)
+	if err != nil {
+		return files, nil
+	}
+
+	var dirs []string
+	for _, service := range analysis.Services {
+		dirs = append(dirs, service.Path)
+	}
+	for _, cfg := range analysis.Configs {
+		dirs = append(dirs, filepath.Dir(cfg.Path))
+	}
+
+	for _, service := range analysis.Services {
+		name := service.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(service.SpecFile), filepath.Ext(service.SpecFile))
+		}
+		var uri string
+		switch service.Type {
+		case "api":
+			uri = Scheme + "api/" + name
+		case "rpc":
+			uri = Scheme + "proto/" + name
+		default:
+			continue
+		}
+		if _, exists := files[uri]; !exists {
+			files[uri] = service.SpecFile
+		}
+	}
+
+	for _, cfg := range analysis.Configs {
+		uri := configURI(validation.ConfigServiceName(cfg.Path), validation.DetectEnvironment(cfg.Path))
+		if _, exists := files[uri]; !exists {
+			files[uri] = cfg.Path
+		}
+	}
+
+	return files, outermostDirs(dirs)
+}
+
+// outermostDirs drops duplicates and directories inside another one
+func outermostDirs(dirs []string) []string {
+	sort.Strings(dirs)
+	var outer []string
+next:
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		for _, parent := range outer {
+			if within(parent, dir) {
+				continue next
+			}
+		}
+		outer = append(outer, dir)
+	}
+	return outer
+}
+
+// within reports whether path is dir or below it
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && (rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))))
+}
+
+func configURI(service, env string) string {
+	return Scheme + "config/" + service + "/" + env
+}
+
+// readFileResult reads a spec or config file inside the session's sandbox
+// scope; config secrets are redacted the way manage_config displays them
+func (r *Registry) readFileResult(ctx context.Context, req *mcp.ReadResourceRequest, path string) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	path, err := r.policy.Scope(ctx, req.Session).Resolve(path, sandbox.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	text := string(content)
+	if strings.HasPrefix(uri, Scheme+"config/") {
+		text = security.RedactConfigText(text)
+	}
+	return textResult(uri, "text/plain", text), nil
+}
+
+func textResult(uri, mimeType, text string) *mcp.ReadResourceResult {
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: mimeType, Text: text}},
+	}
+}
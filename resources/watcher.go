@@ -0,0 +1,205 @@
+package resources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultWatchInterval is how often the project is polled for changes
+const DefaultWatchInterval = 2 * time.Second
+
+// RescanInterval is how often the whole project is rescanned for services
+// added outside the watched directories
+const RescanInterval = time.Minute
+
+// maxDebounce bounds how many polls a batch of changes waits for the files
+// to settle, so a file rewritten continuously is still reported
+const maxDebounce = 5
+
+// watchedExtensions are the files that back resources or the project analysis
+var watchedExtensions = map[string]bool{
+	".api":   true,
+	".proto": true,
+	".yaml":  true,
+	".yml":   true,
+	".json":  true,
+	".toml":  true,
+	".mod":   true,
+}
+
+// specExtensions are the files whose content decides resource names
+var specExtensions = map[string]bool{
+	".api":   true,
+	".proto": true,
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch polls the project until ctx is done and sends resource-updated
+// notifications for changed files. Only the service and config directories
+// found by the last scan are walked, plus the files directly in the project
+// root; the whole project is rescanned every RescanInterval. Changes are
+// reported once a poll finds no further changes. Polling keeps the server
+// free of platform-specific file watching dependencies.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous, previousDirs := r.snapshotFiles()
+	pending := make(map[string]bool)
+	waited := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			stale := time.Since(r.lastRefresh) >= RescanInterval
+			r.mu.Unlock()
+			if stale {
+				r.Refresh()
+			}
+
+			current, dirs := r.snapshotFiles()
+			changed := false
+			for _, path := range changedFiles(previous, current) {
+				// Files of directories added by a rescan are not changes
+				if r.inScope(path, previousDirs) {
+					pending[path] = true
+					changed = true
+				}
+			}
+			previous, previousDirs = current, dirs
+			if len(pending) == 0 {
+				continue
+			}
+			if waited++; changed && waited < maxDebounce {
+				continue
+			}
+
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			r.notifyChanged(ctx, paths)
+			pending = make(map[string]bool)
+			waited = 0
+		}
+	}
+}
+
+// notifyChanged notifies subscribers of every resource backed by a changed
+// file, plus the project analysis. The resource list is only rescanned when
+// files were added or removed or a spec changed
+func (r *Registry) notifyChanged(ctx context.Context, changed []string) {
+	uris := map[string]bool{AnalysisURI: true}
+
+	// Deleted files are only known before the refresh, new files only after
+	refresh := false
+	for _, path := range changed {
+		known := r.URIsForFile(path)
+		for _, uri := range known {
+			uris[uri] = true
+		}
+		if _, err := os.Stat(path); len(known) == 0 || err != nil || specExtensions[strings.ToLower(filepath.Ext(path))] {
+			refresh = true
+		}
+	}
+	if !refresh {
+		r.notify(ctx, uris)
+		return
+	}
+
+	r.Refresh()
+	for _, path := range changed {
+		for _, uri := range r.URIsForFile(path) {
+			uris[uri] = true
+		}
+	}
+	r.notify(ctx, uris)
+}
+
+func (r *Registry) notify(ctx context.Context, uris map[string]bool) {
+	for uri := range uris {
+		r.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+}
+
+// snapshotFiles records the watched files in the project root and below the
+// directories of the last scan, which it returns too
+func (r *Registry) snapshotFiles() (map[string]fileState, []string) {
+	r.mu.Lock()
+	dirs := append([]string(nil), r.dirs...)
+	r.mu.Unlock()
+
+	files := make(map[string]fileState)
+	if entries, err := os.ReadDir(r.projectRoot); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || !watchedExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+				continue
+			}
+			if info, err := entry.Info(); err == nil {
+				files[filepath.Join(r.projectRoot, entry.Name())] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+		}
+	}
+	for _, dir := range dirs {
+		walkWatchedFiles(dir, files)
+	}
+	return files, dirs
+}
+
+// inScope reports whether a snapshot over dirs covers path
+func (r *Registry) inScope(path string, dirs []string) bool {
+	if filepath.Dir(path) == filepath.Clean(r.projectRoot) {
+		return true
+	}
+	for _, dir := range dirs {
+		if within(dir, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func walkWatchedFiles(root string, files map[string]fileState) {
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if watchedExtensions[strings.ToLower(filepath.Ext(path))] {
+			files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+}
+
+func changedFiles(previous, current map[string]fileState) []string {
+	var changed []string
+	for path, state := range current {
+		if old, ok := previous[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
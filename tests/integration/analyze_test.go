@@ -138,17 +138,12 @@ func TestAnalyzeProjectCache(t *testing.T) {
 		t.Error("Expected data to be returned")
 	}
 
-	dataMap, ok := data2.(map[string]any)
+	result, ok := data2.(*tools.AnalyzeProjectResult)
 	if !ok {
-		t.Fatalf("Expected data to be a map")
+		t.Fatalf("Expected *tools.AnalyzeProjectResult, got %T", data2)
 	}
 
-	fromCache, ok := dataMap["from_cache"].(bool)
-	if !ok {
-		t.Errorf("Expected from_cache field in data")
-	}
-
-	if !fromCache {
+	if !result.FromCache {
 		t.Errorf("Second call should be from cache")
 	}
 }
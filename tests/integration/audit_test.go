@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+func TestAuditLog(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	if err := os.WriteFile(goctlPath, []byte(fakeGoctl), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	tools.Audit = auditLog
+	t.Cleanup(func() {
+		tools.Audit = nil
+		auditLog.Close()
+	})
+
+	session := connectTools(t)
+	ctx := context.Background()
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool %s failed: %v", name, err)
+		}
+		return result
+	}
+
+	tmpDir := t.TempDir()
+	call("create_api_service", map[string]any{"service_name": "audited", "output_dir": tmpDir})
+	call("generate_config_template", map[string]any{
+		"service_name": "user-api", "service_type": "api", "environment": "development",
+		"output_path": filepath.Join(tmpDir, "etc", "user-api.yaml"),
+	})
+
+	events, _, err := auditLog.Query(audit.Filter{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	var goctlRun, serviceFile, configFile bool
+	for _, event := range events {
+		switch {
+		case event.Type == audit.TypeCommand && event.Tool == "create_api_service" && event.Command == goctlPath:
+			goctlRun = event.ExitCode != nil && *event.ExitCode == 0 && event.Dir == tmpDir
+		case event.Path == filepath.Join(tmpDir, "audited", "audited.go"):
+			serviceFile = event.Action == audit.ActionCreated && event.Tool == "create_api_service"
+		case event.Path == filepath.Join(tmpDir, "etc", "user-api.yaml"):
+			configFile = event.Action == audit.ActionCreated && event.Tool == "generate_config_template"
+		}
+	}
+	if !goctlRun || !serviceFile || !configFile {
+		t.Errorf("missing audit events (goctl=%v service=%v config=%v): %+v", goctlRun, serviceFile, configFile, events)
+	}
+
+	result := call("list_audit_events", map[string]any{"tool": "generate_config_template", "path": filepath.Join(tmpDir, "etc"), "since": "1h"})
+	if result.IsError {
+		t.Fatalf("list_audit_events failed: %s", result.Content[0].(*mcp.TextContent).Text)
+	}
+	data := result.StructuredContent.(map[string]any)
+	if data["matched"] != float64(1) {
+		t.Errorf("expected one matching event, got %v", data["matched"])
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "created "+filepath.Join(tmpDir, "etc", "user-api.yaml")) {
+		t.Errorf("unexpected listing: %s", text)
+	}
+
+	if result := call("list_audit_events", map[string]any{"since": "yesterday"}); !result.IsError {
+		t.Error("expected an invalid since to be rejected")
+	}
+}
@@ -2,8 +2,10 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -54,6 +56,74 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+func TestValidateConfigTOMLWithEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	os.WriteFile(configPath, []byte("Name = \"testapi\"\nHost = \"0.0.0.0\"\nPort = ${API_PORT}\n"), 0644)
+
+	params := tools.ValidateConfigParams{
+		ConfigPath: configPath,
+		Env:        map[string]string{"API_PORT": "8888"},
+	}
+
+	result, data, _ := tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, params)
+	if result.IsError {
+		t.Fatalf("Expected valid config, got: %v", result.Content)
+	}
+	if result, ok := data.(*tools.ValidateConfigResult); !ok || result.Format != "toml" {
+		t.Errorf("Expected format toml in data, got: %v", data)
+	}
+
+	params.Env = map[string]string{}
+	params.ExpandEnv = true
+	os.WriteFile(configPath, []byte("Name = \"testapi\"\nHost = \"${MCP_ZERO_UNDEFINED_HOST}\"\nPort = 8888\n"), 0644)
+
+	result, _, _ = tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, params)
+	if !result.IsError {
+		t.Errorf("Expected undefined variable to be reported as an error")
+	}
+}
+
+func TestValidateConfigOutputFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(configPath, []byte("Name: testapi\nHost: 0.0.0.0\nPort: 8888\nLog:\n  Mode: stdout\n"), 0644)
+
+	params := tools.ValidateConfigParams{ConfigPath: configPath}
+	result, _, _ := tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, params)
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Log.Mode (line 5, column 3)") {
+		t.Errorf("Expected located warning in text output, got:\n%s", text)
+	}
+
+	params.OutputFormat = "sarif"
+	result, _, _ = tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, params)
+	var sarif map[string]any
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &sarif); err != nil || sarif["version"] != "2.1.0" {
+		t.Errorf("Expected SARIF 2.1.0 output, got err=%v", err)
+	}
+
+	params.OutputFormat = "json"
+	result, _, _ = tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, params)
+	var report struct {
+		Diagnostics []struct {
+			Field string `json:"field"`
+			Line  int    `json:"line"`
+		} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &report); err != nil {
+		t.Fatalf("Expected JSON output: %v", err)
+	}
+	if len(report.Diagnostics) != 1 || report.Diagnostics[0].Line != 5 {
+		t.Errorf("Unexpected diagnostics: %+v", report.Diagnostics)
+	}
+
+	params.OutputFormat = "xml"
+	result, _, _ = tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, params)
+	if !result.IsError {
+		t.Errorf("Expected unsupported output format to be rejected")
+	}
+}
+
 func TestGenerateConfigTemplate(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -79,3 +149,244 @@ func TestGenerateConfigTemplate(t *testing.T) {
 		t.Errorf("Output file not created")
 	}
 }
+
+func TestDiffConfigs(t *testing.T) {
+	tmpDir := t.TempDir()
+	devPath := filepath.Join(tmpDir, "user-api.yaml")
+	prodPath := filepath.Join(tmpDir, "user-api-production.yaml")
+	os.WriteFile(devPath, []byte("Name: user-api\nHost: 0.0.0.0\nPort: 8888\nLog:\n  Level: info\n"), 0644)
+	os.WriteFile(prodPath, []byte("Name: user-api\nHost: 0.0.0.0\nPort: 8888\nMode: dev\n"), 0644)
+
+	params := tools.DiffConfigsParams{
+		ConfigPaths: []string{devPath, prodPath},
+	}
+
+	result, data, err := tools.DiffConfigs(context.Background(), &mcp.CallToolRequest{}, params)
+	if err != nil || result.IsError {
+		t.Fatalf("DiffConfigs failed: %v", err)
+	}
+
+	diff, ok := data.(*tools.DiffConfigsResult)
+	if !ok {
+		t.Fatalf("Expected *tools.DiffConfigsResult, got %T", data)
+	}
+	if diff.RiskCount != 1 {
+		t.Errorf("Expected 1 promotion risk, got %v", diff.RiskCount)
+	}
+	missing := diff.MissingInProduction
+	if len(missing[prodPath]) != 1 || missing[prodPath][0] != "Log.Level" {
+		t.Errorf("Expected Log.Level missing in production, got %v", missing)
+	}
+}
+
+func TestDiffConfigsRedactsSecretsInLists(t *testing.T) {
+	tmpDir := t.TempDir()
+	devPath := filepath.Join(tmpDir, "user-api.yaml")
+	prodPath := filepath.Join(tmpDir, "user-api-production.yaml")
+	os.WriteFile(devPath, []byte("Name: user-api\nCacheRedis:\n  - Host: 127.0.0.1:6379\n    Pass: devRedisS3cret\n"), 0644)
+	os.WriteFile(prodPath, []byte("Name: user-api\nCacheRedis:\n  - Host: 127.0.0.1:6379\n    Pass: prodRedisS3cret\n"), 0644)
+
+	result, data, err := tools.DiffConfigs(context.Background(), &mcp.CallToolRequest{}, tools.DiffConfigsParams{
+		ConfigPaths: []string{devPath, prodPath},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("DiffConfigs failed: %v", err)
+	}
+
+	structured, _ := json.Marshal(data)
+	for _, output := range []string{result.Content[0].(*mcp.TextContent).Text, string(structured)} {
+		if strings.Contains(output, "devRedisS3cret") || strings.Contains(output, "prodRedisS3cret") {
+			t.Errorf("Secrets nested in lists must be redacted, got:\n%s", output)
+		}
+		if !strings.Contains(output, "CacheRedis") {
+			t.Errorf("Expected the CacheRedis change to be reported, got:\n%s", output)
+		}
+	}
+}
+
+func TestGenerateConfigTemplateFromStruct(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module github.com/example/user\n\ngo 1.21\n"), 0644)
+	configDir := filepath.Join(tmpDir, "internal", "config")
+	os.MkdirAll(configDir, 0755)
+	configGo := "package config\n\ntype Config struct {\n\tName string\n\tListenOn string\n\tMysql struct {\n\t\tDataSource string\n\t}\n\tPageSize int `json:\",default=20\"`\n}\n"
+	os.WriteFile(filepath.Join(configDir, "config.go"), []byte(configGo), 0644)
+
+	params := tools.GenerateConfigParams{
+		ServiceName: "user-rpc",
+		Environment: "production",
+		FromStruct:  true,
+		ProjectPath: tmpDir,
+		Overrides:   `{"Mysql": {"DataSource": "${MYSQL_DSN}"}}`,
+	}
+
+	result, data, err := tools.GenerateConfigTemplate(context.Background(), &mcp.CallToolRequest{}, params)
+	if err != nil || result.IsError {
+		t.Fatalf("GenerateConfigTemplate failed: %v", err)
+	}
+
+	if generated, ok := data.(*tools.GenerateConfigTemplateResult); !ok || generated.ServiceType != "rpc" {
+		t.Errorf("Expected rpc service type detected from ListenOn, got %+v", data)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "etc", "user-rpc-production.yaml"))
+	if err != nil {
+		t.Fatalf("Output file not created: %v", err)
+	}
+	for _, want := range []string{"Name: user-rpc", "ListenOn: 0.0.0.0:9090", "DataSource: ${MYSQL_DSN}", "# int, default: 20", "PageSize: 20"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected generated config to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerateConfigTemplateFromStructTestEnvironment(t *testing.T) {
+	// Force the built-in go-zero type definitions so the fallback is reported
+	t.Setenv("GOMODCACHE", t.TempDir())
+
+	cases := []struct {
+		name     string
+		embed    string
+		importOf string
+		want     []string
+		reject   string
+	}{
+		{"api", "rest.RestConf", "github.com/zeromicro/go-zero/rest", []string{"Host: 127.0.0.1", "Port: 8888", "Middlewares:"}, "ListenOn:"},
+		{"rpc", "zrpc.RpcServerConf", "github.com/zeromicro/go-zero/zrpc", []string{"ListenOn: 127.0.0.1:9090"}, "Host: 127.0.0.1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			goMod := "module github.com/example/user\n\ngo 1.21\n\nrequire github.com/zeromicro/go-zero v1.6.0\n"
+			os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0644)
+			configDir := filepath.Join(tmpDir, "internal", "config")
+			os.MkdirAll(configDir, 0755)
+			configGo := "package config\n\nimport \"" + tc.importOf + "\"\n\ntype Config struct {\n\t" + tc.embed + "\n}\n"
+			os.WriteFile(filepath.Join(configDir, "config.go"), []byte(configGo), 0644)
+
+			result, data, err := tools.GenerateConfigTemplate(context.Background(), &mcp.CallToolRequest{}, tools.GenerateConfigParams{
+				ServiceName: "user-" + tc.name,
+				ServiceType: tc.name,
+				Environment: "test",
+				FromStruct:  true,
+				ProjectPath: tmpDir,
+			})
+			if err != nil || result.IsError {
+				t.Fatalf("GenerateConfigTemplate failed: %v", result.Content)
+			}
+
+			generated, ok := data.(*tools.GenerateConfigTemplateResult)
+			if !ok {
+				t.Fatalf("Expected *tools.GenerateConfigTemplateResult, got %T", data)
+			}
+			if len(generated.UnknownKeys) != 0 {
+				t.Errorf("Environment overrides should match the %s config struct, got unknown keys %v", tc.name, generated.UnknownKeys)
+			}
+			if len(generated.Fallbacks) == 0 {
+				t.Errorf("Expected the built-in go-zero fallback to be reported")
+			}
+
+			content, err := os.ReadFile(generated.OutputPath)
+			if err != nil {
+				t.Fatalf("Output file not created: %v", err)
+			}
+			for _, want := range append(tc.want, "# go-zero sources not found") {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("Expected generated config to contain %q, got:\n%s", want, content)
+				}
+			}
+			if strings.Contains(string(content), tc.reject) {
+				t.Errorf("Generated %s config should not contain %q, got:\n%s", tc.name, tc.reject, content)
+			}
+		})
+	}
+}
+
+func TestScanConfigSecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	etcDir := filepath.Join(tmpDir, "etc")
+	os.MkdirAll(etcDir, 0755)
+	config := "Name: user-api\nHost: 0.0.0.0\nPort: 8888\n" +
+		"Mysql:\n  DataSource: root:S3cretPassw0rd@tcp(127.0.0.1:3306)/user\n" +
+		"Auth:\n  AccessSecret: your-secret-key\n  AccessExpire: 86400\n" +
+		"Redis:\n  Host: 127.0.0.1:6379\n  Pass: ${REDIS_PASS}\n"
+	configPath := filepath.Join(etcDir, "user-api.yaml")
+	os.WriteFile(configPath, []byte(config), 0644)
+
+	result, data, _ := tools.ScanConfigSecrets(context.Background(), &mcp.CallToolRequest{}, tools.ScanConfigSecretsParams{
+		ProjectPath: tmpDir,
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if strings.Contains(text, "S3cretPassw0rd") || strings.Contains(text, "your-secret-key") {
+		t.Errorf("Secret values must be redacted, got:\n%s", text)
+	}
+	if !strings.Contains(text, "${MYSQL_DATA_SOURCE}") {
+		t.Errorf("Expected env substitution suggestion, got:\n%s", text)
+	}
+	if scan, ok := data.(*tools.ScanConfigSecretsResult); !ok || scan.SecretCount != 2 {
+		t.Errorf("Expected 2 secrets (placeholder Pass is fine), got: %v", data)
+	}
+
+	validateResult, _, _ := tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, tools.ValidateConfigParams{
+		ConfigPath: configPath,
+	})
+	text = validateResult.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "=== Secrets ===") || strings.Contains(text, "S3cretPassw0rd") {
+		t.Errorf("Expected redacted secrets section in validate_config output, got:\n%s", text)
+	}
+}
+
+func TestValidateConfigWarnsWhenSecretsNotScanned(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "user-api.toml")
+	// The unquoted placeholder only parses after expansion, so there are no raw values to scan
+	os.WriteFile(configPath, []byte("Name = \"user-api\"\nHost = \"0.0.0.0\"\nPort = ${PORT}\n"), 0644)
+
+	result, data, _ := tools.ValidateConfig(context.Background(), &mcp.CallToolRequest{}, tools.ValidateConfigParams{
+		ConfigPath: configPath,
+		Env:        map[string]string{"PORT": "8888"},
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "secrets were not scanned") {
+		t.Errorf("Expected a warning that secrets were not scanned, got:\n%s", text)
+	}
+	if validated, ok := data.(*tools.ValidateConfigResult); !ok || validated.WarningCount == 0 {
+		t.Errorf("Expected the skipped secret scan to count as a warning, got %+v", data)
+	}
+}
+
+func TestCheckConfigConsistency(t *testing.T) {
+	tmpDir := t.TempDir()
+	userEtc := filepath.Join(tmpDir, "user", "rpc", "etc")
+	orderEtc := filepath.Join(tmpDir, "order", "api", "etc")
+	os.MkdirAll(userEtc, 0755)
+	os.MkdirAll(orderEtc, 0755)
+	os.WriteFile(filepath.Join(userEtc, "user.yaml"), []byte(
+		"Name: user.rpc\nListenOn: 0.0.0.0:8888\nEtcd:\n  Hosts:\n    - 127.0.0.1:2379\n  Key: user.rpc\n"), 0644)
+	os.WriteFile(filepath.Join(orderEtc, "order-api.yaml"), []byte(
+		"Name: order-api\nHost: 0.0.0.0\nPort: 8888\nUserRpc:\n  Etcd:\n    Hosts:\n      - 127.0.0.1:2379\n    Key: user.rpc\n"), 0644)
+
+	result, data, _ := tools.CheckConfigConsistency(context.Background(), &mcp.CallToolRequest{}, tools.CheckConfigConsistencyParams{
+		ProjectPath: tmpDir,
+	})
+	if result.IsError {
+		t.Fatalf("Unexpected error: %v", result.Content)
+	}
+
+	check, ok := data.(*tools.CheckConfigConsistencyResult)
+	if !ok || check.ConfigCount != 2 || check.IssueCount != 1 {
+		t.Fatalf("Expected 2 configs with 1 port conflict, got: %v", data)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "both listen on port 8888") {
+		t.Errorf("Expected port conflict in output, got:\n%s", text)
+	}
+}
@@ -0,0 +1,219 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+// connectTools connects a client to a server with every tool registered
+func connectTools(t *testing.T) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	if err := tools.Register(server); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// resolveSchema resolves a schema as received by the client
+func resolveSchema(t *testing.T, raw any) *jsonschema.Resolved {
+	t.Helper()
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatalf("failed to resolve schema: %v", err)
+	}
+	return resolved
+}
+
+func TestToolOutputSchemas(t *testing.T) {
+	session := connectTools(t)
+
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(result.Tools) == 0 {
+		t.Fatal("expected registered tools")
+	}
+
+	for _, tool := range result.Tools {
+		if tool.OutputSchema == nil {
+			t.Errorf("tool %s declares no output schema", tool.Name)
+			continue
+		}
+		schema, _ := tool.OutputSchema.(map[string]any)
+		if schema["type"] != "object" {
+			t.Errorf("tool %s output schema should be an object, got %v", tool.Name, tool.OutputSchema)
+		}
+		resolveSchema(t, tool.OutputSchema)
+	}
+}
+
+func TestToolStructuredContentMatchesSchema(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	if err := os.WriteFile(goctlPath, []byte(fakeGoctl), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+
+	tmpDir := t.TempDir()
+	etcDir := filepath.Join(tmpDir, "etc")
+	os.MkdirAll(etcDir, 0755)
+	devConfig := filepath.Join(etcDir, "user-api.yaml")
+	prodConfig := filepath.Join(etcDir, "user-api-production.yaml")
+	os.WriteFile(devConfig, []byte("Name: user-api\nHost: 0.0.0.0\nPort: 8888\nLog:\n  Level: debug\n"+
+		"Auth:\n  AccessSecret: hard-coded-secret-value\n"), 0644)
+	os.WriteFile(prodConfig, []byte("Name: user-api\nHost: 0.0.0.0\nPort: 8888\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "user.api"), []byte("syntax = \"v1\"\n\ntype Request {\n\tName string `json:\"name\"`\n}\n\n"+
+		"type Response {\n\tMessage string `json:\"message\"`\n}\n\nservice user-api {\n\t@handler Hello\n\tpost /hello (Request) returns (Response)\n}\n"), 0644)
+
+	serviceDir := t.TempDir()
+	endpoints, _ := json.Marshal([]tools.EndpointInput{{
+		Method:   "get",
+		Path:     "/users",
+		Handler:  "ListUsers",
+		Response: map[string]interface{}{"total": 1},
+	}})
+
+	calls := []struct {
+		tool string
+		args map[string]any
+	}{
+		{"create_api_service", map[string]any{"service_name": "contract", "output_dir": serviceDir}},
+		{"create_api_spec", map[string]any{"service_name": "order", "endpoints_json": string(endpoints), "output_path": filepath.Join(tmpDir, "order.api")}},
+		{"analyze_project", map[string]any{"project_path": tmpDir}},
+		{"validate_config", map[string]any{"config_path": prodConfig}},
+		{"validate_config", map[string]any{"config_path": prodConfig, "output_format": "json"}},
+		{"generate_config_template", map[string]any{"service_name": "user-api", "service_type": "api", "environment": "test", "output_path": filepath.Join(tmpDir, "out", "user-api-test.yaml")}},
+		{"diff_configs", map[string]any{"config_paths": []string{devConfig, prodConfig}}},
+		{"scan_config_secrets", map[string]any{"project_path": tmpDir}},
+		{"check_config_consistency", map[string]any{"project_path": tmpDir}},
+		{"generate_template", map[string]any{"template_type": "deployment", "template_name": "docker", "parameters": `{"ServiceName": "user-api", "Port": 8888}`, "output_path": filepath.Join(tmpDir, "Dockerfile")}},
+		{"query_docs", map[string]any{"query": "middleware"}},
+		{"query_docs", map[string]any{"query": "zzz"}},
+		{"server_stats", map[string]any{}},
+		{"server_stats", map[string]any{"format": "prometheus"}},
+		{"show_server_config", map[string]any{}},
+		{"diagnose_goctl", map[string]any{"project_path": tmpDir}},
+		{"doctor", map[string]any{"dir": tmpDir}},
+	}
+
+	session := connectTools(t)
+	ctx := context.Background()
+
+	listed, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	schemas := make(map[string]*jsonschema.Resolved)
+	for _, tool := range listed.Tools {
+		schemas[tool.Name] = resolveSchema(t, tool.OutputSchema)
+	}
+
+	for _, call := range calls {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: call.tool, Arguments: call.args})
+		if err != nil {
+			t.Errorf("%s: CallTool failed: %v", call.tool, err)
+			continue
+		}
+		text := ""
+		if len(result.Content) > 0 {
+			text = result.Content[0].(*mcp.TextContent).Text
+		}
+		if result.IsError {
+			t.Errorf("%s: unexpected error result: %s", call.tool, text)
+			continue
+		}
+		if result.StructuredContent == nil {
+			t.Errorf("%s: missing structured content", call.tool)
+			continue
+		}
+
+		data, _ := json.Marshal(result.StructuredContent)
+		var instance any
+		json.Unmarshal(data, &instance)
+		if err := schemas[call.tool].Validate(instance); err != nil {
+			t.Errorf("%s: structured content does not match output schema: %v\n%s", call.tool, err, data)
+		}
+
+		// The text is for humans; the structured result is not dumped into it
+		if strings.Contains(text, string(data)) || strings.Contains(text, "\n{\n  \"") {
+			t.Errorf("%s: text content contains the raw JSON result:\n%s", call.tool, text)
+		}
+	}
+
+	// Failures report the error in _meta; structured content, if any, still
+	// matches the output schema
+	failures := []struct {
+		tool string
+		args map[string]any
+		code string
+	}{
+		{"create_api_service", map[string]any{"service_name": "Bad Name!", "output_dir": serviceDir}, "invalid_service_name"},
+		{"validate_config", map[string]any{"config_path": filepath.Join(tmpDir, "missing.yaml")}, ""},
+		{"generate_config_template", map[string]any{"service_name": "user-api", "service_type": "grpc", "environment": "test"}, "invalid_input"},
+	}
+	for _, call := range failures {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: call.tool, Arguments: call.args})
+		if err != nil {
+			t.Errorf("%s: CallTool failed: %v", call.tool, err)
+			continue
+		}
+		if !result.IsError {
+			t.Errorf("%s: expected an error result", call.tool)
+			continue
+		}
+		if result.StructuredContent != nil {
+			data, _ := json.Marshal(result.StructuredContent)
+			var instance any
+			json.Unmarshal(data, &instance)
+			if err := schemas[call.tool].Validate(instance); err != nil {
+				t.Errorf("%s: error result's structured content does not match output schema: %v\n%s", call.tool, err, data)
+			}
+		}
+		errorContent, ok := result.Meta[responses.ErrorMetaKey].(map[string]any)
+		if !ok {
+			t.Errorf("%s: expected the error in _meta, got %v", call.tool, result.Meta)
+			continue
+		}
+		if errorContent["code"] == "" || errorContent["category"] == "" {
+			t.Errorf("%s: error without code or category: %v", call.tool, errorContent)
+		}
+		if call.code != "" && errorContent["code"] != call.code {
+			t.Errorf("%s: expected code %s, got %v", call.tool, call.code, errorContent["code"])
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDiagnoseGoctl(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	script := "#!/bin/sh\necho 'goctl version 1.3.4 linux/amd64'\n"
+	if err := os.WriteFile(goctlPath, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+
+	projectDir := t.TempDir()
+	os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module demo\n\ngo 1.21\n\nrequire github.com/zeromicro/go-zero v1.6.3\n"), 0644)
+	nested := filepath.Join(projectDir, "internal", "logic")
+	os.MkdirAll(nested, 0755)
+
+	session := connectTools(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "diagnose_goctl",
+		Arguments: map[string]any{"project_path": nested},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("diagnose_goctl failed: %v %v", err, result)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	data := result.StructuredContent.(map[string]any)
+
+	if data["version"] != "1.3.4" || data["go_zero_version"] != "1.6.3" || data["go_mod_dir"] != projectDir {
+		t.Errorf("unexpected result: %v", data)
+	}
+	if data["compatible"] != false || !strings.Contains(text, "install goctl 1.6.x") {
+		t.Errorf("expected a version mismatch warning, got: %s", text)
+	}
+	if !strings.Contains(text, "Style flag: -style") {
+		t.Errorf("goctl 1.3.4 should use single-dash flags: %s", text)
+	}
+}
@@ -0,0 +1,190 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/tools"
+	"github.com/jinguoxing/mcp-gozero/transport"
+)
+
+// bearerTransport adds an Authorization header to every request
+type bearerTransport struct {
+	token string
+}
+
+func (b bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newHTTPTestServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "query_docs",
+		Description: "Query go-zero framework documentation and migration guides",
+	}, tools.QueryDocs)
+
+	ts := httptest.NewServer(transport.NewHTTPHandler(server, transport.HTTPOptions{
+		AuthToken: token,
+		Version:   "1.0.0",
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func connectHTTP(t *testing.T, endpoint, token string) *mcp.ClientSession {
+	t.Helper()
+
+	httpClient := &http.Client{}
+	if token != "" {
+		httpClient.Transport = bearerTransport{token: token}
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:   endpoint,
+		HTTPClient: httpClient,
+		MaxRetries: -1,
+	}, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestHTTPTransportHealth(t *testing.T) {
+	ts := newHTTPTestServer(t, "secret-token")
+
+	// Health is reachable without a token so load balancers can probe it
+	resp, err := http.Get(ts.URL + transport.HealthPath)
+	if err != nil {
+		t.Fatalf("health request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var health transport.Health
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		t.Fatalf("failed to decode health: %v", err)
+	}
+	if health.Status != "ok" || health.Version != "1.0.0" {
+		t.Errorf("unexpected health: %+v", health)
+	}
+}
+
+func TestHTTPTransportAuth(t *testing.T) {
+	ts := newHTTPTestServer(t, "secret-token")
+
+	for name, header := range map[string]string{
+		"missing": "",
+		"wrong":   "Bearer other-token",
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, ts.URL+transport.MCPPath, nil)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d", resp.StatusCode)
+			}
+		})
+	}
+
+	session := connectHTTP(t, ts.URL+transport.MCPPath, "secret-token")
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "query_docs" {
+		t.Errorf("unexpected tools: %+v", result.Tools)
+	}
+}
+
+func TestHTTPTransportSessions(t *testing.T) {
+	ts := newHTTPTestServer(t, "")
+	ctx := context.Background()
+
+	first := connectHTTP(t, ts.URL+transport.MCPPath, "")
+	second := connectHTTP(t, ts.URL+transport.MCPPath, "")
+
+	if first.ID() == "" || first.ID() == second.ID() {
+		t.Fatalf("expected distinct session IDs, got %q and %q", first.ID(), second.ID())
+	}
+
+	for _, session := range []*mcp.ClientSession{first, second} {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "query_docs",
+			Arguments: map[string]any{"query": "middleware"},
+		})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if result.IsError {
+			t.Errorf("query_docs returned an error: %+v", result.Content)
+		}
+	}
+
+	resp, err := http.Get(ts.URL + transport.HealthPath)
+	if err != nil {
+		t.Fatalf("health request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var health transport.Health
+	json.NewDecoder(resp.Body).Decode(&health)
+	if health.Sessions != 2 {
+		t.Errorf("expected 2 active sessions, got %d", health.Sessions)
+	}
+}
+
+func TestHTTPTransportShutdown(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- transport.Serve(ctx, listener, server, transport.HTTPOptions{ShutdownTimeout: time.Second})
+	}()
+
+	url := "http://" + listener.Addr().String() + transport.HealthPath
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("health request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+
+	if _, err := http.Get(url); err == nil {
+		t.Error("expected requests to fail after shutdown")
+	}
+}
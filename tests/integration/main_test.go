@@ -0,0 +1,24 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+// TestMain lets tools access the temporary directories created by tests
+// and fixtures relative to the package directory
+func TestMain(m *testing.M) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := tools.Sandbox.SetAllowList([]string{os.TempDir(), cwd}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
@@ -0,0 +1,57 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMigrateStyle(t *testing.T) {
+	session := connectTools(t)
+
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"internal/handler/pinghandler.go": "package handler\n\nfunc PingHandler() {}\n",
+		"internal/logic/pinglogic.go":     "package logic\n\ntype PingLogic struct{}\n",
+		"internal/svc/servicecontext.go":  "package svc\n\ntype ServiceContext struct{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data := callForError(t, session, "migrate_style", map[string]any{"project_path": projectDir, "to_style": "snake"})
+	if data["code"] != "invalid_input" {
+		t.Errorf("expected invalid_input for an unknown style, got %v", data["code"])
+	}
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "migrate_style",
+		Arguments: map[string]any{"project_path": projectDir, "to_style": "goZero"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("migrate_style failed: %v", result.Content[0])
+	}
+
+	migrated := result.StructuredContent.(map[string]any)
+	if migrated["from_style"] != "gozero" {
+		t.Errorf("expected the detected gozero style, got %v", migrated["from_style"])
+	}
+	if renamed := migrated["renamed"].([]any); len(renamed) != 3 {
+		t.Errorf("expected three renamed files, got %v", renamed)
+	}
+	for _, name := range []string{"internal/handler/pingHandler.go", "internal/logic/pingLogic.go", "internal/svc/serviceContext.go"} {
+		if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(name))); err != nil {
+			t.Errorf("%s should exist: %v", name, err)
+		}
+	}
+}
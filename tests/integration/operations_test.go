@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/tools"
+	"github.com/jinguoxing/mcp-gozero/transport"
+)
+
+func TestUndoOperation(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	if err := os.WriteFile(goctlPath, []byte(fakeGoctl), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+
+	session := connectTools(t)
+	ctx := context.Background()
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool %s failed: %v", name, err)
+		}
+		return result
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "README.md")
+	os.WriteFile(existing, []byte("# project\n"), 0644)
+
+	if result := call("create_api_service", map[string]any{"service_name": "undoable", "output_dir": tmpDir}); result.IsError {
+		t.Fatalf("create_api_service failed: %s", text(result))
+	}
+	serviceDir := filepath.Join(tmpDir, "undoable")
+
+	result := call("list_operations", map[string]any{"tool": "create_api_service", "limit": 1})
+	operations := result.StructuredContent.(map[string]any)["operations"].([]any)
+	if len(operations) != 1 {
+		t.Fatalf("expected one create_api_service operation, got: %s", text(result))
+	}
+	op := operations[0].(map[string]any)
+	if op["created"].(float64) == 0 || !strings.Contains(text(result), filepath.Join(serviceDir, "undoable.go")) {
+		t.Errorf("operation should list the generated files: %s", text(result))
+	}
+	opID := op["id"].(string)
+
+	// A file edited after the operation blocks undo until forced
+	edited := filepath.Join(serviceDir, "undoable.go")
+	os.WriteFile(edited, []byte("package main\n\n// edited\nfunc main() {}\n"), 0644)
+	result = call("undo_operation", map[string]any{"operation_id": opID})
+	if !result.IsError || !strings.Contains(text(result), edited) || !strings.Contains(text(result), "force=true") {
+		t.Fatalf("expected undo to refuse because of the edited file, got: %s", text(result))
+	}
+	if _, err := os.Stat(edited); err != nil {
+		t.Fatal("a refused undo must leave files in place")
+	}
+
+	result = call("undo_operation", map[string]any{"operation_id": opID, "force": true})
+	if result.IsError {
+		t.Fatalf("forced undo failed: %s", text(result))
+	}
+	if _, err := os.Stat(serviceDir); !os.IsNotExist(err) {
+		t.Error("undo should remove the generated service")
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "# project\n" {
+		t.Error("files the operation didn't touch must be kept")
+	}
+
+	result = call("undo_operation", map[string]any{"operation_id": opID})
+	if !result.IsError || !strings.Contains(text(result), "already undone") {
+		t.Errorf("expected second undo to be rejected, got: %s", text(result))
+	}
+	if result := call("undo_operation", map[string]any{"operation_id": "op-999999"}); !result.IsError {
+		t.Error("expected unknown operation to be rejected")
+	}
+}
+
+func TestUndoOperationScopedToSession(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	if err := os.WriteFile(goctlPath, []byte(fakeGoctl), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+
+	// Streamable HTTP sessions have distinct IDs, unlike in-memory ones
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	if err := tools.Register(server); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	ts := httptest.NewServer(transport.NewHTTPHandler(server, transport.HTTPOptions{Version: "1.0.0"}))
+	t.Cleanup(ts.Close)
+	owner := connectHTTP(t, ts.URL+transport.MCPPath, "")
+	other := connectHTTP(t, ts.URL+transport.MCPPath, "")
+
+	ctx := context.Background()
+	call := func(session *mcp.ClientSession, name string, args map[string]any) (*mcp.CallToolResult, string) {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool %s failed: %v", name, err)
+		}
+		return result, result.Content[0].(*mcp.TextContent).Text
+	}
+
+	tmpDir := t.TempDir()
+	if result, text := call(owner, "create_api_service", map[string]any{"service_name": "scoped", "output_dir": tmpDir}); result.IsError {
+		t.Fatalf("create_api_service failed: %s", text)
+	}
+	serviceDir := filepath.Join(tmpDir, "scoped")
+
+	// Another session neither sees nor undoes the operation by default
+	if _, text := call(other, "list_operations", nil); strings.Contains(text, serviceDir) {
+		t.Errorf("list_operations leaked another session's files: %s", text)
+	}
+	if result, text := call(other, "undo_operation", nil); !result.IsError || !strings.Contains(text, "no operation to undo") {
+		t.Errorf("expected nothing to undo for the other session, got: %s", text)
+	}
+	result, _ := call(owner, "list_operations", map[string]any{"tool": "create_api_service", "limit": 1})
+	operations := result.StructuredContent.(map[string]any)["operations"].([]any)
+	if len(operations) != 1 {
+		t.Fatalf("expected the owner to see its operation, got %v", operations)
+	}
+	opID := operations[0].(map[string]any)["id"].(string)
+	if result, text := call(other, "undo_operation", map[string]any{"operation_id": opID}); !result.IsError || !strings.Contains(text, "another session") {
+		t.Errorf("expected undo of another session's operation to be refused, got: %s", text)
+	}
+	if _, err := os.Stat(serviceDir); err != nil {
+		t.Fatal("a refused undo must leave the service in place")
+	}
+
+	// Repeated undo without an ID doesn't revert the previous undo
+	if result, text := call(owner, "undo_operation", nil); result.IsError || !strings.Contains(text, opID) {
+		t.Fatalf("expected undo of %s, got: %s", opID, text)
+	}
+	if result, text := call(owner, "undo_operation", nil); !result.IsError || !strings.Contains(text, "no operation to undo") {
+		t.Errorf("expected the second undo to find nothing, got: %s", text)
+	}
+	if _, err := os.Stat(serviceDir); !os.IsNotExist(err) {
+		t.Error("the second undo must not restore the removed service")
+	}
+}
@@ -0,0 +1,245 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+// fakeGoctl generates a minimal dependency-free API service so the whole
+// create_api_service pipeline runs without goctl or network access
+const fakeGoctl = `#!/bin/sh
+if [ "$1" = "--version" ]; then
+	echo "goctl version 1.6.3 linux/amd64"
+	exit 0
+fi
+name="$3"
+echo "Generating service $name"
+mkdir -p "$name/etc" "$name/internal/handler"
+printf 'syntax = "v1"\n' > "$name/$name.api"
+printf 'package main\n\nfunc main() {}\n' > "$name/$name.go"
+printf 'Name: %s\nHost: 0.0.0.0\nPort: 8888\n' "$name" > "$name/etc/$name-api.yaml"
+echo "Done."
+`
+
+type progressRecorder struct {
+	mu       sync.Mutex
+	progress []*mcp.ProgressNotificationParams
+	logs     []*mcp.LoggingMessageParams
+}
+
+func (r *progressRecorder) snapshot() ([]*mcp.ProgressNotificationParams, []*mcp.LoggingMessageParams) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*mcp.ProgressNotificationParams(nil), r.progress...), append([]*mcp.LoggingMessageParams(nil), r.logs...)
+}
+
+func TestCreateAPIServiceProgress(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	if err := os.WriteFile(goctlPath, []byte(fakeGoctl), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_api_service",
+		Description: "Create a new go-zero API service with proper structure and configuration",
+	}, tools.CreateAPIService)
+
+	recorder := &progressRecorder{}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+			recorder.mu.Lock()
+			recorder.progress = append(recorder.progress, req.Params)
+			recorder.mu.Unlock()
+		},
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			recorder.mu.Lock()
+			recorder.logs = append(recorder.logs, req.Params)
+			recorder.mu.Unlock()
+		},
+	})
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	defer session.Close()
+
+	if err := session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatalf("SetLoggingLevel failed: %v", err)
+	}
+
+	// SetProgressToken only works on existing metadata, so set it directly
+	params := &mcp.CallToolParams{
+		Meta: mcp.Meta{"progressToken": "create-1"},
+		Name: "create_api_service",
+		Arguments: map[string]any{
+			"service_name": "progressdemo",
+			"output_dir":   t.TempDir(),
+		},
+	}
+
+	result, err := session.CallTool(ctx, params)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("create_api_service failed: %s", result.Content[0].(*mcp.TextContent).Text)
+	}
+
+	// Notifications are delivered asynchronously; wait for the final one
+	deadline := time.Now().Add(5 * time.Second)
+	var progress []*mcp.ProgressNotificationParams
+	var logs []*mcp.LoggingMessageParams
+	for {
+		progress, logs = recorder.snapshot()
+		if len(progress) > 0 && progress[len(progress)-1].Progress == progress[len(progress)-1].Total {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("did not receive final progress notification, got %d", len(progress))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var steps []string
+	for i, p := range progress {
+		if p.ProgressToken != "create-1" {
+			t.Errorf("unexpected progress token %v", p.ProgressToken)
+		}
+		if i > 0 && p.Progress < progress[i-1].Progress {
+			t.Errorf("progress decreased: %v after %v", p.Progress, progress[i-1].Progress)
+		}
+		steps = append(steps, p.Message)
+	}
+	for _, want := range []string{"Running goctl api new", "Fixing imports", "Initializing Go module", "Running go mod tidy", "Checking style conflicts", "Running go build", "Validating project structure"} {
+		found := false
+		for _, step := range steps {
+			if step == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing progress step %q in %v", want, steps)
+		}
+	}
+
+	goctlOutput := false
+	for _, l := range logs {
+		if l.Logger == "goctl" && strings.Contains(l.Data.(string), "Generating service progressdemo") {
+			goctlOutput = true
+		}
+	}
+	if !goctlOutput {
+		t.Errorf("expected goctl output to be streamed as log messages, got %d logs", len(logs))
+	}
+}
+
+func TestCreateAPIServiceProgressFailure(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	script := "#!/bin/sh\necho \"boom: template not found\" >&2\nexit 1\n"
+	if err := os.WriteFile(goctlPath, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "create_api_service"}, tools.CreateAPIService)
+
+	recorder := &progressRecorder{}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			recorder.mu.Lock()
+			recorder.logs = append(recorder.logs, req.Params)
+			recorder.mu.Unlock()
+		},
+	})
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	defer session.Close()
+	session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "info"})
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "create_api_service",
+		Arguments: map[string]any{"service_name": "brokendemo", "output_dir": t.TempDir()},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected create_api_service to fail")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, logs := recorder.snapshot()
+		var sawStderr, sawError bool
+		for _, l := range logs {
+			text, _ := l.Data.(string)
+			if l.Logger == "goctl" && strings.Contains(text, "boom: template not found") {
+				sawStderr = true
+			}
+			if l.Level == "error" && strings.Contains(text, "failed to create API service") {
+				sawError = true
+			}
+		}
+		if sawStderr && sawError {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected goctl stderr and failure log, got %d logs", len(logs))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCreateAPIServiceGoctlTimeout(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	if err := os.WriteFile(goctlPath, []byte("#!/bin/sh\nsleep 30\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	t.Setenv("GOCTL_PATH", goctlPath)
+	t.Setenv("MCP_GOZERO_TIMEOUT_GOCTL", "200ms")
+
+	start := time.Now()
+	result, _, _ := tools.CreateAPIService(context.Background(), &mcp.CallToolRequest{}, tools.CreateAPIServiceParams{
+		ServiceName: "slowdemo",
+		OutputDir:   t.TempDir(),
+	})
+	if time.Since(start) > 10*time.Second {
+		t.Fatal("goctl was not killed after the step timeout")
+	}
+	if !result.IsError {
+		t.Fatal("expected a timeout error")
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "timed out after 200ms") || !strings.Contains(text, "MCP_GOZERO_TIMEOUT_GOCTL") {
+		t.Errorf("expected timeout message with configuration hint, got: %s", text)
+	}
+}
@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/prompts"
+)
+
+func TestWorkflowPrompts(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "shop.api"), []byte(`syntax = "v1"
+
+service shop-api {
+	@handler ListOrders
+	get /orders
+}
+`), 0644)
+
+	policy := sandbox.NewPolicy()
+	if err := policy.SetAllowList([]string{tmpDir}); err != nil {
+		t.Fatalf("SetAllowList failed: %v", err)
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	prompts.Register(server, tmpDir, policy)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	defer session.Close()
+
+	list, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts failed: %v", err)
+	}
+	if len(list.Prompts) != 4 {
+		t.Errorf("Expected 4 prompts, got %d", len(list.Prompts))
+	}
+
+	tests := []struct {
+		name     string
+		args     map[string]string
+		contains []string
+	}{
+		{
+			name:     "scaffold_crud_service",
+			args:     map[string]string{"table": "order_item", "service_name": "order-api"},
+			contains: []string{"DELETE /order_item/:id (DeleteOrderItem)", "`generate_model`", "GET /orders → ListOrders"},
+		},
+		{
+			name:     "migrate_gin_handler",
+			args:     map[string]string{"handler_code": "func Login(c *gin.Context) {}"},
+			contains: []string{"func Login(c *gin.Context) {}", "Migrating from Gin", "`generate_api_from_spec`"},
+		},
+		{
+			name:     "add_jwt_auth",
+			args:     map[string]string{"service_name": "shop"},
+			contains: []string{"@server(jwt: Auth)", "${AUTH_ACCESS_SECRET}"},
+		},
+		{
+			name:     "split_monolith",
+			args:     map[string]string{"service_name": "shop", "domains": "order,user"},
+			contains: []string{"`create_rpc_service`", "`check_config_consistency`"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: tt.name, Arguments: tt.args})
+			if err != nil {
+				t.Fatalf("GetPrompt failed: %v", err)
+			}
+			text := result.Messages[0].Content.(*mcp.TextContent).Text
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Prompt missing %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+
+	if _, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "add_jwt_auth"}); err == nil {
+		t.Errorf("Expected missing required argument to be rejected")
+	}
+
+	// project_path is resolved through the sandbox like tool paths
+	outside := t.TempDir()
+	_, err = session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "add_jwt_auth",
+		Arguments: map[string]string{"service_name": "shop", "project_path": outside},
+	})
+	if err == nil || !strings.Contains(err.Error(), "outside the permitted roots") {
+		t.Errorf("Expected project_path outside the roots to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRenameModule(t *testing.T) {
+	// -mod=mod is rejected in workspace mode
+	t.Setenv("GOFLAGS", "")
+	session := connectTools(t)
+
+	workDir := t.TempDir()
+	projectDir := filepath.Join(workDir, "user")
+	files := map[string]string{
+		"go.mod":                    "module github.com/example/user\n\ngo 1.21\n",
+		"user.go":                   "package main\n\nimport \"github.com/example/user/internal/config\"\n\nfunc main() { _ = config.Config{} }\n",
+		"internal/config/config.go": "package config\n\ntype Config struct{}\n",
+		"user.api":                  "syntax = \"v1\"\n\nimport \"github.com/example/user/types.api\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(workDir, "go.work"), []byte("go 1.21\n\nuse ./user\n"), 0644)
+
+	data := callForError(t, session, "rename_module", map[string]any{"project_path": projectDir, "new_module": "github.com/acme/my user"})
+	if data["code"] != "invalid_input" {
+		t.Errorf("expected invalid_input for a bad module path, got %v", data["code"])
+	}
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "rename_module",
+		Arguments: map[string]any{"project_path": projectDir, "new_module": "github.com/acme/user"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("rename_module failed: %v", result.Content[0])
+	}
+
+	renamed := result.StructuredContent.(map[string]any)
+	if renamed["old_module"] != "github.com/example/user" || !strings.HasSuffix(renamed["go_work"].(string), "go.work") {
+		t.Errorf("unexpected result %v", renamed)
+	}
+	if changed := renamed["files"].([]any); len(changed) != 3 {
+		t.Errorf("expected go.mod, user.go and user.api to change, got %v", changed)
+	}
+	content, _ := os.ReadFile(filepath.Join(projectDir, "user.go"))
+	if !strings.Contains(string(content), `"github.com/acme/user/internal/config"`) {
+		t.Errorf("imports should be renamed:\n%s", content)
+	}
+}
@@ -0,0 +1,217 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/resources"
+)
+
+// connectResources starts a server with project resources, allowing the
+// project root, and returns a connected client session
+func connectResources(t *testing.T, projectRoot string, opts *mcp.ClientOptions) (*resources.Registry, *mcp.ClientSession) {
+	t.Helper()
+
+	policy := sandbox.NewPolicy()
+	if err := policy.SetAllowList([]string{projectRoot}); err != nil {
+		t.Fatalf("SetAllowList failed: %v", err)
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   resources.Subscribe,
+		UnsubscribeHandler: resources.Unsubscribe,
+	})
+	registry := resources.Register(server, projectRoot, policy)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, opts)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+
+	return registry, session
+}
+
+func TestProjectResources(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "etc"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "user.api"), []byte(`syntax = "v1"
+
+service user-api {
+	@handler GetUser
+	get /users/:id
+}
+`), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "etc", "user-api.yaml"), []byte("Name: user-api\nHost: 0.0.0.0\nPort: 8888\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "etc", "user-api-production.yaml"), []byte("Name: user-api\nHost: 0.0.0.0\nPort: 80\n"), 0644)
+
+	_, session := connectResources(t, tmpDir, nil)
+	ctx := context.Background()
+
+	list, err := session.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+	uris := make(map[string]bool)
+	for _, r := range list.Resources {
+		uris[r.URI] = true
+	}
+	for _, uri := range []string{resources.AnalysisURI, "gozero://api/user", "gozero://config/user-api/production"} {
+		if !uris[uri] {
+			t.Errorf("Expected resource %s in list, got %v", uri, uris)
+		}
+	}
+
+	tests := []struct {
+		uri      string
+		contains string
+	}{
+		{resources.AnalysisURI, `"GetUser"`},
+		{"gozero://api/user", "service user-api"},
+		{"gozero://config/user-api/prod", "Port: 80"},
+		{"gozero://docs/concept/middleware", "# Middleware"},
+		{"gozero://docs/migration/gin", "Migrating from Gin"},
+		{"gozero://templates/middleware/auth", "package middleware"},
+	}
+	for _, tt := range tests {
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tt.uri})
+		if err != nil {
+			t.Errorf("ReadResource(%s) failed: %v", tt.uri, err)
+			continue
+		}
+		if !strings.Contains(result.Contents[0].Text, tt.contains) {
+			t.Errorf("ReadResource(%s) missing %q:\n%s", tt.uri, tt.contains, result.Contents[0].Text)
+		}
+	}
+
+	if _, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "gozero://proto/missing"}); err == nil {
+		t.Errorf("Expected error for unknown proto resource")
+	}
+}
+
+func TestConfigResourceRedactsSecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "etc"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "user.api"), []byte("syntax = \"v1\"\n\nservice user-api {\n}\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "etc", "user-api.yaml"), []byte(`Name: user-api
+Port: 8888
+Mysql:
+  DataSource: root:pa55word@tcp(127.0.0.1:3306)/user?parseTime=true
+Auth:
+  AccessSecret: short-secret
+`), 0644)
+
+	_, session := connectResources(t, tmpDir, nil)
+	ctx := context.Background()
+
+	for _, uri := range []string{"gozero://config/user-api/development", "gozero://config/user-api/dev"} {
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			t.Fatalf("ReadResource(%s) failed: %v", uri, err)
+		}
+		text := result.Contents[0].Text
+		for _, secret := range []string{"pa55word", "short-secret"} {
+			if strings.Contains(text, secret) {
+				t.Errorf("ReadResource(%s) leaked %q:\n%s", uri, secret, text)
+			}
+		}
+		if !strings.Contains(text, "root:****@tcp(127.0.0.1:3306)/user") || !strings.Contains(text, "Port: 8888") {
+			t.Errorf("ReadResource(%s) should keep non-secret content:\n%s", uri, text)
+		}
+	}
+}
+
+func TestProjectResourceUpdated(t *testing.T) {
+	tmpDir := t.TempDir()
+	apiFile := filepath.Join(tmpDir, "user.api")
+	os.WriteFile(apiFile, []byte("syntax = \"v1\"\n\nservice user-api {\n}\n"), 0644)
+
+	updated := make(chan string, 10)
+	registry, session := connectResources(t, tmpDir, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: "gozero://api/user"}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	go registry.Watch(ctx, 20*time.Millisecond)
+
+	// Let the watcher take its initial snapshot before changing the file
+	time.Sleep(50 * time.Millisecond)
+	os.WriteFile(apiFile, []byte("syntax = \"v1\"\n\nservice user-api {\n\t@handler Ping\n\tget /ping\n}\n"), 0644)
+
+	select {
+	case uri := <-updated:
+		if uri != "gozero://api/user" {
+			t.Errorf("Expected update for gozero://api/user, got %s", uri)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Timed out waiting for resource-updated notification")
+	}
+}
+
+func TestProjectResourceWatchScope(t *testing.T) {
+	tmpDir := t.TempDir()
+	serviceDir := filepath.Join(tmpDir, "services", "user")
+	os.MkdirAll(filepath.Join(serviceDir, "etc"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "web", "data"), 0755)
+	os.WriteFile(filepath.Join(serviceDir, "user.api"), []byte("syntax = \"v1\"\n\nservice user-api {\n}\n"), 0644)
+	configFile := filepath.Join(serviceDir, "etc", "user-api.yaml")
+	os.WriteFile(configFile, []byte("Name: user-api\nPort: 8888\n"), 0644)
+
+	updated := make(chan string, 10)
+	registry, session := connectResources(t, tmpDir, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: resources.AnalysisURI}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	go registry.Watch(ctx, 20*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	// Files outside the service directories are not polled
+	os.WriteFile(filepath.Join(tmpDir, "web", "data", "fixtures.json"), []byte("{}"), 0644)
+	select {
+	case uri := <-updated:
+		t.Fatalf("Unexpected update %s for a file outside the service directories", uri)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// A burst of writes is reported once
+	for i := 0; i < 3; i++ {
+		os.WriteFile(configFile, []byte(strings.Repeat("#\n", i+1)+"Name: user-api\nPort: 8888\n"), 0644)
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case <-updated:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Timed out waiting for resource-updated notification")
+	}
+	select {
+	case uri := <-updated:
+		t.Errorf("Expected a single notification for the burst, got another for %s", uri)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
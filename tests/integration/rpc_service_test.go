@@ -1,5 +1,185 @@
-package integration_test
+package integration
 
-// Note: RPC service creation tests are covered by the demo in demo/bookstore_demo/test_service.sh
-// Integration testing of protoc/goctl with proper go_package paths in temp directories is complex
-// and doesn't add value beyond what's already tested in the functional demo.
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/goctl"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+const orderProto = `syntax = "proto3";
+
+package order;
+option go_package = "./order";
+
+message GetOrderRequest {
+  int64 id = 1;
+}
+
+message GetOrderResponse {
+  int64 id = 1;
+  string status = 2;
+}
+
+service Order {
+  rpc GetOrder(GetOrderRequest) returns (GetOrderResponse);
+}
+`
+
+// useExecutor makes the tools run goctl through executor for the rest of the test
+func useExecutor(t *testing.T, executor goctl.Executor) {
+	t.Helper()
+	previous := tools.NewExecutor
+	tools.NewExecutor = func() (goctl.Executor, error) { return executor, nil }
+	t.Cleanup(func() { tools.NewExecutor = previous })
+}
+
+// loadFixture reads a fixture tree from testdata/goctl
+func loadFixture(t *testing.T, command, name string) goctl.Fixture {
+	t.Helper()
+	fixture, err := goctl.LoadFixture(command, filepath.Join("testdata", "goctl", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fixture
+}
+
+func TestCreateRPCService(t *testing.T) {
+	fake := goctl.NewFakeExecutor(goctl.Version{Major: 1, Minor: 6, Patch: 3}, loadFixture(t, "rpc protoc", "rpc_protoc"))
+	useExecutor(t, fake)
+
+	outputDir := t.TempDir()
+	session := connectTools(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "create_rpc_service",
+		Arguments: map[string]any{"service_name": "order", "proto_content": orderProto, "output_dir": outputDir},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError {
+		t.Fatalf("create_rpc_service failed: %s", text)
+	}
+
+	data := result.StructuredContent.(map[string]any)
+	if data["method_count"].(float64) != 1 || data["message_count"].(float64) != 2 {
+		t.Errorf("expected the proto to be parsed, got %v", data)
+	}
+	if !strings.Contains(text, "GetOrder(GetOrderRequest) returns (GetOrderResponse)") {
+		t.Errorf("expected the methods to be listed, got: %s", text)
+	}
+
+	serviceDir := filepath.Join(outputDir, "order")
+	if _, err := os.Stat(filepath.Join(serviceDir, "go.mod")); err != nil {
+		t.Errorf("expected the module to be initialized: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "order.proto")); !os.IsNotExist(err) {
+		t.Error("the temporary proto file should be removed")
+	}
+
+	runs := fake.Runs()
+	if len(runs) != 1 || strings.Join(runs[0], " ") != "rpc protoc order.proto --go_out=. --go-grpc_out=. --zrpc_out=. --style go_zero" {
+		t.Errorf("unexpected goctl runs: %v", runs)
+	}
+}
+
+func TestCreateRPCServiceMultipleUnsupported(t *testing.T) {
+	fake := goctl.NewFakeExecutor(goctl.Version{Major: 1, Minor: 3, Patch: 4}, loadFixture(t, "rpc protoc", "rpc_protoc"))
+	useExecutor(t, fake)
+
+	session := connectTools(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "create_rpc_service",
+		Arguments: map[string]any{"service_name": "order", "proto_content": orderProto, "output_dir": t.TempDir(), "multiple": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "--multiple") {
+		t.Errorf("expected --multiple to be rejected for goctl 1.3.4, got: %v", result.Content[0])
+	}
+	if len(fake.Runs()) != 0 {
+		t.Error("goctl must not run when its arguments can't be built")
+	}
+}
+
+func TestGoctlRecordReplay(t *testing.T) {
+	binDir := t.TempDir()
+	goctlPath := filepath.Join(binDir, "goctl")
+	if err := os.WriteFile(goctlPath, []byte(fakeGoctl), 0755); err != nil {
+		t.Fatalf("failed to write fake goctl: %v", err)
+	}
+	recordings := t.TempDir()
+	session := connectTools(t)
+	create := func(outputDir string) {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "create_api_service",
+			Arguments: map[string]any{"service_name": "replayed", "output_dir": outputDir},
+		})
+		if err != nil || result.IsError {
+			t.Fatalf("create_api_service failed: %v %v", err, result.Content)
+		}
+	}
+
+	// Record the goctl run of a real pipeline...
+	t.Setenv("GOCTL_PATH", goctlPath)
+	t.Setenv(goctl.EnvRecord, recordings)
+	create(t.TempDir())
+
+	// ...and replay it without any goctl installed
+	t.Setenv("GOCTL_PATH", filepath.Join(binDir, "missing"))
+	t.Setenv(goctl.EnvRecord, "")
+	t.Setenv(goctl.EnvReplay, recordings)
+	outputDir := t.TempDir()
+	create(outputDir)
+
+	for _, file := range []string{"replayed.api", "replayed.go", "etc/replayed-api.yaml", "go.mod"} {
+		if _, err := os.Stat(filepath.Join(outputDir, "replayed", file)); err != nil {
+			t.Errorf("expected %s in the replayed service: %v", file, err)
+		}
+	}
+}
+
+func TestCreateRPCServiceDiagnostics(t *testing.T) {
+	fake := goctl.NewFakeExecutor(goctl.Version{Major: 1, Minor: 6, Patch: 3}, goctl.Fixture{
+		Command:  "rpc protoc",
+		Stderr:   "protoc-gen-go: unable to determine Go import path for \"order.proto\"\n--go_out: protoc-gen-go: Plugin failed with status code 1.\n",
+		ExitCode: 1,
+	})
+	useExecutor(t, fake)
+
+	session := connectTools(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "create_rpc_service",
+		Arguments: map[string]any{"service_name": "order", "proto_content": orderProto, "output_dir": t.TempDir()},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !result.IsError || !strings.Contains(text, "Suggestion: Add a Go import path") {
+		t.Fatalf("expected the failure to be diagnosed, got: %s", text)
+	}
+
+	data, ok := result.Meta[responses.ErrorMetaKey].(map[string]any)
+	if !ok {
+		t.Fatalf("expected structured diagnostics, got %v", result.Meta)
+	}
+	diagnostics := data["diagnostics"].([]any)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diagnostics)
+	}
+	diagnostic := diagnostics[0].(map[string]any)
+	if diagnostic["kind"] != "missing_go_package" || diagnostic["file"] != "order.proto" || diagnostic["remedy"] == "" {
+		t.Errorf("unexpected diagnostic %v", diagnostic)
+	}
+}
@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+func TestSandboxClientRoots(t *testing.T) {
+	// Only the client's roots apply while the allow-list is empty
+	allowList := tools.Sandbox.AllowList()
+	tools.Sandbox.SetAllowList(nil)
+	t.Cleanup(func() { tools.Sandbox.SetAllowList(allowList) })
+
+	root := t.TempDir()
+	outside := t.TempDir()
+	os.WriteFile(filepath.Join(outside, "user-api.yaml"), []byte("Name: user-api\nHost: 0.0.0.0\nPort: 8888\n"), 0644)
+	if err := os.Symlink(outside, filepath.Join(root, "linked")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, &mcp.ServerOptions{
+		RootsListChangedHandler: tools.Sandbox.RootsChanged,
+	})
+	if err := tools.Register(server); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	var listed atomic.Int32
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	client.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "roots/list" {
+				listed.Add(1)
+			}
+			return next(ctx, method, req)
+		}
+	})
+	rootURI := (&url.URL{Scheme: "file", Path: filepath.ToSlash(root)}).String()
+	client.AddRoots(&mcp.Root{URI: rootURI, Name: "project"})
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	defer session.Close()
+
+	generate := func(outputPath string) *mcp.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name: "generate_config_template",
+			Arguments: map[string]any{
+				"service_name": "user-api",
+				"service_type": "api",
+				"environment":  "development",
+				"output_path":  outputPath,
+			},
+		})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		return result
+	}
+
+	inside := filepath.Join(root, "etc", "user-api.yaml")
+	if result := generate(inside); result.IsError {
+		t.Fatalf("write inside the client root rejected: %v", result.Content[0].(*mcp.TextContent).Text)
+	}
+	if _, err := os.Stat(inside); err != nil {
+		t.Errorf("expected config written inside the root: %v", err)
+	}
+
+	for name, path := range map[string]string{
+		"outside":   filepath.Join(outside, "etc", "user-api.yaml"),
+		"traversal": root + "/../" + filepath.Base(outside) + "/user-api.yaml",
+		"symlink":   filepath.Join(root, "linked", "etc", "user-api.yaml"),
+	} {
+		result := generate(path)
+		text := result.Content[0].(*mcp.TextContent).Text
+		if !result.IsError || !strings.Contains(text, "outside the permitted roots") {
+			t.Errorf("%s: expected write to be rejected, got: %s", name, text)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "etc")); !os.IsNotExist(err) {
+		t.Error("nothing may be created outside the client root")
+	}
+
+	// Reads go through the same policy
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "validate_config",
+		Arguments: map[string]any{"config_path": filepath.Join(root, "linked", "user-api.yaml")},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !result.IsError || !strings.Contains(text, "read access") {
+		t.Errorf("expected read through an escaping symlink to be rejected, got: %s", text)
+	}
+
+	// Roots are listed once per session and cached
+	if n := listed.Load(); n != 1 {
+		t.Errorf("expected roots to be listed once, got %d", n)
+	}
+
+	// Removing the root sends roots/list_changed, which revokes access
+	client.RemoveRoots(rootURI)
+	deadline := time.Now().Add(2 * time.Second)
+	for !generate(inside).IsError {
+		if time.Now().After(deadline) {
+			t.Fatal("expected write to be rejected after the root was removed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
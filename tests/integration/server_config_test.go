@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/serverconfig"
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+// useServerConfig loads content as the server config until the test ends
+func useServerConfig(t *testing.T, content string) *serverconfig.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), serverconfig.FileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := serverconfig.Load(path, "")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	previous := tools.ServerConfig()
+	allowList := tools.Sandbox.AllowList()
+	cfg.AllowedRoots = append(cfg.AllowedRoots, allowList...)
+	if err := tools.ApplyConfig(cfg); err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+	t.Cleanup(func() {
+		tools.ApplyConfig(previous)
+		tools.Sandbox.SetAllowList(allowList)
+	})
+	return cfg
+}
+
+func TestServerConfigDefaults(t *testing.T) {
+	useServerConfig(t, `
+module_prefix: gitlab.acme.io/platform
+ports:
+  api: {default: 8100, min: 8000, max: 8999}
+tools:
+  disabled: [generate_model]
+cache:
+  ttl: 30s
+`)
+	session := connectTools(t)
+	ctx := context.Background()
+
+	listed, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	for _, tool := range listed.Tools {
+		if tool.Name == "generate_model" {
+			t.Error("disabled tool should not be registered")
+		}
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "show_server_config", Arguments: map[string]any{}})
+	if err != nil || result.IsError {
+		t.Fatalf("show_server_config failed: %v %v", err, result)
+	}
+	data := result.StructuredContent.(map[string]any)
+	if data["module_prefix"] != "gitlab.acme.io/platform" {
+		t.Errorf("unexpected module prefix: %v", data["module_prefix"])
+	}
+	if data["cache"].(map[string]any)["ttl"] != "30s" {
+		t.Errorf("unexpected cache: %v", data["cache"])
+	}
+	disabled := data["tools"].(map[string]any)["disabled"].([]any)
+	if len(disabled) != 1 || disabled[0] != "generate_model" {
+		t.Errorf("unexpected disabled tools: %v", disabled)
+	}
+
+	// Config templates use the configured default port and range
+	tmpDir := t.TempDir()
+	outputPath := filepath.Join(tmpDir, "user-api.yaml")
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "generate_config_template",
+		Arguments: map[string]any{"service_name": "user-api", "service_type": "api", "environment": "development", "output_path": outputPath},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("generate_config_template failed: %s", result.Content[0].(*mcp.TextContent).Text)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected config to be written: %v", err)
+	}
+	if !strings.Contains(string(content), "Port: 8100") {
+		t.Errorf("expected configured default port, got:\n%s", content)
+	}
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "generate_config_template",
+		Arguments: map[string]any{"service_name": "user-api", "service_type": "api", "environment": "development", "port": 9000, "output_path": filepath.Join(tmpDir, "other.yaml")},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !result.IsError || !strings.Contains(text, "8000-8999") {
+		t.Errorf("expected port outside the configured range to be rejected, got: %s", text)
+	}
+}
+
+func TestServerConfigUnknownTool(t *testing.T) {
+	useServerConfig(t, "tools:\n  enabled: [query_doc]\n")
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	if err := tools.Register(server); err == nil || !strings.Contains(err.Error(), "query_doc") {
+		t.Errorf("expected unknown tool name to be rejected, got %v", err)
+	}
+}
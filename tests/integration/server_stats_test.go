@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/tools"
+)
+
+func TestServerStats(t *testing.T) {
+	tools.Metrics.Reset()
+	defer tools.Metrics.Reset()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	tools.AddTool[tools.QueryDocsResult](server, &mcp.Tool{Name: "query_docs", Description: "Query go-zero docs"}, tools.QueryDocs)
+	tools.AddTool[tools.ServerStatsResult](server, &mcp.Tool{Name: "server_stats", Description: "Server stats"}, tools.ServerStats)
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	defer session.Close()
+
+	// One successful call and one validation failure
+	for _, query := range []string{"middleware", ""} {
+		if _, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "query_docs",
+			Arguments: map[string]any{"query": query},
+		}); err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "server_stats"})
+	if err != nil {
+		t.Fatalf("CallTool server_stats failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "query_docs: 2 call(s), 1 error(s)") {
+		t.Errorf("expected query_docs stats, got:\n%s", text)
+	}
+	if !strings.Contains(text, "- validation: 1") {
+		t.Errorf("expected validation error category, got:\n%s", text)
+	}
+	if !strings.Contains(text, "=== Analysis Cache ===") {
+		t.Errorf("expected cache stats, got:\n%s", text)
+	}
+
+	// Prometheus output through the tool and the HTTP handler
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "server_stats",
+		Arguments: map[string]any{"format": "prometheus"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool server_stats failed: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, `mcp_gozero_tool_errors_total{tool="query_docs",category="validation"} 1`) {
+		t.Errorf("expected error counter in prometheus output, got:\n%s", text)
+	}
+
+	recorder := httptest.NewRecorder()
+	tools.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+	for _, want := range []string{
+		`mcp_gozero_tool_calls_total{tool="query_docs"} 2`,
+		`mcp_gozero_tool_calls_total{tool="server_stats"} 2`,
+		"mcp_gozero_analysis_cache_entries",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("missing %q in metrics endpoint output:\n%s", want, body)
+		}
+	}
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"go/format"
 	"os"
 	"path/filepath"
 	"strings"
@@ -216,6 +217,16 @@ func TestGenerateTemplate(t *testing.T) {
 								expected, string(content))
 						}
 					}
+
+					// Go files are checked and formatted in-process
+					if strings.HasSuffix(outputPath, ".go") {
+						if formatted, err := format.Source(content); err != nil || string(formatted) != string(content) {
+							t.Errorf("Expected %s to be gofmt-formatted (err: %v)", outputPath, err)
+						}
+						if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "verified successfully") {
+							t.Errorf("Expected the Go file to be verified, got: %s", text)
+						}
+					}
 				}
 
 				// Verify response has content
@@ -0,0 +1,5 @@
+package config
+
+type Config struct {
+	Name string
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/example/{{name}}/internal/config"
+)
+
+func main() {
+	fmt.Println(config.Config{Name: "{{name}}.rpc"}.Name)
+}
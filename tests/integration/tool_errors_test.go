@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/goctl"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+)
+
+// callForError calls a tool that must fail and returns the structured error in its _meta
+func callForError(t *testing.T, session *mcp.ClientSession, name string, args map[string]any) map[string]any {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("%s should fail, got: %v", name, result.Content[0])
+	}
+	if result.StructuredContent != nil {
+		t.Errorf("%s: error results must not carry structured content, got %v", name, result.StructuredContent)
+	}
+	data, ok := result.Meta[responses.ErrorMetaKey].(map[string]any)
+	if !ok {
+		t.Fatalf("expected a structured error in _meta, got %v", result.Meta)
+	}
+	return data
+}
+
+func TestToolErrorCodes(t *testing.T) {
+	fake := goctl.NewFakeExecutor(goctl.Version{Major: 1, Minor: 6, Patch: 3}, goctl.Fixture{
+		Command:  "rpc protoc",
+		Stderr:   "something unexpected happened\n",
+		ExitCode: 2,
+	})
+	useExecutor(t, fake)
+	session := connectTools(t)
+
+	tests := []struct {
+		name     string
+		tool     string
+		args     map[string]any
+		code     string
+		category string
+		detail   string
+	}{
+		{
+			name:     "invalid service name",
+			tool:     "create_api_service",
+			args:     map[string]any{"service_name": "1user", "output_dir": t.TempDir()},
+			code:     "invalid_service_name",
+			category: "validation",
+			detail:   "field",
+		},
+		{
+			name:     "missing output directory",
+			tool:     "create_rpc_service",
+			args:     map[string]any{"service_name": "order", "proto_content": orderProto, "output_dir": t.TempDir() + "/missing"},
+			code:     "invalid_path",
+			category: "validation",
+			detail:   "path",
+		},
+		{
+			name:     "goctl failure",
+			tool:     "create_rpc_service",
+			args:     map[string]any{"service_name": "order", "proto_content": orderProto, "output_dir": t.TempDir()},
+			code:     "goctl_failed",
+			category: "execution",
+			detail:   "exit_code",
+		},
+		{
+			name:     "missing api file",
+			tool:     "generate_api_from_spec",
+			args:     map[string]any{"api_file": t.TempDir() + "/user.api", "output_dir": t.TempDir()},
+			code:     "not_found",
+			category: "not_found",
+			detail:   "path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := callForError(t, session, tt.tool, tt.args)
+			if data["code"] != tt.code || data["category"] != tt.category || data["retryable"] != false {
+				t.Errorf("got code %v, category %v, retryable %v; want %s, %s, false", data["code"], data["category"], data["retryable"], tt.code, tt.category)
+			}
+			if details, _ := data["details"].(map[string]any); details[tt.detail] == nil {
+				t.Errorf("expected %s in the details, got %v", tt.detail, data["details"])
+			}
+			if data["error"] == "" {
+				t.Error("expected the error message")
+			}
+		})
+	}
+}
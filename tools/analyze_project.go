@@ -3,7 +3,6 @@ package tools
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -13,12 +12,26 @@ import (
 
 	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
 )
 
 type AnalyzeProjectParams struct {
 	ProjectPath string `json:"project_path"`
 }
 
+// AnalyzeProjectResult is the structured result of analyze_project
+type AnalyzeProjectResult struct {
+	ProjectPath     string `json:"project_path"`
+	TotalServices   int    `json:"total_services"`
+	APIServices     int    `json:"api_services"`
+	RPCServices     int    `json:"rpc_services"`
+	TotalEndpoints  int    `json:"total_endpoints"`
+	TotalRPCMethods int    `json:"total_rpc_methods"`
+	Dependencies    int    `json:"dependencies"`
+	GoZeroVersion   string `json:"go_zero_version"`
+	FromCache       bool   `json:"from_cache"`
+}
+
 // Cache for project analysis results with automatic cleanup
 type analysisCache struct {
 	mu      sync.RWMutex
@@ -35,11 +48,9 @@ var cache = &analysisCache{
 	entries: make(map[string]*cacheEntry),
 }
 
-const (
-	cacheTTL             = 5 * time.Minute
-	cacheCleanupInterval = 10 * time.Minute
-	maxCacheEntries      = 100 // Prevent unbounded memory growth
-)
+// cacheCleanupInterval is how often expired entries are dropped; TTL and
+// capacity come from the cache section of the server config
+const cacheCleanupInterval = 10 * time.Minute
 
 func init() {
 	// Start background cache cleanup goroutine
@@ -54,18 +65,10 @@ func init() {
 }
 
 func AnalyzeProject(ctx context.Context, req *mcp.CallToolRequest, params AnalyzeProjectParams) (*mcp.CallToolResult, any, error) {
-	projectPath := params.ProjectPath
-	if projectPath == "" {
-		cwd, _ := os.Getwd()
-		projectPath = cwd
-	}
-
-	if !filepath.IsAbs(projectPath) {
-		absPath, err := filepath.Abs(projectPath)
-		if err != nil {
-			return responses.FormatError(fmt.Sprintf("failed to resolve project path: %v", err))
-		}
-		projectPath = absPath
+	// An empty project path resolves to the working directory
+	projectPath, err := pathScope(ctx, req).Resolve(params.ProjectPath, sandbox.Read)
+	if err != nil {
+		return formatPathError("project_path", params.ProjectPath, err)
 	}
 
 	// Check cache
@@ -76,7 +79,7 @@ func AnalyzeProject(ctx context.Context, req *mcp.CallToolRequest, params Analyz
 	// Perform analysis
 	analysis, err := analyzer.ScanProject(projectPath)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to analyze project: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to analyze project: %v", err), err)
 	}
 
 	// Cache the result
@@ -85,6 +88,21 @@ func AnalyzeProject(ctx context.Context, req *mcp.CallToolRequest, params Analyz
 	return formatAnalysisResult(analysis, false)
 }
 
+// analyzeProjectCached returns the cached analysis for a project, scanning it on a miss
+func analyzeProjectCached(projectPath string) (*analyzer.ProjectAnalysis, error) {
+	if cachedAnalysis := getCachedAnalysis(projectPath); cachedAnalysis != nil {
+		return cachedAnalysis, nil
+	}
+
+	analysis, err := analyzer.ScanProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	cacheAnalysis(projectPath, analysis)
+
+	return analysis, nil
+}
+
 func getCachedAnalysis(projectPath string) *analyzer.ProjectAnalysis {
 	cache.mu.Lock() // Use write lock to update hits counter
 	defer cache.mu.Unlock()
@@ -95,7 +113,7 @@ func getCachedAnalysis(projectPath string) *analyzer.ProjectAnalysis {
 	}
 
 	// Check if cache is still valid
-	if time.Since(entry.timestamp) > cacheTTL {
+	if time.Since(entry.timestamp) > ServerConfig().Cache.TTL {
 		// Remove expired entry immediately
 		delete(cache.entries, projectPath)
 		return nil
@@ -112,7 +130,7 @@ func cacheAnalysis(projectPath string, analysis *analyzer.ProjectAnalysis) {
 	defer cache.mu.Unlock()
 
 	// Enforce max cache size - evict least recently used entries
-	if len(cache.entries) >= maxCacheEntries {
+	for len(cache.entries) >= ServerConfig().Cache.MaxEntries {
 		evictOldestEntry()
 	}
 
@@ -129,8 +147,9 @@ func cleanupExpiredEntries() {
 	defer cache.mu.Unlock()
 
 	now := time.Now()
+	ttl := ServerConfig().Cache.TTL
 	for path, entry := range cache.entries {
-		if now.Sub(entry.timestamp) > cacheTTL {
+		if now.Sub(entry.timestamp) > ttl {
 			delete(cache.entries, path)
 		}
 	}
@@ -181,7 +200,7 @@ func GetCacheStats() map[string]interface{} {
 		"total_entries": len(cache.entries),
 		"total_hits":    totalHits,
 		"oldest_entry":  oldestEntry,
-		"max_capacity":  maxCacheEntries,
+		"max_capacity":  ServerConfig().Cache.MaxEntries,
 	}
 }
 
@@ -279,16 +298,16 @@ func formatAnalysisResult(analysis *analyzer.ProjectAnalysis, fromCache bool) (*
 	message.WriteString("  - Use create_rpc_service to add new RPC services\n")
 	message.WriteString("  - Use generate_model to add database models\n")
 
-	data := map[string]any{
-		"project_path":      analysis.ProjectPath,
-		"total_services":    analysis.Summary.TotalServices,
-		"api_services":      analysis.Summary.APIServices,
-		"rpc_services":      analysis.Summary.RPCServices,
-		"total_endpoints":   analysis.Summary.TotalEndpoints,
-		"total_rpc_methods": analysis.Summary.TotalRPCMethods,
-		"dependencies":      analysis.Summary.TotalDependencies,
-		"go_zero_version":   analysis.Summary.GoZeroVersion,
-		"from_cache":        fromCache,
+	data := &AnalyzeProjectResult{
+		ProjectPath:     analysis.ProjectPath,
+		TotalServices:   analysis.Summary.TotalServices,
+		APIServices:     analysis.Summary.APIServices,
+		RPCServices:     analysis.Summary.RPCServices,
+		TotalEndpoints:  analysis.Summary.TotalEndpoints,
+		TotalRPCMethods: analysis.Summary.TotalRPCMethods,
+		Dependencies:    analysis.Summary.TotalDependencies,
+		GoZeroVersion:   analysis.Summary.GoZeroVersion,
+		FromCache:       fromCache,
 	}
 
 	return responses.FormatSuccessWithData(message.String(), data)
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/validation"
+)
+
+type CheckConfigConsistencyParams struct {
+	ProjectPath string `json:"project_path"`
+}
+
+// CheckConfigConsistencyResult is the structured result of check_config_consistency
+type CheckConfigConsistencyResult struct {
+	ProjectPath string             `json:"project_path"`
+	ConfigCount int                `json:"config_count"`
+	IssueCount  int                `json:"issue_count"`
+	ErrorCount  int                `json:"error_count"`
+	Issues      []ConsistencyIssue `json:"issues"`
+	Skipped     []string           `json:"skipped,omitempty"`
+}
+
+// ConsistencyIssue is a problem found by comparing service configs
+type ConsistencyIssue struct {
+	Kind        string           `json:"kind"`
+	Severity    string           `json:"severity"`
+	Environment string           `json:"environment"`
+	Message     string           `json:"message"`
+	Locations   []ConfigLocation `json:"locations"`
+	Suggestion  string           `json:"suggestion"`
+}
+
+// ConfigLocation is a config field involved in a consistency issue
+type ConfigLocation struct {
+	ConfigPath string `json:"config_path"`
+	Field      string `json:"field"`
+	Value      string `json:"value"`
+}
+
+// CheckConfigConsistency loads every config in a project and checks them against each other
+func CheckConfigConsistency(ctx context.Context, req *mcp.CallToolRequest, params CheckConfigConsistencyParams) (*mcp.CallToolResult, any, error) {
+	// An empty project path resolves to the working directory
+	projectPath, err := pathScope(ctx, req).Resolve(params.ProjectPath, sandbox.Read)
+	if err != nil {
+		return formatPathError("project_path", params.ProjectPath, err)
+	}
+
+	analysis, err := analyzeProjectCached(projectPath)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to analyze project: %v", err), err)
+	}
+
+	configs := make([]validation.EnvironmentConfig, 0, len(analysis.Configs))
+	var skipped []string
+	for _, cfg := range analysis.Configs {
+		loaded, err := validation.LoadConfigFile(cfg.Path, validation.ConfigLoadOptions{})
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", relativeToProject(projectPath, cfg.Path), err))
+			continue
+		}
+		configs = append(configs, validation.EnvironmentConfig{
+			Path:        cfg.Path,
+			Environment: validation.DetectEnvironment(cfg.Path),
+			Values:      loaded.Values,
+		})
+	}
+
+	issues := validation.CheckProjectConsistency(configs)
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("Config Consistency Check: %s\n\n", projectPath))
+	message.WriteString(fmt.Sprintf("Config files checked: %d\n\n", len(configs)))
+
+	errorCount := 0
+	if len(issues) > 0 {
+		message.WriteString("=== Issues ===\n")
+		for _, issue := range issues {
+			icon := "⚠️ "
+			if issue.Severity == "error" {
+				icon = "❌"
+				errorCount++
+			}
+			message.WriteString(fmt.Sprintf("  %s [%s] %s\n", icon, issue.Environment, issue.Message))
+			for _, loc := range issue.Locations {
+				message.WriteString(fmt.Sprintf("     - %s: %s = %s\n", relativeToProject(projectPath, loc.Path), loc.Field, loc.Value))
+			}
+			if issue.Suggestion != "" {
+				message.WriteString(fmt.Sprintf("     Suggestion: %s\n", issue.Suggestion))
+			}
+		}
+		message.WriteString("\n")
+	}
+
+	if len(skipped) > 0 {
+		message.WriteString("=== Skipped ===\n")
+		for _, s := range skipped {
+			message.WriteString(fmt.Sprintf("  ⚠️  %s\n", s))
+		}
+		message.WriteString("\n")
+	}
+
+	if len(issues) == 0 {
+		message.WriteString("✅ Service configs are consistent.\n")
+	} else {
+		message.WriteString(fmt.Sprintf("Found %d issue(s), %d error(s).\n", len(issues), errorCount))
+	}
+
+	issueData := make([]ConsistencyIssue, 0, len(issues))
+	for _, issue := range issues {
+		locations := make([]ConfigLocation, 0, len(issue.Locations))
+		for _, loc := range issue.Locations {
+			locations = append(locations, ConfigLocation{
+				ConfigPath: loc.Path,
+				Field:      loc.Field,
+				Value:      loc.Value,
+			})
+		}
+		issueData = append(issueData, ConsistencyIssue{
+			Kind:        issue.Kind,
+			Severity:    issue.Severity,
+			Environment: issue.Environment,
+			Message:     issue.Message,
+			Locations:   locations,
+			Suggestion:  issue.Suggestion,
+		})
+	}
+
+	data := &CheckConfigConsistencyResult{
+		ProjectPath: projectPath,
+		ConfigCount: len(configs),
+		IssueCount:  len(issues),
+		ErrorCount:  errorCount,
+		Issues:      issueData,
+		Skipped:     skipped,
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/diagnostics"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+)
+
+// commandErrorMessage formats a failed goctl or go step; timeouts and
+// cancelled requests are called out so they are not mistaken for build errors
+func commandErrorMessage(action string, err error) string {
+	var timeoutErr *mcperrors.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		envVar := process.EnvVar(process.Step(timeoutErr.Step))
+		return fmt.Sprintf("%s: %s timed out after %s and was killed\nSuggestion: raise the limit with %s (e.g. %s=%s)",
+			action, timeoutErr.Step, timeoutErr.Timeout, envVar, envVar, 2*timeoutErr.Timeout)
+	}
+	if errors.Is(err, context.Canceled) {
+		return fmt.Sprintf("%s: request cancelled", action)
+	}
+	return fmt.Sprintf("%s: %v", action, err)
+}
+
+// commandFailure reports a failed goctl or go step; failures recognized in
+// the step's output are returned as structured diagnostics
+func commandFailure(message string, err error) (*mcp.CallToolResult, any, error) {
+	if found := diagnostics.Diagnose(err); len(found) > 0 {
+		return responses.FormatDiagnostics(message, found)
+	}
+	return responses.FormatErrorWithCause(message, err)
+}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/jinguoxing/mcp-gozero/internal/fixer"
 	"github.com/jinguoxing/mcp-gozero/internal/goctl"
+	"github.com/jinguoxing/mcp-gozero/internal/progress"
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
 	"github.com/jinguoxing/mcp-gozero/internal/validation"
 )
 
+// createAPIServiceSteps is the number of progress steps reported by CreateAPIService
+const createAPIServiceSteps = 8
+
 // CreateAPIServiceParams defines the parameters for creating an API service
 type CreateAPIServiceParams struct {
 	ServiceName string `json:"service_name"`
@@ -21,26 +27,43 @@ type CreateAPIServiceParams struct {
 	Style       string `json:"style,omitempty"`
 }
 
+// CreateAPIServiceResult is the structured result of create_api_service
+type CreateAPIServiceResult struct {
+	ServiceType  string             `json:"service_type"`
+	ServiceName  string             `json:"service_name"`
+	OutputDir    string             `json:"output_dir"`
+	Port         int                `json:"port"`
+	Style        string             `json:"style"`
+	Warnings     []string           `json:"warnings,omitempty"`
+	FixedImports []fixer.FileChange `json:"fixed_imports,omitempty"`
+}
+
 // CreateAPIService creates a new go-zero API service
 func CreateAPIService(ctx context.Context, req *mcp.CallToolRequest, params CreateAPIServiceParams) (*mcp.CallToolResult, any, error) {
 	// Validate service name
 	if err := validation.ValidateServiceName(params.ServiceName); err != nil {
-		return responses.FormatValidationError("service_name", params.ServiceName, err.Error(), "Use lowercase letters, numbers, and hyphens only")
+		return responses.FormatValidationErrorWithCause("service_name", params.ServiceName, err, "Use lowercase letters, numbers, and hyphens only")
 	}
 
+	cfg := ServerConfig()
+
 	// Validate and set default port
+	ports := cfg.PortRange("api")
 	port := params.Port
 	if port == 0 {
-		port = 8888
+		port = ports.Default
+	}
+	if !ports.Contains(port) {
+		return responses.FormatValidationError("port", fmt.Sprintf("%d", port), fmt.Sprintf("port is outside the configured API port range %d-%d", ports.Min, ports.Max), fmt.Sprintf("Use a port number between %d and %d", ports.Min, ports.Max))
 	}
 	if err := validation.ValidatePort(port); err != nil {
-		return responses.FormatValidationError("port", fmt.Sprintf("%d", port), err.Error(), "Use a port number between 1024 and 65535")
+		return responses.FormatValidationError("port", fmt.Sprintf("%d", port), err.Error(), fmt.Sprintf("Use a port number between %d and %d", ports.Min, ports.Max))
 	}
 
 	// Set default style
 	style := params.Style
 	if style == "" {
-		style = "go_zero"
+		style = cfg.Style
 	}
 
 	// Validate output directory
@@ -48,75 +71,121 @@ func CreateAPIService(ctx context.Context, req *mcp.CallToolRequest, params Crea
 	if outputDir == "" {
 		outputDir = "."
 	}
+	// Only the service directory is written and tracked, not all of outputDir
+	scope := pathScope(ctx, req)
+	outputDir, err := scope.Resolve(outputDir, sandbox.Read)
+	if err != nil {
+		return formatPathError("output_dir", params.OutputDir, err)
+	}
 	if err := validation.ValidateOutputDir(outputDir); err != nil {
-		return responses.FormatValidationError("output_dir", outputDir, err.Error(), "Provide an absolute path to an existing writable directory")
+		return responses.FormatValidationErrorWithCause("output_dir", outputDir, err, "Provide an absolute path to an existing writable directory")
 	}
 
 	// Prepare service directory
 	serviceDir := filepath.Join(outputDir, params.ServiceName)
+	if _, err := scope.Resolve(serviceDir, sandbox.Write); err != nil {
+		return formatPathError("service_name", params.ServiceName, err)
+	}
+
+	// Report each step to the client; goctl and go output is streamed as log messages
+	reporter := progress.NewReporter(ctx, req, createAPIServiceSteps)
 
 	// Execute goctl api new command
-	executor, err := goctl.NewExecutor()
+	executor, err := NewExecutor()
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create executor: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to create executor: %v", err), err)
 	}
 
 	// goctl api new creates service in current directory, so we execute in outputDir
-	args := []string{
-		"api",
-		"new",
-		params.ServiceName,
-		"--style", style,
-	}
+	caps := executor.Capabilities(ctx)
+	args := caps.APINewArgs(params.ServiceName, style)
 
-	result := executor.ExecuteInDir(outputDir, args...)
+	reporter.Step("Running goctl api new")
+	result := executor.Run(ctx, outputDir, reporter.Output("goctl"), args...)
 	if result.Error != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create API service: %v\nStderr: %s", result.Error, result.Stderr))
+		return failStep(reporter, commandErrorMessage("failed to create API service", result.Error), result.Error)
 	}
 
 	// Use a proper module path format (avoid module names starting with numbers)
-	moduleName := "github.com/example/" + params.ServiceName
+	moduleName := cfg.ModulePath(params.ServiceName)
 
 	// Fix imports
-	if err := fixer.FixImports(serviceDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to fix imports: %v", err))
+	reporter.Step("Fixing imports")
+	fixedImports, err := fixer.FixImports(serviceDir, moduleName)
+	if err != nil {
+		return failStep(reporter, fmt.Sprintf("failed to fix imports: %v", err), err)
 	}
 
 	// Initialize Go module
-	if err := fixer.InitializeGoModule(serviceDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to initialize Go module: %v", err))
+	reporter.Step("Initializing Go module")
+	if err := fixer.InitializeGoModuleWithOutput(ctx, serviceDir, moduleName, reporter.Output("go")); err != nil {
+		return failStep(reporter, commandErrorMessage("failed to initialize Go module", err), err)
 	}
 
 	// Tidy module
-	if err := fixer.TidyGoModule(serviceDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to tidy Go module: %v", err))
+	reporter.Step("Running go mod tidy")
+	if err := fixer.TidyGoModuleWithOutput(ctx, serviceDir, reporter.Output("go")); err != nil {
+		return failStep(reporter, commandErrorMessage("failed to tidy Go module", err), err)
 	}
 
 	// Update config file with port
+	reporter.Step("Updating config file")
 	if err := fixer.UpdateConfigFile(serviceDir, params.ServiceName, port); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to update config file: %v", err))
+		return failStep(reporter, fmt.Sprintf("failed to update config file: %v", err), err)
 	}
 
 	// Validate no style conflicts
+	reporter.Step("Checking style conflicts")
 	if err := fixer.ValidateNoStyleConflicts(serviceDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("style conflicts detected: %v", err))
+		return failStep(reporter, fmt.Sprintf("style conflicts detected: %v", err), err)
 	}
 
 	// Verify build
-	if err := fixer.VerifyBuild(serviceDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to verify build: %v", err))
+	reporter.Step("Running go build")
+	if err := fixer.VerifyBuildWithOutput(ctx, serviceDir, reporter.Output("go")); err != nil {
+		return failStep(reporter, commandErrorMessage("failed to verify build", err), err)
 	}
 
 	// Validate project structure
+	reporter.Step("Validating project structure")
 	validator := goctl.NewValidator()
 	if err := validator.ValidateServiceProject(serviceDir, "api"); err != nil {
-		return responses.FormatError(fmt.Sprintf("project structure validation failed: %v", err))
+		return failStep(reporter, fmt.Sprintf("project structure validation failed: %v", err), err)
+	}
+
+	warnings := goctlWarnings(caps, serviceDir)
+	for _, warning := range warnings {
+		reporter.Log(mcp.LoggingLevel("warning"), "goctl", warning)
 	}
 
+	reporter.Done("API service created")
+
 	// Return success response
 	additionalInfo := map[string]string{
 		"port":  fmt.Sprintf("%d", port),
 		"style": style,
 	}
-	return responses.FormatServiceCreated("api", params.ServiceName, serviceDir, additionalInfo)
+	if caps.Detected {
+		additionalInfo["goctl"] = caps.Version.String()
+	}
+	if len(warnings) > 0 {
+		additionalInfo["warnings"] = "⚠️ " + strings.Join(warnings, "; ")
+	}
+	return responses.FormatServiceCreated("api", params.ServiceName, serviceDir, additionalInfo, &CreateAPIServiceResult{
+		ServiceType:  "api",
+		ServiceName:  params.ServiceName,
+		OutputDir:    serviceDir,
+		Port:         port,
+		Style:        style,
+		Warnings:     warnings,
+		FixedImports: fixedImports,
+	})
+}
+
+// failStep logs a failed step to the client and returns the error response,
+// with diagnostics if the step's output was recognized
+func failStep(reporter *progress.Reporter, message string, cause error) (*mcp.CallToolResult, any, error) {
+	result, data, err := commandFailure(message, cause)
+	reporter.Log(mcp.LoggingLevel("error"), "progress", err.Error())
+	return result, data, err
 }
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"text/template"
 
@@ -14,6 +13,7 @@ import (
 
 	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
 	"github.com/jinguoxing/mcp-gozero/internal/templates"
 	"github.com/jinguoxing/mcp-gozero/internal/validation"
 )
@@ -24,6 +24,14 @@ type CreateAPISpecParams struct {
 	OutputPath    string `json:"output_path,omitempty"`
 }
 
+// CreateAPISpecResult is the structured result of create_api_spec
+type CreateAPISpecResult struct {
+	ServiceName   string `json:"service_name"`
+	OutputPath    string `json:"output_path"`
+	EndpointCount int    `json:"endpoint_count"`
+	TypeCount     int    `json:"type_count"`
+}
+
 type EndpointInput struct {
 	Method   string                 `json:"method"`
 	Path     string                 `json:"path"`
@@ -34,7 +42,7 @@ type EndpointInput struct {
 
 func CreateAPISpec(ctx context.Context, req *mcp.CallToolRequest, params CreateAPISpecParams) (*mcp.CallToolResult, any, error) {
 	if err := validation.ValidateServiceName(params.ServiceName); err != nil {
-		return responses.FormatValidationError("service_name", params.ServiceName, err.Error(), "Use lowercase letters, numbers, and hyphens only")
+		return responses.FormatValidationErrorWithCause("service_name", params.ServiceName, err, "Use lowercase letters, numbers, and hyphens only")
 	}
 
 	if params.EndpointsJSON == "" {
@@ -43,7 +51,7 @@ func CreateAPISpec(ctx context.Context, req *mcp.CallToolRequest, params CreateA
 
 	var endpointInputs []EndpointInput
 	if err := json.Unmarshal([]byte(params.EndpointsJSON), &endpointInputs); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to parse endpoints_json: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse endpoints_json: %v", err), err)
 	}
 
 	if len(endpointInputs) == 0 {
@@ -54,9 +62,9 @@ func CreateAPISpec(ctx context.Context, req *mcp.CallToolRequest, params CreateA
 	if outputPath == "" {
 		outputPath = fmt.Sprintf("%s.api", params.ServiceName)
 	}
-	if !filepath.IsAbs(outputPath) {
-		cwd, _ := os.Getwd()
-		outputPath = filepath.Join(cwd, outputPath)
+	outputPath, err := pathScope(ctx, req).Resolve(outputPath, sandbox.Write)
+	if err != nil {
+		return formatPathError("output_path", params.OutputPath, err)
 	}
 
 	spec := templates.APISpec{
@@ -105,21 +113,21 @@ func CreateAPISpec(ctx context.Context, req *mcp.CallToolRequest, params CreateA
 
 	tmpl, err := template.New("api").Parse(templates.APISpecTemplate)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to parse template: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse template: %v", err), err)
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, spec); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to generate spec: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to generate spec: %v", err), err)
 	}
 
 	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to write spec file: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to write spec file: %v", err), err)
 	}
 
 	if _, err := analyzer.ParseAPISpecification(outputPath); err != nil {
 		os.Remove(outputPath)
-		return responses.FormatError(fmt.Sprintf("generated spec is invalid: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("generated spec is invalid: %v", err), err)
 	}
 
 	message := fmt.Sprintf("Successfully created API specification: %s\n\nOutput file: %s\n", params.ServiceName, outputPath)
@@ -130,11 +138,11 @@ func CreateAPISpec(ctx context.Context, req *mcp.CallToolRequest, params CreateA
 	message += "  2. Use generate_api_from_spec to generate code\n"
 	message += fmt.Sprintf("  3. goctl api go -api %s -dir ./output\n", outputPath)
 
-	data := map[string]any{
-		"service_name":   params.ServiceName,
-		"output_path":    outputPath,
-		"endpoint_count": len(spec.Endpoints),
-		"type_count":     len(spec.Types),
+	data := &CreateAPISpecResult{
+		ServiceName:   params.ServiceName,
+		OutputPath:    outputPath,
+		EndpointCount: len(spec.Endpoints),
+		TypeCount:     len(spec.Types),
 	}
 
 	return responses.FormatSuccessWithData(message, data)
@@ -12,6 +12,7 @@ import (
 	"github.com/jinguoxing/mcp-gozero/internal/fixer"
 	"github.com/jinguoxing/mcp-gozero/internal/goctl"
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
 	"github.com/jinguoxing/mcp-gozero/internal/validation"
 )
 
@@ -20,83 +21,104 @@ type CreateRPCServiceParams struct {
 	ProtoContent string `json:"proto_content"`
 	OutputDir    string `json:"output_dir,omitempty"`
 	Style        string `json:"style,omitempty"`
+	Multiple     bool   `json:"multiple,omitempty"` // proto file declares several services (goctl --multiple)
+}
+
+// CreateRPCServiceResult is the structured result of create_rpc_service
+type CreateRPCServiceResult struct {
+	ServiceType  string             `json:"service_type"`
+	ServiceName  string             `json:"service_name"`
+	OutputDir    string             `json:"output_dir"`
+	Style        string             `json:"style"`
+	MethodCount  int                `json:"method_count"`
+	MessageCount int                `json:"message_count"`
+	Warnings     []string           `json:"warnings,omitempty"`
+	FixedImports []fixer.FileChange `json:"fixed_imports,omitempty"`
 }
 
 func CreateRPCService(ctx context.Context, req *mcp.CallToolRequest, params CreateRPCServiceParams) (*mcp.CallToolResult, any, error) {
 	if err := validation.ValidateServiceName(params.ServiceName); err != nil {
-		return responses.FormatValidationError("service_name", params.ServiceName, err.Error(), "Use lowercase letters, numbers, and hyphens only")
+		return responses.FormatValidationErrorWithCause("service_name", params.ServiceName, err, "Use lowercase letters, numbers, and hyphens only")
 	}
 
+	cfg := ServerConfig()
+
 	style := params.Style
 	if style == "" {
-		style = "go_zero"
+		style = cfg.Style
 	}
 
 	outputDir := params.OutputDir
 	if outputDir == "" {
 		outputDir = "."
 	}
+	// Only the service directory is written and tracked, not all of outputDir
+	scope := pathScope(ctx, req)
+	outputDir, err := scope.Resolve(outputDir, sandbox.Read)
+	if err != nil {
+		return formatPathError("output_dir", params.OutputDir, err)
+	}
 	if err := validation.ValidateOutputDir(outputDir); err != nil {
-		return responses.FormatValidationError("output_dir", outputDir, err.Error(), "Provide an absolute path to an existing writable directory")
+		return responses.FormatValidationErrorWithCause("output_dir", outputDir, err, "Provide an absolute path to an existing writable directory")
 	}
 
 	serviceDir := filepath.Join(outputDir, params.ServiceName)
+	if _, err := scope.Resolve(serviceDir, sandbox.Write); err != nil {
+		return formatPathError("service_name", params.ServiceName, err)
+	}
 
 	// Write proto file to output directory (not temp) so protoc can find it
 	protoFile := filepath.Join(outputDir, params.ServiceName+".proto")
 	if err := os.WriteFile(protoFile, []byte(params.ProtoContent), 0644); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to write proto file: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to write proto file: %v", err), err)
 	}
 	defer os.Remove(protoFile)
 
 	spec, err := analyzer.ParseProtoSpecification(protoFile)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to parse proto specification: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse proto specification: %v", err), err)
 	}
 
-	executor, err := goctl.NewExecutor()
+	executor, err := NewExecutor()
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create executor: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to create executor: %v", err), err)
 	}
 
 	// Use relative path for proto file and execute in outputDir
-	args := []string{
-		"rpc",
-		"protoc",
-		params.ServiceName + ".proto",
-		"--go_out=.",
-		"--go-grpc_out=.",
-		"--zrpc_out=.",
-		"--style", style,
+	caps := executor.Capabilities(ctx)
+	args, err := caps.RPCArgs(params.ServiceName+".proto", style, params.Multiple)
+	if err != nil {
+		return responses.FormatValidationErrorWithCause("multiple", "true", err, "Upgrade goctl or split the proto file into one service per file")
 	}
 
-	result := executor.ExecuteInDir(outputDir, args...)
+	result := executor.Run(ctx, outputDir, nil, args...)
 	if result.Error != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create RPC service: %v\nStderr: %s", result.Error, result.Stderr))
+		return commandFailure(commandErrorMessage("failed to create RPC service", result.Error), result.Error)
 	}
 
 	// Use a proper module path format (avoid module names starting with numbers)
-	moduleName := "github.com/example/" + params.ServiceName
+	moduleName := cfg.ModulePath(params.ServiceName)
 
-	if err := fixer.FixImports(serviceDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to fix imports: %v", err))
+	fixedImports, err := fixer.FixImports(serviceDir, moduleName)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to fix imports: %v", err), err)
 	}
 
-	if err := fixer.InitializeGoModule(serviceDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to initialize Go module: %v", err))
+	if err := fixer.InitializeGoModule(ctx, serviceDir, moduleName); err != nil {
+		return commandFailure(commandErrorMessage("failed to initialize Go module", err), err)
 	}
 
-	if err := fixer.TidyGoModule(serviceDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to tidy Go module: %v", err))
+	if err := fixer.TidyGoModule(ctx, serviceDir); err != nil {
+		return commandFailure(commandErrorMessage("failed to tidy Go module", err), err)
 	}
 
-	if err := fixer.VerifyBuild(serviceDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to verify build: %v", err))
+	if err := fixer.VerifyBuild(ctx, serviceDir); err != nil {
+		return commandFailure(commandErrorMessage("failed to verify build", err), err)
 	}
 
 	validator := goctl.NewValidator()
 	if err := validator.ValidateServiceProject(serviceDir, "rpc"); err != nil {
-		return responses.FormatError(fmt.Sprintf("project structure validation failed: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("project structure validation failed: %v", err), err)
 	}
 
 	message := fmt.Sprintf("Successfully created RPC service '%s'\n\nOutput directory: %s\n", params.ServiceName, serviceDir)
@@ -110,18 +132,24 @@ func CreateRPCService(ctx context.Context, req *mcp.CallToolRequest, params Crea
 		message += fmt.Sprintf("  %s(%s) returns (%s)%s\n", method.Name, method.Request, method.Response, streamInfo)
 	}
 	message += fmt.Sprintf("\nMessages: %d\n", len(spec.Messages))
+	warnings := goctlWarnings(caps, serviceDir)
+	for _, warning := range warnings {
+		message += fmt.Sprintf("\n⚠️ %s\n", warning)
+	}
 	message += "\nNext steps:\n"
 	message += fmt.Sprintf("  1. cd %s\n", serviceDir)
 	message += "  2. go mod tidy\n"
 	message += "  3. go run .\n"
 
-	data := map[string]any{
-		"service_type":  "rpc",
-		"service_name":  params.ServiceName,
-		"output_dir":    serviceDir,
-		"style":         style,
-		"method_count":  len(spec.Methods),
-		"message_count": len(spec.Messages),
+	data := &CreateRPCServiceResult{
+		ServiceType:  "rpc",
+		ServiceName:  params.ServiceName,
+		OutputDir:    serviceDir,
+		Style:        style,
+		MethodCount:  len(spec.Methods),
+		MessageCount: len(spec.Messages),
+		Warnings:     warnings,
+		FixedImports: fixedImports,
 	}
 
 	return responses.FormatSuccessWithData(message, data)
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
+	"github.com/jinguoxing/mcp-gozero/internal/goctl"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+)
+
+type DiagnoseGoctlParams struct {
+	ProjectPath string `json:"project_path,omitempty"` // compare goctl with the go-zero version of this project
+}
+
+// DiagnoseGoctlResult is the structured result of diagnose_goctl
+type DiagnoseGoctlResult struct {
+	Found         bool               `json:"found"`
+	GoctlPath     string             `json:"goctl_path,omitempty"`
+	Version       string             `json:"version,omitempty"`
+	Detected      bool               `json:"detected"`
+	Capabilities  []CapabilityStatus `json:"capabilities"`
+	GoModDir      string             `json:"go_mod_dir,omitempty"`
+	GoZeroVersion string             `json:"go_zero_version,omitempty"`
+	Compatible    bool               `json:"compatible"`
+	Warnings      []string           `json:"warnings"`
+}
+
+// CapabilityStatus reports whether the discovered goctl has a feature
+type CapabilityStatus struct {
+	Name        string `json:"name"`
+	Since       string `json:"since"`
+	Supported   bool   `json:"supported"`
+	Description string `json:"description"`
+}
+
+// DiagnoseGoctl reports the discovered goctl, its version and the argument
+// variants the tools use with it, and checks it against a project's go-zero
+func DiagnoseGoctl(ctx context.Context, req *mcp.CallToolRequest, params DiagnoseGoctlParams) (*mcp.CallToolResult, any, error) {
+	data := &DiagnoseGoctlResult{Capabilities: []CapabilityStatus{}, Warnings: []string{}}
+
+	if params.ProjectPath != "" {
+		projectPath, err := pathScope(ctx, req).Resolve(params.ProjectPath, sandbox.Read)
+		if err != nil {
+			return formatPathError("project_path", params.ProjectPath, err)
+		}
+		version, dir, err := analyzer.FindGoZeroVersion(projectPath)
+		if err != nil {
+			return responses.FormatErrorWithCause(fmt.Sprintf("failed to read go.mod: %v", err), err)
+		}
+		data.GoZeroVersion = version
+		data.GoModDir = dir
+		if dir == "" {
+			data.Warnings = append(data.Warnings, fmt.Sprintf("no go.mod found in %s or its parents", projectPath))
+		} else if version == "" {
+			data.Warnings = append(data.Warnings, fmt.Sprintf("%s/go.mod does not require go-zero", dir))
+		}
+	}
+
+	var message strings.Builder
+	message.WriteString("=== goctl ===\n")
+
+	executor, err := NewExecutor()
+	if err != nil {
+		data.Warnings = append(data.Warnings, err.Error())
+		fmt.Fprintf(&message, "❌ %v\n", err)
+		return responses.FormatSuccessWithData(message.String(), data)
+	}
+	data.Found = true
+	data.GoctlPath = executor.GetPath()
+
+	caps := executor.Capabilities(ctx)
+	data.Detected = caps.Detected
+	fmt.Fprintf(&message, "Path: %s\n", data.GoctlPath)
+	if caps.Detected {
+		data.Version = caps.Version.String()
+		fmt.Fprintf(&message, "Version: %s\n", data.Version)
+	} else {
+		fmt.Fprintf(&message, "Version: ⚠️ unknown (%s)\n", caps.Error)
+	}
+	data.Warnings = append(data.Warnings, goctlWarnings(caps, data.GoModDir)...)
+
+	message.WriteString("\n=== Capabilities ===\n")
+	for _, capability := range goctl.Matrix {
+		status := CapabilityStatus{
+			Name:        capability.Name,
+			Since:       capability.Since.String(),
+			Supported:   caps.Has(capability.Name),
+			Description: capability.Description,
+		}
+		data.Capabilities = append(data.Capabilities, status)
+		mark := "✅"
+		if !status.Supported {
+			mark = "❌"
+		}
+		fmt.Fprintf(&message, "%s %s (since %s): %s\n", mark, status.Name, status.Since, status.Description)
+	}
+	fmt.Fprintf(&message, "\nStyle flag: %s\n", caps.Flag("style"))
+
+	if data.GoModDir != "" {
+		message.WriteString("\n=== Project ===\n")
+		fmt.Fprintf(&message, "go.mod: %s\n", data.GoModDir)
+		if data.GoZeroVersion != "" {
+			fmt.Fprintf(&message, "go-zero: %s\n", data.GoZeroVersion)
+		}
+	}
+
+	data.Compatible = caps.Detected && caps.CompatibilityWarning(data.GoZeroVersion) == ""
+	if len(data.Warnings) > 0 {
+		message.WriteString("\n=== ⚠️ Warnings ===\n")
+		for _, warning := range data.Warnings {
+			fmt.Fprintf(&message, "- %s\n", warning)
+		}
+	} else {
+		message.WriteString("\n✅ No compatibility problems found\n")
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
+
+// goctlWarnings describes goctl problems worth reporting next to generated
+// code: an undetected version, or a go-zero in the project at dir whose
+// major/minor version differs from goctl's
+func goctlWarnings(caps goctl.Capabilities, dir string) []string {
+	var warnings []string
+	if !caps.Detected {
+		warnings = append(warnings, fmt.Sprintf("could not detect the goctl version, assuming a current release (%s)", caps.Error))
+	}
+	if dir == "" {
+		return warnings
+	}
+	if version, _, err := analyzer.FindGoZeroVersion(dir); err == nil {
+		if warning := caps.CompatibilityWarning(version); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/security"
+	"github.com/jinguoxing/mcp-gozero/internal/validation"
+)
+
+type DiffConfigsParams struct {
+	ConfigPaths  []string `json:"config_paths"`           // base config first, e.g. etc/user-api.yaml
+	Environments []string `json:"environments,omitempty"` // optional, same order as config_paths
+}
+
+// DiffConfigsResult is the structured result of diff_configs; config values are redacted
+type DiffConfigsResult struct {
+	ConfigCount         int                 `json:"config_count"`
+	Diffs               []ConfigDiffResult  `json:"diffs"`
+	Risks               []PromotionRisk     `json:"risks"`
+	RiskCount           int                 `json:"risk_count"`
+	MissingInProduction map[string][]string `json:"missing_in_production"`
+}
+
+// ConfigDiffResult lists the keys that differ between a base and a target config
+type ConfigDiffResult struct {
+	Base    string              `json:"base"`
+	Target  string              `json:"target"`
+	Added   []string            `json:"added"`
+	Removed []string            `json:"removed"`
+	Changed []ConfigValueChange `json:"changed"`
+}
+
+// ConfigValueChange is a key whose value differs between two configs
+type ConfigValueChange struct {
+	Key  string `json:"key"`
+	From any    `json:"from"`
+	To   any    `json:"to"`
+}
+
+// PromotionRisk is a config value that is unsafe to promote to production
+type PromotionRisk struct {
+	ConfigPath string `json:"config_path"`
+	Field      string `json:"field"`
+	Value      any    `json:"value"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// DiffConfigs compares environment configs of one service and reports promotion risks
+func DiffConfigs(ctx context.Context, req *mcp.CallToolRequest, params DiffConfigsParams) (*mcp.CallToolResult, any, error) {
+	if len(params.ConfigPaths) < 2 {
+		return responses.FormatValidationError("config_paths", strings.Join(params.ConfigPaths, ","),
+			"at least two config files are required", "Provide e.g. etc/user-api.yaml and etc/user-api-production.yaml")
+	}
+
+	if len(params.Environments) > 0 && len(params.Environments) != len(params.ConfigPaths) {
+		return responses.FormatValidationError("environments", strings.Join(params.Environments, ","),
+			"environments must match config_paths one-to-one", "Omit environments to detect them from file names")
+	}
+
+	scope := pathScope(ctx, req)
+	configs := make([]validation.EnvironmentConfig, 0, len(params.ConfigPaths))
+	for i, path := range params.ConfigPaths {
+		absPath, err := scope.Resolve(path, sandbox.Read)
+		if err != nil {
+			return formatPathError("config_paths", path, err)
+		}
+
+		loaded, err := validation.LoadConfigFile(absPath, validation.ConfigLoadOptions{})
+		if err != nil {
+			return responses.FormatErrorWithCause(fmt.Sprintf("%s: %v", absPath, err), err)
+		}
+
+		environment := validation.DetectEnvironment(absPath)
+		if len(params.Environments) > 0 && params.Environments[i] != "" {
+			environment = validation.NormalizeEnvironment(params.Environments[i])
+		}
+
+		configs = append(configs, validation.EnvironmentConfig{
+			Path:        absPath,
+			Environment: environment,
+			Values:      loaded.Values,
+		})
+	}
+
+	report := validation.DiffConfigs(configs)
+
+	var message strings.Builder
+	message.WriteString("Configuration Diff\n\n")
+	message.WriteString("=== Configs ===\n")
+	for _, cfg := range report.Configs {
+		message.WriteString(fmt.Sprintf("  - %s (%s)\n", cfg.Path, cfg.Environment))
+	}
+	message.WriteString("\n")
+
+	for _, diff := range report.Diffs {
+		message.WriteString(fmt.Sprintf("=== %s → %s ===\n", filepath.Base(diff.BasePath), filepath.Base(diff.TargetPath)))
+		if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0 {
+			message.WriteString("  No differences\n\n")
+			continue
+		}
+		for _, key := range diff.Added {
+			message.WriteString(fmt.Sprintf("  + %s\n", key))
+		}
+		for _, key := range diff.Removed {
+			message.WriteString(fmt.Sprintf("  - %s\n", key))
+		}
+		for _, change := range diff.Changed {
+			message.WriteString(fmt.Sprintf("  ~ %s: %v → %v\n", change.Key,
+				security.RedactConfigValue(change.Key, change.From), security.RedactConfigValue(change.Key, change.To)))
+		}
+		message.WriteString("\n")
+	}
+
+	if len(report.Risks) > 0 {
+		message.WriteString("=== Promotion Risks ===\n")
+		for _, risk := range report.Risks {
+			message.WriteString(fmt.Sprintf("  ⚠️  %s [%s]: %s\n", risk.Field, filepath.Base(risk.Path), risk.Message))
+			message.WriteString(fmt.Sprintf("     Current value: %v\n", security.RedactConfigValue(risk.Field, risk.Value)))
+			if risk.Suggestion != "" {
+				message.WriteString(fmt.Sprintf("     Suggestion: %s\n", risk.Suggestion))
+			}
+		}
+		message.WriteString("\n")
+	}
+
+	missingCount := 0
+	if len(report.MissingInProduction) > 0 {
+		message.WriteString("=== Missing in Production ===\n")
+		for _, cfg := range report.Configs {
+			missing := report.MissingInProduction[cfg.Path]
+			if len(missing) == 0 {
+				continue
+			}
+			message.WriteString(fmt.Sprintf("  %s:\n", filepath.Base(cfg.Path)))
+			for _, key := range missing {
+				message.WriteString(fmt.Sprintf("    - %s\n", key))
+			}
+			missingCount += len(missing)
+		}
+		message.WriteString("\n")
+	}
+
+	if len(report.Risks) == 0 && missingCount == 0 {
+		message.WriteString("✅ No promotion risks detected.\n")
+	} else {
+		message.WriteString("⚠️  Review the risks above before promoting to production.\n")
+	}
+
+	diffs := make([]ConfigDiffResult, 0, len(report.Diffs))
+	for _, diff := range report.Diffs {
+		changed := make([]ConfigValueChange, 0, len(diff.Changed))
+		for _, change := range diff.Changed {
+			changed = append(changed, ConfigValueChange{
+				Key:  change.Key,
+				From: security.RedactConfigValue(change.Key, change.From),
+				To:   security.RedactConfigValue(change.Key, change.To),
+			})
+		}
+		diffs = append(diffs, ConfigDiffResult{
+			Base:    diff.BasePath,
+			Target:  diff.TargetPath,
+			Added:   append([]string{}, diff.Added...),
+			Removed: append([]string{}, diff.Removed...),
+			Changed: changed,
+		})
+	}
+
+	risks := make([]PromotionRisk, 0, len(report.Risks))
+	for _, risk := range report.Risks {
+		risks = append(risks, PromotionRisk{
+			ConfigPath: risk.Path,
+			Field:      risk.Field,
+			Value:      security.RedactConfigValue(risk.Field, risk.Value),
+			Message:    risk.Message,
+			Suggestion: risk.Suggestion,
+		})
+	}
+
+	data := &DiffConfigsResult{
+		ConfigCount:         len(report.Configs),
+		Diffs:               diffs,
+		Risks:               risks,
+		RiskCount:           len(report.Risks),
+		MissingInProduction: report.MissingInProduction,
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/doctor"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+)
+
+type DoctorParams struct {
+	Dir string `json:"dir,omitempty"` // directory to check for write access (default: current directory)
+}
+
+// DoctorResult is the structured result of doctor
+type DoctorResult struct {
+	Passed bool          `json:"passed"`
+	Failed int           `json:"failed"`
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// DoctorCheck is one item of the doctor checklist
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // pass, warn or fail
+	Detail  string `json:"detail"`
+	Fix     string `json:"fix,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version,omitempty"`
+	Minimum string `json:"minimum,omitempty"`
+}
+
+// Doctor checks the toolchain and environment the generation tools need and
+// returns a pass/fail checklist with a fix for every problem
+func Doctor(ctx context.Context, req *mcp.CallToolRequest, params DoctorParams) (*mcp.CallToolResult, any, error) {
+	dir := params.Dir
+	if dir == "" {
+		dir = "."
+	}
+	// The write access check creates and removes a file in dir, so dir needs
+	// write access; it leaves nothing behind, so dir isn't tracked for undo
+	var session *mcp.ServerSession
+	if req != nil {
+		session = req.Session
+	}
+	dir, err := Sandbox.Scope(ctx, session).Resolve(dir, sandbox.Write)
+	if err != nil {
+		return formatPathError("dir", params.Dir, err)
+	}
+
+	checks := doctor.Run(ctx, doctor.Options{Dirs: []string{dir}})
+	data := &DoctorResult{
+		Failed: doctor.Failed(checks),
+		Checks: []DoctorCheck{},
+	}
+	data.Passed = data.Failed == 0
+	for _, check := range checks {
+		data.Checks = append(data.Checks, DoctorCheck{
+			Name:    check.Name,
+			Status:  check.Status,
+			Detail:  check.Detail,
+			Fix:     check.Fix,
+			Path:    check.Path,
+			Version: check.Version,
+			Minimum: check.Minimum,
+		})
+	}
+
+	return responses.FormatSuccessWithData(doctor.Format(checks), data)
+}
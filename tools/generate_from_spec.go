@@ -4,14 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
 	"github.com/jinguoxing/mcp-gozero/internal/fixer"
-	"github.com/jinguoxing/mcp-gozero/internal/goctl"
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
 	"github.com/jinguoxing/mcp-gozero/internal/validation"
 )
 
@@ -22,97 +22,101 @@ type GenerateAPIFromSpecParams struct {
 	Style     string `json:"style,omitempty"`
 }
 
+// GenerateAPIFromSpecResult is the structured result of generate_api_from_spec
+type GenerateAPIFromSpecResult struct {
+	ServiceName   string             `json:"service_name"`
+	APIFile       string             `json:"api_file"`
+	OutputDir     string             `json:"output_dir"`
+	Style         string             `json:"style"`
+	EndpointCount int                `json:"endpoint_count"`
+	TypeCount     int                `json:"type_count"`
+	Warnings      []string           `json:"warnings,omitempty"`
+	FixedImports  []fixer.FileChange `json:"fixed_imports,omitempty"`
+}
+
 // GenerateAPIFromSpec generates go-zero API code from API specification file (T044-T046)
 func GenerateAPIFromSpec(ctx context.Context, req *mcp.CallToolRequest, params GenerateAPIFromSpecParams) (*mcp.CallToolResult, any, error) {
 	// T040: Validate API file exists
-	apiFile := params.APIFile
-	if !filepath.IsAbs(apiFile) {
-		cwd, _ := os.Getwd()
-		apiFile = filepath.Join(cwd, apiFile)
+	scope := pathScope(ctx, req)
+	apiFile, err := scope.Resolve(params.APIFile, sandbox.Read)
+	if err != nil {
+		return formatPathError("api_file", params.APIFile, err)
 	}
 
 	if _, err := os.Stat(apiFile); os.IsNotExist(err) {
-		return responses.FormatError(fmt.Sprintf("API file not found: %s", apiFile))
+		return responses.FormatErrorWithCause(fmt.Sprintf("API file not found: %s", apiFile), mcperrors.NewPathError(apiFile, "stat", "API file not found", err))
 	}
 
 	// T041: Parse API specification to extract metadata
 	spec, err := analyzer.ParseAPISpecification(apiFile)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to parse API specification: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse API specification: %v", err), err)
 	}
 
 	// T042: Validate output directory
-	outputDir := params.OutputDir
-	if outputDir == "" {
-		outputDir, _ = os.Getwd()
-	}
-	if !filepath.IsAbs(outputDir) {
-		cwd, _ := os.Getwd()
-		outputDir = filepath.Join(cwd, outputDir)
+	outputDir, err := scope.Resolve(params.OutputDir, sandbox.Write)
+	if err != nil {
+		return formatPathError("output_dir", params.OutputDir, err)
 	}
 
 	if err := validation.ValidateOutputDir(outputDir); err != nil {
-		return responses.FormatValidationError("output_dir", outputDir, err.Error(), "Provide an absolute path to an existing writable directory")
+		return responses.FormatValidationErrorWithCause("output_dir", outputDir, err, "Provide an absolute path to an existing writable directory")
 	}
 
 	// T043: Set code style (default: detect existing or use go_zero)
 	style := params.Style
 	if style == "" {
 		// Try to detect existing style to avoid conflicts
-		style = fixer.SuggestStyleBasedOnExisting(outputDir, "go_zero")
+		style = fixer.SuggestStyleBasedOnExisting(outputDir, ServerConfig().Style)
 	}
-	if style != "go_zero" && style != "gozero" {
-		return responses.FormatValidationError("style", style, "invalid style", "Use 'go_zero' or 'gozero'")
+	if !fixer.ValidStyle(style) {
+		return responses.FormatValidationError("style", style, "invalid style", "Use 'go_zero', 'gozero' or 'goZero'")
 	}
 
 	// Clean up any existing style conflicts before generating
 	if err := fixer.CleanupStyleConflicts(outputDir, style); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to cleanup style conflicts: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to cleanup style conflicts: %v", err), err)
 	}
 
 	// T044: Execute goctl api go command
-	executor, err := goctl.NewExecutor()
+	executor, err := NewExecutor()
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create executor: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to create executor: %v", err), err)
 	}
 
-	args := []string{
-		"api",
-		"go",
-		"-api", apiFile,
-		"-dir", outputDir,
-		"-style", style,
-	}
+	caps := executor.Capabilities(ctx)
+	args := caps.APIGoArgs(apiFile, outputDir, style)
 
-	result := executor.Execute(args...)
+	result := executor.Run(ctx, "", nil, args...)
 	if result.Error != nil {
-		return responses.FormatError(fmt.Sprintf("failed to generate API code: %v\nStderr: %s", result.Error, result.Stderr))
+		return commandFailure(commandErrorMessage("failed to generate API code", result.Error), result.Error)
 	}
 
 	// Get module name from service name
 	moduleName := spec.ServiceName
 
 	// T045: Fix imports and initialize modules
-	if err := fixer.FixImports(outputDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to fix imports: %v", err))
+	fixedImports, err := fixer.FixImports(outputDir, moduleName)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to fix imports: %v", err), err)
 	}
 
-	if err := fixer.InitializeGoModule(outputDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to initialize Go module: %v", err))
+	if err := fixer.InitializeGoModule(ctx, outputDir, moduleName); err != nil {
+		return commandFailure(commandErrorMessage("failed to initialize Go module", err), err)
 	}
 
-	if err := fixer.TidyGoModule(outputDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to tidy Go module: %v", err))
+	if err := fixer.TidyGoModule(ctx, outputDir); err != nil {
+		return commandFailure(commandErrorMessage("failed to tidy Go module", err), err)
 	}
 
 	// Validate no style conflicts after generation
 	if err := fixer.ValidateNoStyleConflicts(outputDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("style conflicts detected after generation: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("style conflicts detected after generation: %v", err), err)
 	}
 
 	// T046: Verify build success
-	if err := fixer.VerifyBuild(outputDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to verify build: %v", err))
+	if err := fixer.VerifyBuild(ctx, outputDir); err != nil {
+		return commandFailure(commandErrorMessage("failed to verify build", err), err)
 	}
 
 	// Format success message with endpoint list
@@ -122,18 +126,24 @@ func GenerateAPIFromSpec(ctx context.Context, req *mcp.CallToolRequest, params G
 		message += fmt.Sprintf("  %s %s → %s\n", ep.Method, ep.Path, ep.Handler)
 	}
 	message += fmt.Sprintf("\nTotal types: %d\n", len(spec.Types))
+	warnings := goctlWarnings(caps, outputDir)
+	for _, warning := range warnings {
+		message += fmt.Sprintf("\n⚠️ %s\n", warning)
+	}
 	message += "\nNext steps:\n"
 	message += fmt.Sprintf("  1. cd %s\n", outputDir)
 	message += "  2. go mod tidy\n"
 	message += "  3. go run .\n"
 
-	data := map[string]any{
-		"service_name":   spec.ServiceName,
-		"api_file":       apiFile,
-		"output_dir":     outputDir,
-		"style":          style,
-		"endpoint_count": len(spec.Endpoints),
-		"type_count":     len(spec.Types),
+	data := &GenerateAPIFromSpecResult{
+		ServiceName:   spec.ServiceName,
+		APIFile:       apiFile,
+		OutputDir:     outputDir,
+		Style:         style,
+		EndpointCount: len(spec.Endpoints),
+		TypeCount:     len(spec.Types),
+		Warnings:      warnings,
+		FixedImports:  fixedImports,
 	}
 
 	return responses.FormatSuccessWithData(message, data)
@@ -3,13 +3,12 @@ package tools
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/jinguoxing/mcp-gozero/internal/fixer"
-	"github.com/jinguoxing/mcp-gozero/internal/goctl"
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
 	"github.com/jinguoxing/mcp-gozero/internal/security"
 )
 
@@ -21,6 +20,16 @@ type GenerateModelParams struct {
 	Style      string `json:"style,omitempty"`
 }
 
+// GenerateModelResult is the structured result of generate_model
+type GenerateModelResult struct {
+	SourceType   string             `json:"source_type"`
+	Table        string             `json:"table"`
+	OutputDir    string             `json:"output_dir"`
+	Style        string             `json:"style"`
+	Warnings     []string           `json:"warnings,omitempty"`
+	FixedImports []fixer.FileChange `json:"fixed_imports,omitempty"`
+}
+
 func GenerateModel(ctx context.Context, req *mcp.CallToolRequest, params GenerateModelParams) (*mcp.CallToolResult, any, error) {
 	if params.SourceType != "mysql" && params.SourceType != "postgresql" && params.SourceType != "mongo" {
 		return responses.FormatValidationError("source_type", params.SourceType, "invalid source type", "Use 'mysql', 'postgresql', or 'mongo'")
@@ -36,77 +45,77 @@ func GenerateModel(ctx context.Context, req *mcp.CallToolRequest, params Generat
 
 	outputDir := params.OutputDir
 	if outputDir == "" {
-		outputDir = "./model"
+		outputDir = ServerConfig().Layout.ModelDir
+	}
+	outputDir, err := pathScope(ctx, req).Resolve(outputDir, sandbox.Write)
+	if err != nil {
+		return formatPathError("output_dir", params.OutputDir, err)
 	}
 
 	style := params.Style
 	if style == "" {
-		style = "go_zero"
+		style = ServerConfig().Style
 	}
 
-	var connInfo *security.ConnectionInfo
-	var err error
-
-	connInfo, err = security.ParseConnectionString(params.Source)
+	connInfo, err := security.ParseConnectionString(params.Source)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to parse connection string: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse connection string: %v", err), err)
 	}
 	defer connInfo.Clear()
 
-	executor, err := goctl.NewExecutor()
+	executor, err := NewExecutor()
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create executor: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to create executor: %v", err), err)
 	}
 
-	args := []string{
-		"model",
-		params.SourceType,
-		"datasource",
-		"-url", connInfo.ToDSN(),
-		"-table", params.Table,
-		"-dir", outputDir,
-		"-style", style,
-	}
+	caps := executor.Capabilities(ctx)
+	args := caps.ModelArgs(params.SourceType, connInfo.ToDSN(), params.Table, outputDir, style)
 
-	result := executor.Execute(args...)
+	result := executor.Run(ctx, "", nil, args...)
 	if result.Error != nil {
 		connInfo.Clear()
-		return responses.FormatError(fmt.Sprintf("failed to generate model: %v\nStderr: %s", result.Error, result.Stderr))
+		return commandFailure(commandErrorMessage("failed to generate model", result.Error), result.Error)
 	}
 
 	connInfo.Clear()
 
 	moduleName := "model"
-	if err := fixer.FixImports(outputDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to fix imports: %v", err))
+	fixedImports, err := fixer.FixImports(outputDir, moduleName)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to fix imports: %v", err), err)
 	}
 
-	if err := fixer.InitializeGoModule(outputDir, moduleName); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to initialize Go module: %v", err))
+	if err := fixer.InitializeGoModule(ctx, outputDir, moduleName); err != nil {
+		return commandFailure(commandErrorMessage("failed to initialize Go module", err), err)
 	}
 
-	if err := fixer.TidyGoModule(outputDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to tidy Go module: %v", err))
+	if err := fixer.TidyGoModule(ctx, outputDir); err != nil {
+		return commandFailure(commandErrorMessage("failed to tidy Go module", err), err)
 	}
 
-	if err := fixer.VerifyBuild(outputDir); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to verify build: %v", err))
+	if err := fixer.VerifyBuild(ctx, outputDir); err != nil {
+		return commandFailure(commandErrorMessage("failed to verify build", err), err)
 	}
 
 	message := fmt.Sprintf("Successfully generated database model for table '%s'\n\nOutput directory: %s\n", params.Table, outputDir)
 	message += fmt.Sprintf("\nSource Type: %s\n", params.SourceType)
 	message += fmt.Sprintf("Table: %s\n", params.Table)
+	warnings := goctlWarnings(caps, outputDir)
+	for _, warning := range warnings {
+		message += fmt.Sprintf("\n⚠️ %s\n", warning)
+	}
 	message += "\nNext steps:\n"
 	message += fmt.Sprintf("  1. cd %s\n", outputDir)
 	message += "  2. Review generated model code\n"
 	message += "  3. Integrate with your service\n"
 
-	absPath, _ := filepath.Abs(outputDir)
-	data := map[string]any{
-		"source_type": params.SourceType,
-		"table":       params.Table,
-		"output_dir":  absPath,
-		"style":       style,
+	data := &GenerateModelResult{
+		SourceType:   params.SourceType,
+		Table:        params.Table,
+		OutputDir:    outputDir,
+		Style:        style,
+		Warnings:     warnings,
+		FixedImports: fixedImports,
 	}
 
 	return responses.FormatSuccessWithData(message, data)
@@ -1,17 +1,19 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"go/format"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
 	"github.com/jinguoxing/mcp-gozero/internal/templates"
 )
 
@@ -22,6 +24,14 @@ type GenerateTemplateParams struct {
 	OutputPath   string `json:"output_path,omitempty"`
 }
 
+// GenerateTemplateResult is the structured result of generate_template
+type GenerateTemplateResult struct {
+	TemplateType string `json:"template_type"`
+	TemplateName string `json:"template_name"`
+	OutputPath   string `json:"output_path"`
+	FileSize     int    `json:"file_size"`
+}
+
 // GenerateTemplate generates code templates for common patterns
 func GenerateTemplate(ctx context.Context, req *mcp.CallToolRequest, params GenerateTemplateParams) (*mcp.CallToolResult, any, error) {
 	if params.TemplateType == "" {
@@ -58,7 +68,7 @@ func GenerateTemplate(ctx context.Context, req *mcp.CallToolRequest, params Gene
 	templateParams := make(map[string]interface{})
 	if params.Parameters != "" {
 		if err := json.Unmarshal([]byte(params.Parameters), &templateParams); err != nil {
-			return responses.FormatError(fmt.Sprintf("failed to parse parameters JSON: %v", err))
+			return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse parameters JSON: %v", err), err)
 		}
 	}
 
@@ -72,7 +82,7 @@ func GenerateTemplate(ctx context.Context, req *mcp.CallToolRequest, params Gene
 	// Execute template
 	code, err := templates.ExecuteTemplate(tmpl, templateParams)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to generate template: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to generate template: %v", err), err)
 	}
 
 	// Determine output path
@@ -81,20 +91,20 @@ func GenerateTemplate(ctx context.Context, req *mcp.CallToolRequest, params Gene
 		outputPath = generateDefaultPath(params.TemplateType, params.TemplateName, templateParams)
 	}
 
-	if !filepath.IsAbs(outputPath) {
-		cwd, _ := os.Getwd()
-		outputPath = filepath.Join(cwd, outputPath)
+	outputPath, err = pathScope(ctx, req).Resolve(outputPath, sandbox.Write)
+	if err != nil {
+		return formatPathError("output_path", params.OutputPath, err)
 	}
 
 	// Create directory if needed
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create directory: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to create directory: %v", err), err)
 	}
 
 	// Write file
 	if err := os.WriteFile(outputPath, []byte(code), 0644); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to write file: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to write file: %v", err), err)
 	}
 
 	// Try to verify the generated code compiles (best effort)
@@ -113,11 +123,11 @@ func GenerateTemplate(ctx context.Context, req *mcp.CallToolRequest, params Gene
 	message += compileCheck
 	message += "\n" + getIntegrationInstructions(params.TemplateType, params.TemplateName, templateParams)
 
-	data := map[string]any{
-		"template_type": params.TemplateType,
-		"template_name": params.TemplateName,
-		"output_path":   outputPath,
-		"file_size":     len(code),
+	data := &GenerateTemplateResult{
+		TemplateType: params.TemplateType,
+		TemplateName: params.TemplateName,
+		OutputPath:   outputPath,
+		FileSize:     len(code),
 	}
 
 	return responses.FormatSuccessWithData(message, data)
@@ -251,11 +261,19 @@ Example usage:
 	return ""
 }
 
+// verifyGoFile checks the syntax of a generated Go file and formats it in
+// place with go/format, like go fmt but without running a subprocess
 func verifyGoFile(filePath string) error {
-	// Use go fmt to check syntax
-	cmd := exec.Command("go", "fmt", filePath)
-	if err := cmd.Run(); err != nil {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
 		return fmt.Errorf("syntax check failed: %w", err)
 	}
-	return nil
+	if bytes.Equal(formatted, src) {
+		return nil
+	}
+	return os.WriteFile(filePath, formatted, 0644)
 }
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/serverconfig"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// defaultAuditLimit is the number of events returned when no limit is given
+const defaultAuditLimit = 100
+
+type ListAuditEventsParams struct {
+	Since string `json:"since,omitempty"` // RFC 3339 time or a duration back from now, e.g. 24h
+	Until string `json:"until,omitempty"` // RFC 3339 time or a duration back from now
+	Tool  string `json:"tool,omitempty"`
+	Type  string `json:"type,omitempty"` // file or command
+	Path  string `json:"path,omitempty"` // file events at or below the path and commands run there
+	Limit int    `json:"limit,omitempty"`
+}
+
+// ListAuditEventsResult is the structured result of list_audit_events
+type ListAuditEventsResult struct {
+	AuditLog string        `json:"audit_log"`
+	Matched  int           `json:"matched"`
+	Events   []audit.Event `json:"events"`
+}
+
+// ListAuditEvents returns the most recent audit events matching the filters
+func ListAuditEvents(ctx context.Context, req *mcp.CallToolRequest, params ListAuditEventsParams) (*mcp.CallToolResult, any, error) {
+	if Audit == nil {
+		return responses.FormatError("audit log is disabled; set audit.enabled in " + serverconfig.FileName + " or start the server with --audit-log")
+	}
+
+	filter := audit.Filter{Tool: params.Tool, Type: params.Type, Limit: params.Limit}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Type != "" && filter.Type != audit.TypeFile && filter.Type != audit.TypeCommand {
+		return responses.FormatValidationError("type", params.Type, "invalid event type", "Use 'file' or 'command'")
+	}
+
+	var err error
+	if filter.Since, err = parseAuditTime(params.Since); err != nil {
+		return responses.FormatValidationErrorWithCause("since", params.Since, err, "Use an RFC 3339 time like 2024-05-01T09:00:00Z or a duration like 24h")
+	}
+	if filter.Until, err = parseAuditTime(params.Until); err != nil {
+		return responses.FormatValidationErrorWithCause("until", params.Until, err, "Use an RFC 3339 time like 2024-05-01T09:00:00Z or a duration like 1h")
+	}
+	if params.Path != "" {
+		if filter.Path, err = filepath.Abs(params.Path); err != nil {
+			return responses.FormatValidationErrorWithCause("path", params.Path, err, "Provide a valid file or directory path")
+		}
+	}
+
+	events, matched, err := Audit.Query(filter)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to read audit log: %v", err), err)
+	}
+	if events == nil {
+		events = []audit.Event{}
+	}
+
+	var message strings.Builder
+	message.WriteString("=== Audit Events ===\n")
+	fmt.Fprintf(&message, "Audit log: %s\n", Audit.Path())
+	if matched > len(events) {
+		fmt.Fprintf(&message, "Showing the latest %d of %d matching events\n", len(events), matched)
+	} else {
+		fmt.Fprintf(&message, "Matching events: %d\n", matched)
+	}
+	if len(events) > 0 {
+		message.WriteString("\n")
+	}
+	for _, event := range events {
+		fmt.Fprintf(&message, "%s [%s]", event.Time.Format(time.RFC3339), event.Tool)
+		if event.Session != "" {
+			fmt.Fprintf(&message, " session=%s", event.Session)
+		}
+		switch event.Type {
+		case audit.TypeFile:
+			switch {
+			case event.Action == "":
+				fmt.Fprintf(&message, " ⚠️ %s not fully compared (more than %d files)\n", event.Path, snapshot.MaxFiles)
+			case event.Truncated:
+				fmt.Fprintf(&message, " %s %s (partial snapshot)\n", event.Action, event.Path)
+			default:
+				fmt.Fprintf(&message, " %s %s\n", event.Action, event.Path)
+			}
+		case audit.TypeCommand:
+			status := "✅"
+			if event.ExitCode != nil && *event.ExitCode != 0 {
+				status = fmt.Sprintf("❌ exit %d", *event.ExitCode)
+			}
+			fmt.Fprintf(&message, " %s %s %s (%dms, in %s)\n", status, event.Command, strings.Join(event.Args, " "), event.DurationMs, event.Dir)
+		}
+	}
+
+	data := &ListAuditEventsResult{
+		AuditLog: Audit.Path(),
+		Matched:  matched,
+		Events:   events,
+	}
+	return responses.FormatSuccessWithData(message.String(), data)
+}
+
+// parseAuditTime accepts an RFC 3339 time or a duration back from now
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	return time.Now().Add(-d), nil
+}
@@ -11,16 +11,23 @@ import (
 	"text/template"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"gopkg.in/yaml.v3"
 
+	"github.com/jinguoxing/mcp-gozero/internal/analyzer"
 	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/security"
+	"github.com/jinguoxing/mcp-gozero/internal/serverconfig"
 	"github.com/jinguoxing/mcp-gozero/internal/templates"
 	"github.com/jinguoxing/mcp-gozero/internal/validation"
 )
 
 type ValidateConfigParams struct {
-	ConfigPath  string `json:"config_path"`
-	ServiceType string `json:"service_type,omitempty"` // "api" or "rpc"
+	ConfigPath   string            `json:"config_path"`
+	ServiceType  string            `json:"service_type,omitempty"`  // "api" or "rpc"
+	ExpandEnv    bool              `json:"expand_env,omitempty"`    // expand ${VAR} like go-zero conf.UseEnv()
+	Env          map[string]string `json:"env,omitempty"`           // variables used for expansion
+	EnvFile      string            `json:"env_file,omitempty"`      // .env file used for expansion
+	OutputFormat string            `json:"output_format,omitempty"` // "text" (default), "json" or "sarif"
 }
 
 type GenerateConfigParams struct {
@@ -29,6 +36,40 @@ type GenerateConfigParams struct {
 	Environment string `json:"environment"`  // "development", "production", "test"
 	Port        int    `json:"port,omitempty"`
 	OutputPath  string `json:"output_path,omitempty"`
+	FromStruct  bool   `json:"from_struct,omitempty"`  // build the skeleton from internal/config/config.go
+	ProjectPath string `json:"project_path,omitempty"` // service directory used with from_struct
+	StructFile  string `json:"struct_file,omitempty"`  // defaults to <project_path>/internal/config/config.go
+	StructName  string `json:"struct_name,omitempty"`  // defaults to "Config"
+	Overrides   string `json:"overrides,omitempty"`    // JSON object layered on top of environment defaults
+}
+
+// ValidateConfigResult is the structured result of validate_config
+type ValidateConfigResult struct {
+	ConfigPath         string                        `json:"config_path"`
+	ServiceType        string                        `json:"service_type"`
+	Format             string                        `json:"format"`
+	Valid              bool                          `json:"valid"`
+	ErrorCount         int                           `json:"error_count"`
+	WarningCount       int                           `json:"warning_count"`
+	UndefinedVariables []string                      `json:"undefined_variables,omitempty"`
+	SecretCount        int                           `json:"secret_count,omitempty"`
+	Secrets            []SecretResult                `json:"secrets,omitempty"`
+	Diagnostics        []validation.ConfigDiagnostic `json:"diagnostics"`
+}
+
+// GenerateConfigTemplateResult is the structured result of generate_config_template;
+// the struct fields are only set when the template is built from a Config struct
+type GenerateConfigTemplateResult struct {
+	ServiceName string   `json:"service_name"`
+	ServiceType string   `json:"service_type"`
+	Environment string   `json:"environment"`
+	OutputPath  string   `json:"output_path"`
+	Port        int      `json:"port"`
+	StructFile  string   `json:"struct_file,omitempty"`
+	StructName  string   `json:"struct_name,omitempty"`
+	FieldCount  int      `json:"field_count,omitempty"`
+	UnknownKeys []string `json:"unknown_keys,omitempty"`
+	Fallbacks   []string `json:"fallbacks,omitempty"`
 }
 
 // ValidateConfig validates a go-zero configuration file
@@ -37,36 +78,43 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, params Valida
 		return responses.FormatValidationError("config_path", "", "config_path is required", "Provide path to config file")
 	}
 
-	if !filepath.IsAbs(params.ConfigPath) {
-		absPath, err := filepath.Abs(params.ConfigPath)
-		if err != nil {
-			return responses.FormatError(fmt.Sprintf("failed to resolve config path: %v", err))
-		}
-		params.ConfigPath = absPath
+	outputFormat := strings.ToLower(params.OutputFormat)
+	if outputFormat == "" {
+		outputFormat = "text"
+	}
+	if outputFormat != "text" && outputFormat != "json" && outputFormat != "sarif" {
+		return responses.FormatValidationError("output_format", params.OutputFormat,
+			"unsupported output format", "Use 'text', 'json' or 'sarif'")
 	}
 
-	content, err := os.ReadFile(params.ConfigPath)
+	scope := pathScope(ctx, req)
+	configPath, err := scope.Resolve(params.ConfigPath, sandbox.Read)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to read config file: %v", err))
+		return formatPathError("config_path", params.ConfigPath, err)
 	}
+	params.ConfigPath = configPath
 
-	// Parse config file
-	var config map[string]interface{}
-	ext := strings.ToLower(filepath.Ext(params.ConfigPath))
-
-	switch ext {
-	case ".yaml", ".yml":
-		if err := yaml.Unmarshal(content, &config); err != nil {
-			return responses.FormatError(fmt.Sprintf("failed to parse YAML config: %v", err))
+	envFile := params.EnvFile
+	if envFile != "" {
+		if !filepath.IsAbs(envFile) {
+			envFile = filepath.Join(filepath.Dir(params.ConfigPath), envFile)
 		}
-	case ".json":
-		if err := json.Unmarshal(content, &config); err != nil {
-			return responses.FormatError(fmt.Sprintf("failed to parse JSON config: %v", err))
+		if envFile, err = scope.Resolve(envFile, sandbox.Read); err != nil {
+			return formatPathError("env_file", params.EnvFile, err)
 		}
-	default:
-		return responses.FormatError(fmt.Sprintf("unsupported config file format: %s (use .yaml, .yml, or .json)", ext))
 	}
 
+	// Parse config file, optionally expanding environment variables
+	loaded, err := validation.LoadConfigFile(params.ConfigPath, validation.ConfigLoadOptions{
+		ExpandEnv: params.ExpandEnv || len(params.Env) > 0 || params.EnvFile != "",
+		Env:       params.Env,
+		EnvFile:   envFile,
+	})
+	if err != nil {
+		return responses.FormatErrorWithCause(err.Error(), err)
+	}
+	config := loaded.Values
+
 	// Determine service type
 	serviceType := params.ServiceType
 	if serviceType == "" {
@@ -91,18 +139,73 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, params Valida
 		return responses.FormatError(fmt.Sprintf("unsupported service type: %s (use 'api' or 'rpc')", serviceType))
 	}
 
+	// Undefined variables expand to empty strings in go-zero, which is almost never intended
+	for _, ref := range loaded.References {
+		if ref.Defined {
+			continue
+		}
+		field := ref.Field
+		if field == "" {
+			field = "${" + ref.Variable + "}"
+		}
+		result.Valid = false
+		result.Errors = append(result.Errors, validation.ConfigError{
+			Field:   field,
+			Message: fmt.Sprintf("environment variable '%s' is not defined", ref.Variable),
+			Value:   "${" + ref.Variable + "}",
+		})
+	}
+
+	// Attach line/column to every finding so editors can jump to it
+	result.Locate(loaded.Positions)
+
+	// Detect credentials committed in the file; scan values as written, not as expanded
+	var secrets []security.SecretFinding
+	if loaded.Raw != nil {
+		secrets = security.ScanConfigSecrets(loaded.Raw)
+	} else {
+		result.Warnings = append(result.Warnings, validation.ConfigWarning{
+			Field:      filepath.Base(params.ConfigPath),
+			Message:    "secrets were not scanned: the file only parses after environment expansion",
+			Suggestion: "Quote ${VAR} placeholders so the file parses as written and can be scanned",
+		})
+	}
+
+	diagnostics := result.Diagnostics()
+	for _, finding := range secrets {
+		severity := "warning"
+		if finding.Severity == "high" {
+			severity = "error"
+		}
+		pos, _ := loaded.Positions.Lookup(finding.Field)
+		diagnostics = append(diagnostics, validation.ConfigDiagnostic{
+			RuleID:     "secret-" + finding.Kind,
+			Severity:   severity,
+			Field:      finding.Field,
+			Message:    fmt.Sprintf("%s (value: %s)", finding.Message, finding.Redacted),
+			Suggestion: finding.Suggestion,
+			Line:       pos.Line,
+			Column:     pos.Column,
+		})
+	}
+
 	// Format validation results
 	var message strings.Builder
 	message.WriteString(fmt.Sprintf("Configuration Validation: %s\n\n", params.ConfigPath))
 	message.WriteString(fmt.Sprintf("Service Type: %s\n", serviceType))
+	message.WriteString(fmt.Sprintf("Format: %s\n", loaded.Format))
+	if len(loaded.References) > 0 {
+		message.WriteString(fmt.Sprintf("Environment Variables: %d referenced, %d undefined\n",
+			len(loaded.References), len(loaded.UndefinedVariables())))
+	}
 	message.WriteString(fmt.Sprintf("Valid: %v\n\n", result.Valid))
 
 	if len(result.Errors) > 0 {
 		message.WriteString("=== Errors ===\n")
 		for _, err := range result.Errors {
-			message.WriteString(fmt.Sprintf("  ❌ %s: %s\n", err.Field, err.Message))
+			message.WriteString(fmt.Sprintf("  ❌ %s%s: %s\n", err.Field, formatPosition(err.Line, err.Column), err.Message))
 			if err.Value != nil {
-				message.WriteString(fmt.Sprintf("     Current value: %v\n", err.Value))
+				message.WriteString(fmt.Sprintf("     Current value: %v\n", security.RedactConfigValue(err.Field, err.Value)))
 			}
 		}
 		message.WriteString("\n")
@@ -111,7 +214,7 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, params Valida
 	if len(result.Warnings) > 0 {
 		message.WriteString("=== Warnings ===\n")
 		for _, warn := range result.Warnings {
-			message.WriteString(fmt.Sprintf("  ⚠️  %s: %s\n", warn.Field, warn.Message))
+			message.WriteString(fmt.Sprintf("  ⚠️  %s%s: %s\n", warn.Field, formatPosition(warn.Line, warn.Column), warn.Message))
 			if warn.Suggestion != "" {
 				message.WriteString(fmt.Sprintf("     Suggestion: %s\n", warn.Suggestion))
 			}
@@ -119,7 +222,18 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, params Valida
 		message.WriteString("\n")
 	}
 
-	if result.Valid && len(result.Warnings) == 0 {
+	if len(secrets) > 0 {
+		message.WriteString("=== Secrets ===\n")
+		for _, finding := range secrets {
+			pos, _ := loaded.Positions.Lookup(finding.Field)
+			message.WriteString(fmt.Sprintf("  🔑 %s%s: %s (%s)\n", finding.Field, formatPosition(pos.Line, pos.Column), finding.Message, finding.Severity))
+			message.WriteString(fmt.Sprintf("     Current value: %s\n", finding.Redacted))
+			message.WriteString(fmt.Sprintf("     Suggestion: %s\n", finding.Suggestion))
+		}
+		message.WriteString("\n")
+	}
+
+	if result.Valid && len(result.Warnings) == 0 && len(secrets) == 0 {
 		message.WriteString("✅ Configuration is valid with no warnings!\n")
 	} else if result.Valid {
 		message.WriteString("✅ Configuration is valid but has warnings to address.\n")
@@ -127,12 +241,44 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, params Valida
 		message.WriteString("❌ Configuration has errors that must be fixed.\n")
 	}
 
-	data := map[string]any{
-		"config_path":   params.ConfigPath,
-		"service_type":  serviceType,
-		"valid":         result.Valid,
-		"error_count":   len(result.Errors),
-		"warning_count": len(result.Warnings),
+	data := &ValidateConfigResult{
+		ConfigPath:         params.ConfigPath,
+		ServiceType:        serviceType,
+		Format:             loaded.Format,
+		Valid:              result.Valid,
+		ErrorCount:         len(result.Errors),
+		WarningCount:       len(result.Warnings),
+		UndefinedVariables: loaded.UndefinedVariables(),
+		Diagnostics:        diagnostics,
+	}
+	if len(secrets) > 0 {
+		data.SecretCount = len(secrets)
+		data.Secrets = secretFindingsData(secrets)
+	}
+
+	// Machine-readable formats replace the text report and are returned as-is
+	// so clients and CI can parse the content directly
+	var report []byte
+	switch outputFormat {
+	case "json":
+		report, err = json.MarshalIndent(map[string]any{
+			"config_path":  params.ConfigPath,
+			"service_type": serviceType,
+			"format":       loaded.Format,
+			"valid":        result.Valid,
+			"diagnostics":  diagnostics,
+		}, "", "  ")
+	case "sarif":
+		report, err = validation.FormatSARIF(sarifURI(params.ConfigPath), diagnostics)
+	}
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to encode %s report: %v", outputFormat, err), err)
+	}
+	if report != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(report)}},
+			IsError: !result.Valid,
+		}, data, nil
 	}
 
 	if !result.Valid {
@@ -147,12 +293,40 @@ func ValidateConfig(ctx context.Context, req *mcp.CallToolRequest, params Valida
 	return responses.FormatSuccessWithData(message.String(), data)
 }
 
+// formatPosition renders " (line L, column C)" for located findings
+func formatPosition(line, column int) string {
+	if line == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (line %d, column %d)", line, column)
+}
+
+// sarifURI makes config paths relative to the working directory, which is
+// the repository root when run in CI, so code scanning can map results
+func sarifURI(path string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return "file://" + filepath.ToSlash(path)
+}
+
 // GenerateConfigTemplate generates a configuration file template
 func GenerateConfigTemplate(ctx context.Context, req *mcp.CallToolRequest, params GenerateConfigParams) (*mcp.CallToolResult, any, error) {
 	if params.ServiceName == "" {
 		return responses.FormatValidationError("service_name", "", "service_name is required", "Provide service name")
 	}
 
+	if params.Environment == "" {
+		params.Environment = "development"
+	}
+
+	scope := pathScope(ctx, req)
+	if params.FromStruct {
+		return generateConfigFromStruct(scope, params)
+	}
+
 	if params.ServiceType == "" {
 		return responses.FormatValidationError("service_type", "", "service_type is required", "Use 'api' or 'rpc'")
 	}
@@ -161,17 +335,13 @@ func GenerateConfigTemplate(ctx context.Context, req *mcp.CallToolRequest, param
 		return responses.FormatValidationError("service_type", params.ServiceType, "invalid service type", "Use 'api' or 'rpc'")
 	}
 
-	if params.Environment == "" {
-		params.Environment = "development"
-	}
-
 	// Set default port if not provided
+	ports := ServerConfig().PortRange(params.ServiceType)
 	if params.Port == 0 {
-		if params.ServiceType == "api" {
-			params.Port = 8888
-		} else {
-			params.Port = 9090
-		}
+		params.Port = ports.Default
+	}
+	if !ports.Contains(params.Port) {
+		return formatPortRangeError(params.ServiceType, params.Port, ports)
 	}
 
 	// Get template
@@ -183,7 +353,7 @@ func GenerateConfigTemplate(ctx context.Context, req *mcp.CallToolRequest, param
 	// Parse and execute template
 	tmpl, err := template.New("config").Parse(templateStr)
 	if err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to parse template: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse template: %v", err), err)
 	}
 
 	metricsPort := params.Port + 1000
@@ -195,35 +365,27 @@ func GenerateConfigTemplate(ctx context.Context, req *mcp.CallToolRequest, param
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to execute template: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to execute template: %v", err), err)
 	}
 
 	configContent := buf.String()
 
 	// Determine output path
-	outputPath := params.OutputPath
-	if outputPath == "" {
-		envSuffix := ""
-		if params.Environment != "development" {
-			envSuffix = "-" + params.Environment
-		}
-		outputPath = fmt.Sprintf("etc/%s%s.yaml", params.ServiceName, envSuffix)
-	}
-
-	if !filepath.IsAbs(outputPath) {
-		cwd, _ := os.Getwd()
-		outputPath = filepath.Join(cwd, outputPath)
+	cwd, _ := os.Getwd()
+	outputPath, err := scope.Resolve(resolveConfigOutputPath(cwd, params), sandbox.Write)
+	if err != nil {
+		return formatPathError("output_path", params.OutputPath, err)
 	}
 
 	// Create directory if needed
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to create directory: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to create directory: %v", err), err)
 	}
 
 	// Write config file
 	if err := os.WriteFile(outputPath, []byte(configContent), 0644); err != nil {
-		return responses.FormatError(fmt.Sprintf("failed to write config file: %v", err))
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to write config file: %v", err), err)
 	}
 
 	message := fmt.Sprintf("Successfully generated %s configuration for %s environment\n\n", params.ServiceType, params.Environment)
@@ -252,13 +414,160 @@ func GenerateConfigTemplate(ctx context.Context, req *mcp.CallToolRequest, param
 	message += "  2. Use validate_config to verify your changes\n"
 	message += fmt.Sprintf("  3. Start your service with: ./%s -f %s\n", params.ServiceName, outputPath)
 
-	resultData := map[string]any{
-		"service_name": params.ServiceName,
-		"service_type": params.ServiceType,
-		"environment":  params.Environment,
-		"output_path":  outputPath,
-		"port":         params.Port,
+	resultData := &GenerateConfigTemplateResult{
+		ServiceName: params.ServiceName,
+		ServiceType: params.ServiceType,
+		Environment: params.Environment,
+		OutputPath:  outputPath,
+		Port:        params.Port,
+	}
+
+	return responses.FormatSuccessWithData(message, resultData)
+}
+
+// resolveConfigOutputPath returns the output path, defaulting to
+// <layout.config_dir>/<service>[-<env>].yaml under baseDir
+func resolveConfigOutputPath(baseDir string, params GenerateConfigParams) string {
+	outputPath := params.OutputPath
+	if outputPath == "" {
+		envSuffix := ""
+		if params.Environment != "development" {
+			envSuffix = "-" + params.Environment
+		}
+		outputPath = filepath.Join(ServerConfig().Layout.ConfigDir, params.ServiceName+envSuffix+".yaml")
+	}
+
+	if !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(baseDir, outputPath)
+	}
+	return outputPath
+}
+
+// generateConfigFromStruct renders a config skeleton from the service's Config struct
+func generateConfigFromStruct(scope *sandbox.Scope, params GenerateConfigParams) (*mcp.CallToolResult, any, error) {
+	projectPath, err := scope.Resolve(params.ProjectPath, sandbox.Read)
+	if err != nil {
+		return formatPathError("project_path", params.ProjectPath, err)
+	}
+
+	structFile := params.StructFile
+	if structFile == "" {
+		structFile = filepath.Join(projectPath, "internal", "config", "config.go")
+	} else if !filepath.IsAbs(structFile) {
+		structFile = filepath.Join(projectPath, structFile)
+	}
+	if structFile, err = scope.Resolve(structFile, sandbox.Read); err != nil {
+		return formatPathError("struct_file", params.StructFile, err)
+	}
+
+	configStruct, err := analyzer.ParseConfigStruct(structFile, params.StructName)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse config struct: %v", err), err)
+	}
+
+	serviceType := params.ServiceType
+	if serviceType == "" {
+		serviceType = "api"
+		if configStruct.HasField("ListenOn") {
+			serviceType = "rpc"
+		}
+	}
+	if serviceType != "api" && serviceType != "rpc" {
+		return responses.FormatValidationError("service_type", serviceType, "invalid service type", "Use 'api' or 'rpc'")
+	}
+
+	ports := ServerConfig().PortRange(serviceType)
+	if params.Port == 0 {
+		params.Port = ports.Default
+	}
+	if !ports.Contains(params.Port) {
+		return formatPortRangeError(serviceType, params.Port, ports)
+	}
+
+	// Layer values: struct defaults < environment overrides < service identity < user overrides
+	values := templates.ConfigSkeletonOverrides(serviceType, params.Environment)
+	identity := map[string]interface{}{"Name": params.ServiceName}
+	if serviceType == "rpc" {
+		host := "0.0.0.0"
+		if params.Environment == "test" {
+			host = "127.0.0.1"
+		}
+		identity["ListenOn"] = fmt.Sprintf("%s:%d", host, params.Port)
+	} else {
+		identity["Port"] = params.Port
+	}
+	values = templates.MergeConfigValues(values, identity)
+
+	if params.Overrides != "" {
+		var overrides map[string]interface{}
+		if err := json.Unmarshal([]byte(params.Overrides), &overrides); err != nil {
+			return responses.FormatErrorWithCause(fmt.Sprintf("failed to parse overrides JSON: %v", err), err)
+		}
+		values = templates.MergeConfigValues(values, overrides)
+	}
+
+	content, unknownKeys := templates.RenderConfigSkeleton(configStruct.Fields, values)
+	header := fmt.Sprintf("# Generated from %s (%s) for %s environment\n", configStruct.Name, filepath.Base(structFile), params.Environment)
+	if len(configStruct.Fallbacks) > 0 {
+		header += "# go-zero sources not found; built-in definitions used for: " + strings.Join(configStruct.Fallbacks, ", ") + "\n"
+		header += "# Run go mod download and regenerate to include every field of your go-zero version\n"
+	}
+	content = header + content
+
+	outputPath, err := scope.Resolve(resolveConfigOutputPath(projectPath, params), sandbox.Write)
+	if err != nil {
+		return formatPathError("output_path", params.OutputPath, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to create directory: %v", err), err)
+	}
+	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to write config file: %v", err), err)
+	}
+
+	message := fmt.Sprintf("Successfully generated %s configuration from %s for %s environment\n\n", serviceType, configStruct.Name, params.Environment)
+	message += fmt.Sprintf("Struct file: %s\n", structFile)
+	message += fmt.Sprintf("Output file: %s\n", outputPath)
+	message += fmt.Sprintf("Top-level keys: %d\n", len(configStruct.Fields))
+
+	if len(unknownKeys) > 0 {
+		message += "\n⚠️  Overrides that match no Config field (ignored):\n"
+		for _, key := range unknownKeys {
+			message += fmt.Sprintf("  - %s\n", key)
+		}
+	}
+
+	if len(configStruct.Fallbacks) > 0 {
+		message += "\n⚠️  go-zero sources were not found in vendor or the module cache; built-in definitions were used for:\n"
+		for _, pkg := range configStruct.Fallbacks {
+			message += fmt.Sprintf("  - %s\n", pkg)
+		}
+		message += "  Fields added in your go-zero version may be missing. Run go mod download and regenerate.\n"
+	}
+
+	message += "\nNext steps:\n"
+	message += "  1. Fill in the empty values and remove optional sections you don't need\n"
+	message += "  2. Use validate_config to verify your changes\n"
+
+	resultData := &GenerateConfigTemplateResult{
+		ServiceName: params.ServiceName,
+		ServiceType: serviceType,
+		Environment: params.Environment,
+		OutputPath:  outputPath,
+		Port:        params.Port,
+		StructFile:  structFile,
+		StructName:  configStruct.Name,
+		FieldCount:  len(configStruct.Fields),
+		UnknownKeys: unknownKeys,
+		Fallbacks:   configStruct.Fallbacks,
 	}
 
 	return responses.FormatSuccessWithData(message, resultData)
 }
+
+// formatPortRangeError reports a port outside the configured range of the service type
+func formatPortRangeError(serviceType string, port int, ports serverconfig.PortRange) (*mcp.CallToolResult, any, error) {
+	return responses.FormatValidationError("port", fmt.Sprintf("%d", port),
+		fmt.Sprintf("port is outside the configured %s port range %d-%d", serviceType, ports.Min, ports.Max),
+		fmt.Sprintf("Use a port number between %d and %d or change ports.%s in %s", ports.Min, ports.Max, serviceType, serverconfig.FileName))
+}
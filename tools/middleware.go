@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/audit"
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/goctl"
+	"github.com/jinguoxing/mcp-gozero/internal/history"
+	"github.com/jinguoxing/mcp-gozero/internal/logging"
+	"github.com/jinguoxing/mcp-gozero/internal/metrics"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// Metrics records duration and outcome of every tool registered with AddTool
+var Metrics = metrics.NewMetrics(true)
+
+// Logger writes one structured entry per tool call to stderr
+var Logger = logging.NewLogger(true)
+
+// Audit records the files and subprocesses of every tool call; nil disables it
+var Audit *audit.Log
+
+// History keeps the file changes of recent tool calls for undo_operation
+var History = history.NewStore(history.DefaultMaxOperations)
+
+// NewExecutor creates the goctl executor of a tool call; tests replace it
+// with a goctl.FakeExecutor or a goctl.Replay
+var NewExecutor = goctl.NewExecutor
+
+// AddTool registers a tool whose calls are recorded in Metrics and Logger.
+// The output schema is derived from Out, and the handler must return *Out
+// as its structured result, which the SDK validates against the schema.
+// Tools disabled in the server config are skipped
+func AddTool[Out, In any](server *mcp.Server, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, any]) {
+	if !toolEnabled(tool.Name) {
+		return
+	}
+	if tool.OutputSchema == nil {
+		schema, err := jsonschema.For[Out](nil)
+		if err != nil {
+			panic(fmt.Sprintf("AddTool %q: output schema: %v", tool.Name, err))
+		}
+		tool.OutputSchema = schema
+	}
+	mcp.AddTool(server, tool, structuredErrors(Instrument(tool.Name, typedOutput[Out](tool.Name, handler))))
+}
+
+// structuredErrors returns failures that carry a responses.ErrorContent in
+// _meta, such as diagnostics, as error results; the SDK would replace a result
+// returned with an error by the error's text
+func structuredErrors[In, Out any](handler mcp.ToolHandlerFor[In, Out]) mcp.ToolHandlerFor[In, Out] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error) {
+		result, output, err := handler(ctx, req, input)
+		if err != nil && result != nil && result.IsError && result.Meta[responses.ErrorMetaKey] != nil {
+			return result, output, nil
+		}
+		return result, output, err
+	}
+}
+
+// typedOutput rejects structured results that are not *Out, so a tool can't
+// silently drift from its declared output schema
+func typedOutput[Out, In any](name string, handler mcp.ToolHandlerFor[In, any]) mcp.ToolHandlerFor[In, any] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, any, error) {
+		result, output, err := handler(ctx, req, input)
+		if err != nil || output == nil {
+			return result, output, err
+		}
+		if _, ok := output.(*Out); !ok {
+			var want *Out
+			return nil, nil, fmt.Errorf("tool %s returned %T, want %T", name, output, want)
+		}
+		return result, output, nil
+	}
+}
+
+// Instrument wraps a tool handler with metrics, logging, the audit log and
+// the operation history. Paths the tool resolves for writing are tracked so
+// the files it changes can be audited and undone
+func Instrument[In, Out any](name string, handler mcp.ToolHandlerFor[In, Out]) mcp.ToolHandlerFor[In, Out] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error) {
+		sessionID := callSession(req)
+		recorder := Audit.Begin(name, sessionID, input)
+		tracker := snapshot.NewTracker(ServerConfig().History.MaxSnapshotBytes)
+		ctx = snapshot.WithTracker(audit.WithRecorder(ctx, recorder), tracker)
+
+		start := time.Now()
+		result, output, err := handler(ctx, req, input)
+		duration := time.Since(start)
+
+		changes := tracker.Changes()
+		recorder.Finish(changes, tracker.Truncated())
+		History.Record(name, sessionID, audit.RedactArguments(input), changes)
+
+		category := errorCategory(ctx, result, err)
+		Metrics.RecordToolResult(name, duration, category)
+
+		fields := logging.Fields{
+			"duration_ms": duration.Milliseconds(),
+		}
+		if sessionID != "" {
+			fields["session"] = sessionID
+		}
+		if category == "" {
+			fields["status"] = "ok"
+			Logger.InfoFields(name, "tool call completed", fields)
+		} else {
+			fields["status"] = "error"
+			fields["category"] = category
+			if err != nil {
+				fields["error"] = firstLine(err.Error())
+			}
+			Logger.ErrorFields(name, "tool call failed", fields)
+		}
+
+		return result, output, err
+	}
+}
+
+// errorCategory classifies a tool outcome; an empty category means success
+func errorCategory(ctx context.Context, result *mcp.CallToolResult, err error) string {
+	if err == nil && (result == nil || !result.IsError) {
+		return ""
+	}
+	if ctx.Err() != nil {
+		return mcperrors.Category(ctx.Err())
+	}
+	if err == nil {
+		// The tool reported a failure in its result, e.g. an invalid config
+		return mcperrors.CategoryValidation
+	}
+	return mcperrors.Category(err)
+}
+
+func firstLine(s string) string {
+	for i, r := range s {
+		if r == '\n' {
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/fixer"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+)
+
+type MigrateStyleParams struct {
+	ProjectPath string `json:"project_path"`
+	FromStyle   string `json:"from_style,omitempty"` // defaults to the style detected in the project
+	ToStyle     string `json:"to_style"`
+}
+
+// MigrateStyleResult is the structured result of migrate_style
+type MigrateStyleResult struct {
+	ProjectPath string             `json:"project_path"`
+	FromStyle   string             `json:"from_style"`
+	ToStyle     string             `json:"to_style"`
+	Renamed     []fixer.FileRename `json:"renamed"`
+	Settings    []fixer.FileChange `json:"settings"`
+}
+
+// MigrateStyle renames a project's generated files from one goctl naming
+// style to another and updates the style settings
+func MigrateStyle(ctx context.Context, req *mcp.CallToolRequest, params MigrateStyleParams) (*mcp.CallToolResult, any, error) {
+	styles := strings.Join(fixer.Styles, ", ")
+	if !fixer.ValidStyle(params.ToStyle) {
+		return responses.FormatValidationError("to_style", params.ToStyle, "invalid style", "Use one of: "+styles)
+	}
+	if params.FromStyle != "" && !fixer.ValidStyle(params.FromStyle) {
+		return responses.FormatValidationError("from_style", params.FromStyle, "invalid style", "Use one of: "+styles)
+	}
+	if params.ProjectPath == "" {
+		return responses.FormatValidationError("project_path", params.ProjectPath, "project path is required", "Provide the service or project directory")
+	}
+
+	projectPath, err := pathScope(ctx, req).Resolve(params.ProjectPath, sandbox.Write)
+	if err != nil {
+		return formatPathError("project_path", params.ProjectPath, err)
+	}
+
+	fromStyle := params.FromStyle
+	if fromStyle == "" {
+		fromStyle = fixer.DetectExistingStyle(projectPath)
+	}
+
+	migration, err := fixer.MigrateStyle(projectPath, fromStyle, params.ToStyle)
+	if err != nil {
+		message := fmt.Sprintf("failed to migrate style: %v", err)
+		if migration != nil {
+			message += fmt.Sprintf("\n\n%d files were renamed; revert them with undo_operation", len(migration.Renamed))
+		}
+		return responses.FormatErrorWithCause(message, err)
+	}
+
+	var message strings.Builder
+	from := fromStyle
+	if from == "" {
+		from = "any style"
+	}
+	fmt.Fprintf(&message, "Migrated %s from %s to %s\n", projectPath, from, params.ToStyle)
+	fmt.Fprintf(&message, "\nRenamed files (%d):\n", len(migration.Renamed))
+	for _, rename := range migration.Renamed {
+		fmt.Fprintf(&message, "  - %s -> %s (%s)\n", rename.Old, rename.New, rename.Kind)
+	}
+	if len(migration.Settings) > 0 {
+		message.WriteString("\nUpdated style settings:\n")
+		for _, file := range migration.Settings {
+			fmt.Fprintf(&message, "  - %s (%d)\n", file.Path, len(file.Imports))
+		}
+	}
+	message.WriteString("\n✅ No mixed-style duplicates remain\n")
+
+	data := &MigrateStyleResult{
+		ProjectPath: projectPath,
+		FromStyle:   fromStyle,
+		ToStyle:     params.ToStyle,
+		Renamed:     migration.Renamed,
+		Settings:    migration.Settings,
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/history"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+type ListOperationsParams struct {
+	Tool        string `json:"tool,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
+	AllSessions bool   `json:"all_sessions,omitempty"` // include operations of other sessions
+}
+
+// ListOperationsResult is the structured result of list_operations
+type ListOperationsResult struct {
+	Operations []OperationSummary `json:"operations"`
+}
+
+// OperationSummary describes a recorded operation
+type OperationSummary struct {
+	ID         string          `json:"id"`
+	Tool       string          `json:"tool"`
+	Session    string          `json:"session,omitempty"`
+	Time       time.Time       `json:"time"`
+	Created    int             `json:"created"`
+	Modified   int             `json:"modified"`
+	Deleted    int             `json:"deleted"`
+	Restorable bool            `json:"restorable"`
+	Undone     bool            `json:"undone"`
+	UndoneAt   string          `json:"undone_at,omitempty"`
+	Files      []OperationFile `json:"files"`
+}
+
+// OperationFile is a file changed by an operation
+type OperationFile struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+}
+
+type UndoOperationParams struct {
+	OperationID string `json:"operation_id,omitempty"` // defaults to the session's most recent operation not yet undone
+	Force       bool   `json:"force,omitempty"`        // undo even if files changed since the operation
+	AllSessions bool   `json:"all_sessions,omitempty"` // allow undoing an operation of another session
+}
+
+// UndoOperationResult is the structured result of undo_operation
+type UndoOperationResult struct {
+	OperationID string         `json:"operation_id"`
+	Tool        string         `json:"tool"`
+	Forced      bool           `json:"forced"`
+	Restored    []string       `json:"restored"`
+	Removed     []string       `json:"removed"`
+	Skipped     []UndoConflict `json:"skipped"`
+}
+
+// UndoConflict is a file undo couldn't restore safely
+type UndoConflict struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// undoToolName is the tool whose operations revert other operations
+const undoToolName = "undo_operation"
+
+// ListOperations lists the recorded operations of the calling session, most recent first
+func ListOperations(ctx context.Context, req *mcp.CallToolRequest, params ListOperationsParams) (*mcp.CallToolResult, any, error) {
+	session := callSession(req)
+	operations := []OperationSummary{}
+	for _, op := range History.List() {
+		if !params.AllSessions && op.Session != session {
+			continue
+		}
+		if params.Tool != "" && op.Tool != params.Tool {
+			continue
+		}
+		if params.Limit > 0 && len(operations) >= params.Limit {
+			break
+		}
+		operations = append(operations, summarizeOperation(op))
+	}
+
+	var message strings.Builder
+	message.WriteString("=== Operations ===\n")
+	if len(operations) == 0 {
+		message.WriteString("No operations recorded\n")
+	}
+	for _, op := range operations {
+		status := ""
+		switch {
+		case op.Undone:
+			status = " (undone)"
+		case !op.Restorable:
+			status = " ⚠️ not fully restorable"
+		}
+		fmt.Fprintf(&message, "%s %s [%s]%s: %d created, %d modified, %d deleted\n",
+			op.ID, op.Time.Format(time.RFC3339), op.Tool, status, op.Created, op.Modified, op.Deleted)
+		for _, file := range op.Files {
+			fmt.Fprintf(&message, "  %s %s\n", file.Action, file.Path)
+		}
+	}
+
+	return responses.FormatSuccessWithData(message.String(), &ListOperationsResult{Operations: operations})
+}
+
+func summarizeOperation(op *history.Operation) OperationSummary {
+	summary := OperationSummary{
+		ID:         op.ID,
+		Tool:       op.Tool,
+		Session:    op.Session,
+		Time:       op.Time,
+		Created:    op.Count(snapshot.ActionCreated),
+		Modified:   op.Count(snapshot.ActionModified),
+		Deleted:    op.Count(snapshot.ActionDeleted),
+		Restorable: op.Restorable(),
+		Undone:     op.Undone(),
+		Files:      []OperationFile{},
+	}
+	if op.Undone() {
+		summary.UndoneAt = op.UndoneAt.Format(time.RFC3339)
+	}
+	for _, change := range op.Changes {
+		if !change.Dir {
+			summary.Files = append(summary.Files, OperationFile{Path: change.Path, Action: change.Action})
+		}
+	}
+	return summary
+}
+
+// UndoOperation restores the files of an operation to their prior state.
+// Without an ID it picks the session's most recent operation, skipping
+// earlier undos so a repeated call doesn't revert the previous undo
+func UndoOperation(ctx context.Context, req *mcp.CallToolRequest, params UndoOperationParams) (*mcp.CallToolResult, any, error) {
+	session := callSession(req)
+	var op *history.Operation
+	var err error
+	if params.OperationID == "" {
+		op, err = History.Latest(func(op *history.Operation) bool {
+			return op.Tool != undoToolName && (params.AllSessions || op.Session == session)
+		})
+	} else {
+		op, err = History.Get(params.OperationID)
+	}
+	if err != nil {
+		if errors.Is(err, history.ErrNotFound) {
+			return responses.FormatValidationErrorWithCause("operation_id", params.OperationID, err, "Use list_operations to find a recorded operation")
+		}
+		return responses.FormatErrorWithCause(err.Error(), err)
+	}
+	if op.Session != session && !params.AllSessions {
+		return responses.FormatValidationError("operation_id", op.ID, "operation was recorded by another session",
+			"Set all_sessions=true to undo another session's operation")
+	}
+
+	// Undo writes through the same sandbox as the operation did, which also
+	// records the restored files in the audit log and history
+	scope := pathScope(ctx, req)
+	for _, change := range op.Changes {
+		if _, err := scope.Resolve(change.Path, sandbox.Write); err != nil {
+			return formatPathError("operation_id", op.ID, err)
+		}
+	}
+
+	result, err := History.Undo(op, params.Force)
+	if err != nil {
+		var message strings.Builder
+		fmt.Fprintf(&message, "❌ Cannot undo %s: %v\n", op.ID, err)
+		if result != nil && len(result.Skipped) > 0 {
+			for _, conflict := range result.Skipped {
+				fmt.Fprintf(&message, "  %s: %s\n", conflict.Path, conflict.Reason)
+			}
+			message.WriteString("\nReview these files, then call undo_operation with force=true to overwrite them with their prior state")
+		}
+		return responses.FormatErrorWithCause(message.String(), err)
+	}
+
+	data := &UndoOperationResult{
+		OperationID: op.ID,
+		Tool:        op.Tool,
+		Forced:      params.Force,
+		Restored:    append([]string{}, result.Restored...),
+		Removed:     append([]string{}, result.Removed...),
+		Skipped:     []UndoConflict{},
+	}
+	for _, conflict := range result.Skipped {
+		data.Skipped = append(data.Skipped, UndoConflict{Path: conflict.Path, Reason: conflict.Reason})
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "✅ Undid %s (%s)\n", op.ID, op.Tool)
+	if len(data.Restored) > 0 {
+		message.WriteString("\n=== Restored ===\n")
+		for _, path := range data.Restored {
+			fmt.Fprintf(&message, "- %s\n", path)
+		}
+	}
+	if len(data.Removed) > 0 {
+		message.WriteString("\n=== Removed ===\n")
+		for _, path := range data.Removed {
+			fmt.Fprintf(&message, "- %s\n", path)
+		}
+	}
+	if len(data.Skipped) > 0 {
+		message.WriteString("\n=== ⚠️ Skipped ===\n")
+		for _, conflict := range data.Skipped {
+			fmt.Fprintf(&message, "- %s: %s\n", conflict.Path, conflict.Reason)
+		}
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
+
+// callSession returns the MCP session of a tool call, empty outside a session
+func callSession(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	return req.Session.ID()
+}
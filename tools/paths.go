@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	mcperrors "github.com/jinguoxing/mcp-gozero/internal/errors"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/snapshot"
+)
+
+// Sandbox decides which paths tools may read and write
+var Sandbox = sandbox.NewPolicy()
+
+// pathScope returns the roots the current tool call may access; paths
+// resolved for writing are tracked for the audit log and operation history
+func pathScope(ctx context.Context, req *mcp.CallToolRequest) *sandbox.Scope {
+	var session *mcp.ServerSession
+	if req != nil {
+		session = req.Session
+	}
+	return Sandbox.Scope(ctx, session).OnWrite(snapshot.FromContext(ctx).Track)
+}
+
+// formatPathError reports a path rejected by the sandbox
+func formatPathError(field, value string, err error) (*mcp.CallToolResult, any, error) {
+	return responses.FormatValidationErrorWithCause(field, value, mcperrors.Wrap(mcperrors.ErrInvalidPath, err),
+		"Use a path inside the client's roots or add its directory with --allowed-roots")
+}
@@ -15,6 +15,15 @@ type QueryDocsParams struct {
 	Query string `json:"query"` // The documentation query
 }
 
+// QueryDocsResult is the structured result of query_docs
+type QueryDocsResult struct {
+	Query           string   `json:"query"`
+	Found           bool     `json:"found"`
+	ConceptsFound   int      `json:"concepts_found"`
+	MigrationsFound int      `json:"migrations_found"`
+	Keywords        []string `json:"keywords,omitempty"`
+}
+
 // QueryDocs queries go-zero framework documentation and migration guides
 func QueryDocs(ctx context.Context, req *mcp.CallToolRequest, params QueryDocsParams) (*mcp.CallToolResult, any, error) {
 	if params.Query == "" {
@@ -38,11 +47,12 @@ func QueryDocs(ctx context.Context, req *mcp.CallToolRequest, params QueryDocsPa
 
 	message := formatDocsResponse(query, concepts, migrations)
 
-	data := map[string]any{
-		"query":            query,
-		"concepts_found":   len(concepts),
-		"migrations_found": len(migrations),
-		"keywords":         keywords,
+	data := &QueryDocsResult{
+		Query:           query,
+		Found:           true,
+		ConceptsFound:   len(concepts),
+		MigrationsFound: len(migrations),
+		Keywords:        keywords,
 	}
 
 	return responses.FormatSuccessWithData(message, data)
@@ -174,10 +184,9 @@ func formatNoResultsResponse(query string, keywords []string) (*mcp.CallToolResu
 	message += "- \"Explain service context\"\n"
 	message += "- \"How to use JWT authentication?\"\n"
 
-	data := map[string]any{
-		"query":    query,
-		"keywords": keywords,
-		"found":    false,
+	data := &QueryDocsResult{
+		Query:    query,
+		Keywords: keywords,
 	}
 
 	return responses.FormatSuccessWithData(message, data)
@@ -0,0 +1,140 @@
+package tools
+
+import "github.com/modelcontextprotocol/go-sdk/mcp"
+
+// Register adds the tools enabled in the server config to the server
+func Register(server *mcp.Server) error {
+	// Register create_api_service tool (T034 - User Story 1)
+	AddTool[CreateAPIServiceResult](server, &mcp.Tool{
+		Name:        "create_api_service",
+		Description: "Create a new go-zero API service with proper structure and configuration",
+	}, CreateAPIService)
+
+	// Register generate_api_from_spec tool (T047 - User Story 2)
+	AddTool[GenerateAPIFromSpecResult](server, &mcp.Tool{
+		Name:        "generate_api_from_spec",
+		Description: "Generate go-zero API code from API specification file",
+	}, GenerateAPIFromSpec)
+
+	// Register create_rpc_service tool (T058 - User Story 3)
+	AddTool[CreateRPCServiceResult](server, &mcp.Tool{
+		Name:        "create_rpc_service",
+		Description: "Create a new go-zero RPC service with protobuf definition",
+	}, CreateRPCService)
+
+	// Register generate_model tool (T071 - User Story 4)
+	AddTool[GenerateModelResult](server, &mcp.Tool{
+		Name:        "generate_model",
+		Description: "Generate go-zero database model from table schema",
+	}, GenerateModel)
+
+	// Register create_api_spec tool (T081 - User Story 5)
+	AddTool[CreateAPISpecResult](server, &mcp.Tool{
+		Name:        "create_api_spec",
+		Description: "Create a sample API specification file for go-zero. IMPORTANT: Always define concrete types for request and response - do NOT use 'any' type in .api files as it's not supported by go-zero",
+	}, CreateAPISpec)
+
+	// Register analyze_project tool (T097 - User Story 6)
+	AddTool[AnalyzeProjectResult](server, &mcp.Tool{
+		Name:        "analyze_project",
+		Description: "Analyze existing go-zero project structure and dependencies",
+	}, AnalyzeProject)
+
+	// Register validate_config tool (T109 - User Story 7)
+	AddTool[ValidateConfigResult](server, &mcp.Tool{
+		Name:        "validate_config",
+		Description: "Validate go-zero service configuration file",
+	}, ValidateConfig)
+
+	// Register generate_config_template tool (T109 - User Story 7)
+	AddTool[GenerateConfigTemplateResult](server, &mcp.Tool{
+		Name:        "generate_config_template",
+		Description: "Generate configuration template for go-zero service",
+	}, GenerateConfigTemplate)
+
+	// Register diff_configs tool
+	AddTool[DiffConfigsResult](server, &mcp.Tool{
+		Name:        "diff_configs",
+		Description: "Compare environment configs of a go-zero service and highlight production promotion risks",
+	}, DiffConfigs)
+
+	// Register scan_config_secrets tool
+	AddTool[ScanConfigSecretsResult](server, &mcp.Tool{
+		Name:        "scan_config_secrets",
+		Description: "Scan all config files in a go-zero project for hard-coded passwords, JWT secrets and private keys",
+	}, ScanConfigSecrets)
+
+	// Register check_config_consistency tool
+	AddTool[CheckConfigConsistencyResult](server, &mcp.Tool{
+		Name:        "check_config_consistency",
+		Description: "Check configs across all services of a project for port conflicts, unmatched RPC Etcd keys and inconsistent etcd/redis clusters",
+	}, CheckConfigConsistency)
+
+	// Register generate_template tool (T123 - User Story 8)
+	AddTool[GenerateTemplateResult](server, &mcp.Tool{
+		Name:        "generate_template",
+		Description: "Generate common code templates (middleware, error handlers, deployment configs)",
+	}, GenerateTemplate)
+
+	// Register query_docs tool (T134 - User Story 9)
+	AddTool[QueryDocsResult](server, &mcp.Tool{
+		Name:        "query_docs",
+		Description: "Query go-zero framework documentation and migration guides",
+	}, QueryDocs)
+
+	// Register server_stats tool
+	AddTool[ServerStatsResult](server, &mcp.Tool{
+		Name:        "server_stats",
+		Description: "Show per-tool call counts, error categories, latency percentiles and analysis cache statistics of this server",
+	}, ServerStats)
+
+	// Register show_server_config tool
+	AddTool[ShowServerConfigResult](server, &mcp.Tool{
+		Name:        "show_server_config",
+		Description: "Show the effective mcp-gozero.yaml server config: module prefix, style, port ranges, layout, goctl/protoc paths, enabled tools, allowed roots and cache settings",
+	}, ShowServerConfig)
+
+	// Register diagnose_goctl tool
+	AddTool[DiagnoseGoctlResult](server, &mcp.Tool{
+		Name:        "diagnose_goctl",
+		Description: "Show the discovered goctl, its version and supported flags, and warn when its major/minor version differs from the project's go-zero version",
+	}, DiagnoseGoctl)
+
+	// Register doctor tool
+	AddTool[DoctorResult](server, &mcp.Tool{
+		Name:        "doctor",
+		Description: "Check go, goctl, protoc, protoc-gen-go and protoc-gen-go-grpc against minimum versions, GOPATH/bin on PATH, the module cache, GOFLAGS, GOPROXY reachability and write access; returns a pass/fail checklist with fix commands",
+	}, Doctor)
+
+	// Register list_audit_events tool
+	AddTool[ListAuditEventsResult](server, &mcp.Tool{
+		Name:        "list_audit_events",
+		Description: "List audit log entries of files created, modified or deleted and commands run by tools, filtered by time, tool and path",
+	}, ListAuditEvents)
+
+	// Register list_operations tool
+	AddTool[ListOperationsResult](server, &mcp.Tool{
+		Name:        "list_operations",
+		Description: "List recent tool operations that changed files, with the files each created, modified or deleted",
+	}, ListOperations)
+
+	// Register undo_operation tool
+	AddTool[UndoOperationResult](server, &mcp.Tool{
+		Name:        "undo_operation",
+		Description: "Undo an operation by restoring the files it touched to their prior state; refuses if the files changed since, unless forced",
+	}, UndoOperation)
+
+	// Register rename_module tool
+	AddTool[RenameModuleResult](server, &mcp.Tool{
+		Name:        "rename_module",
+		Description: "Rename a service's Go module (e.g. from the placeholder github.com/example/<name>): updates go.mod, Go imports in all packages, .api import paths, proto go_package options and go.work replaces, then confirms with go build ./...",
+	}, RenameModule)
+
+	// Register migrate_style tool
+	AddTool[MigrateStyleResult](server, &mcp.Tool{
+		Name:        "migrate_style",
+		Description: "Rename a project's generated files (handlers, routes, logic, types, svc, config, RPC server and client) from one goctl naming style (go_zero, gozero, goZero) to another, update mcp-gozero.yaml and goctl --style flags, and check no mixed-style duplicates remain",
+	}, MigrateStyle)
+
+	return checkToolNames(ServerConfig())
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/fixer"
+	"github.com/jinguoxing/mcp-gozero/internal/progress"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/validation"
+)
+
+// renameModuleSteps is the number of progress steps reported by RenameModule
+const renameModuleSteps = 2
+
+type RenameModuleParams struct {
+	ProjectPath string `json:"project_path"` // directory containing the go.mod to rename
+	NewModule   string `json:"new_module"`
+}
+
+// RenameModuleResult is the structured result of rename_module
+type RenameModuleResult struct {
+	ProjectPath string             `json:"project_path"`
+	OldModule   string             `json:"old_module"`
+	NewModule   string             `json:"new_module"`
+	GoWork      string             `json:"go_work,omitempty"`
+	Files       []fixer.FileChange `json:"files"`
+	Warnings    []string           `json:"warnings,omitempty"`
+}
+
+// RenameModule renames a service's Go module, e.g. from the placeholder
+// github.com/example/<name>, rewriting go.mod, imports, .api imports,
+// go_package options and go.work replaces, then builds every package
+func RenameModule(ctx context.Context, req *mcp.CallToolRequest, params RenameModuleParams) (*mcp.CallToolResult, any, error) {
+	if params.ProjectPath == "" {
+		return responses.FormatValidationError("project_path", params.ProjectPath, "project path is required", "Provide the directory containing go.mod")
+	}
+	if err := validation.ValidateModulePath("new_module", params.NewModule); err != nil {
+		return responses.FormatValidationErrorWithCause("new_module", params.NewModule, err, "Use a module path such as github.com/acme/user")
+	}
+
+	scope := pathScope(ctx, req)
+	projectPath, err := scope.Resolve(params.ProjectPath, sandbox.Write)
+	if err != nil {
+		return formatPathError("project_path", params.ProjectPath, err)
+	}
+	oldModule, err := fixer.GetGoModuleName(projectPath)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to read module name: %v", err), err)
+	}
+	if oldModule == params.NewModule {
+		return responses.FormatValidationError("new_module", params.NewModule, "the module already has this path", "Provide a different module path")
+	}
+
+	var warnings []string
+	goWork := fixer.FindGoWork(projectPath)
+	if goWork != "" {
+		if goWork, err = scope.Resolve(goWork, sandbox.Write); err != nil {
+			warnings = append(warnings, fmt.Sprintf("go.work not updated: %v", err))
+			goWork = ""
+		}
+	}
+
+	reporter := progress.NewReporter(ctx, req, renameModuleSteps)
+
+	reporter.Step("Rewriting module references")
+	rename, err := fixer.RenameModule(projectPath, params.NewModule, goWork)
+	if err != nil {
+		return failStep(reporter, fmt.Sprintf("failed to rename module: %v", err), err)
+	}
+
+	reporter.Step("Running go build")
+	if err := fixer.VerifyBuildAllWithOutput(ctx, projectPath, reporter.Output("go")); err != nil {
+		return failStep(reporter, commandErrorMessage("module renamed but the build failed (undo with undo_operation)", err), err)
+	}
+
+	reporter.Done("Module renamed")
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "Renamed module %s to %s\n\nProject: %s\n", rename.OldModule, rename.NewModule, projectPath)
+	var updated, skipped []fixer.FileChange
+	for _, file := range rename.Files {
+		if file.Skipped != "" {
+			skipped = append(skipped, file)
+		} else {
+			updated = append(updated, file)
+		}
+	}
+	fmt.Fprintf(&message, "\nUpdated files (%d):\n", len(updated))
+	for _, file := range updated {
+		fmt.Fprintf(&message, "  - %s (%d)\n", file.Path, len(file.Imports))
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(&message, "\nSkipped files (%d):\n", len(skipped))
+		for _, file := range skipped {
+			fmt.Fprintf(&message, "  - %s: %s\n", file.Path, file.Skipped)
+		}
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(&message, "\n⚠️ %s\n", warning)
+	}
+	message.WriteString("\n✅ go build ./... succeeded\n")
+
+	data := &RenameModuleResult{
+		ProjectPath: projectPath,
+		OldModule:   rename.OldModule,
+		NewModule:   rename.NewModule,
+		GoWork:      goWork,
+		Files:       rename.Files,
+		Warnings:    warnings,
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/sandbox"
+	"github.com/jinguoxing/mcp-gozero/internal/security"
+	"github.com/jinguoxing/mcp-gozero/internal/validation"
+)
+
+type ScanConfigSecretsParams struct {
+	ProjectPath string `json:"project_path"`
+}
+
+// ScanConfigSecretsResult is the structured result of scan_config_secrets
+type ScanConfigSecretsResult struct {
+	ProjectPath  string              `json:"project_path"`
+	ConfigCount  int                 `json:"config_count"`
+	SecretCount  int                 `json:"secret_count"`
+	HighSeverity int                 `json:"high_severity"`
+	Files        []ConfigSecretsFile `json:"files"`
+	Skipped      []string            `json:"skipped,omitempty"`
+}
+
+// ConfigSecretsFile lists the secrets found in one config file
+type ConfigSecretsFile struct {
+	ConfigPath string         `json:"config_path"`
+	Secrets    []SecretResult `json:"secrets"`
+}
+
+// SecretResult is a hard-coded secret finding; Value is always redacted
+type SecretResult struct {
+	Field      string `json:"field"`
+	Kind       string `json:"kind"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Value      string `json:"value"`
+	Suggestion string `json:"suggestion"`
+}
+
+// ScanConfigSecrets scans every config file discovered in a project for hard-coded credentials
+func ScanConfigSecrets(ctx context.Context, req *mcp.CallToolRequest, params ScanConfigSecretsParams) (*mcp.CallToolResult, any, error) {
+	// An empty project path resolves to the working directory
+	projectPath, err := pathScope(ctx, req).Resolve(params.ProjectPath, sandbox.Read)
+	if err != nil {
+		return formatPathError("project_path", params.ProjectPath, err)
+	}
+
+	analysis, err := analyzeProjectCached(projectPath)
+	if err != nil {
+		return responses.FormatErrorWithCause(fmt.Sprintf("failed to analyze project: %v", err), err)
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("Config Secret Scan: %s\n\n", projectPath))
+	message.WriteString(fmt.Sprintf("Config files scanned: %d\n\n", len(analysis.Configs)))
+
+	files := make([]ConfigSecretsFile, 0)
+	var skipped []string
+	total, high := 0, 0
+
+	for _, cfg := range analysis.Configs {
+		loaded, err := validation.LoadConfigFile(cfg.Path, validation.ConfigLoadOptions{})
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", relativeToProject(projectPath, cfg.Path), err))
+			continue
+		}
+
+		findings := security.ScanConfigSecrets(loaded.Raw)
+		if len(findings) == 0 {
+			continue
+		}
+
+		relPath := relativeToProject(projectPath, cfg.Path)
+		message.WriteString(fmt.Sprintf("=== %s ===\n", relPath))
+		for _, finding := range findings {
+			message.WriteString(fmt.Sprintf("  🔑 %s: %s (%s)\n", finding.Field, finding.Message, finding.Severity))
+			message.WriteString(fmt.Sprintf("     Current value: %s\n", finding.Redacted))
+			message.WriteString(fmt.Sprintf("     Suggestion: %s\n", finding.Suggestion))
+			if finding.Severity == "high" {
+				high++
+			}
+		}
+		message.WriteString("\n")
+
+		total += len(findings)
+		files = append(files, ConfigSecretsFile{
+			ConfigPath: cfg.Path,
+			Secrets:    secretFindingsData(findings),
+		})
+	}
+
+	if len(skipped) > 0 {
+		message.WriteString("=== Skipped ===\n")
+		for _, s := range skipped {
+			message.WriteString(fmt.Sprintf("  ⚠️  %s\n", s))
+		}
+		message.WriteString("\n")
+	}
+
+	if total == 0 {
+		message.WriteString("✅ No hard-coded secrets found.\n")
+	} else {
+		message.WriteString(fmt.Sprintf("❌ Found %d secret(s) (%d high severity) in %d file(s).\n", total, high, len(files)))
+		message.WriteString("Move them to environment variables and load configs with conf.UseEnv().\n")
+	}
+
+	data := &ScanConfigSecretsResult{
+		ProjectPath:  projectPath,
+		ConfigCount:  len(analysis.Configs),
+		SecretCount:  total,
+		HighSeverity: high,
+		Files:        files,
+		Skipped:      skipped,
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
+
+// secretFindingsData converts findings to response data; values are always redacted
+func secretFindingsData(findings []security.SecretFinding) []SecretResult {
+	result := make([]SecretResult, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, SecretResult{
+			Field:      finding.Field,
+			Kind:       finding.Kind,
+			Severity:   finding.Severity,
+			Message:    finding.Message,
+			Value:      finding.Redacted,
+			Suggestion: finding.Suggestion,
+		})
+	}
+	return result
+}
+
+func relativeToProject(projectPath, path string) string {
+	if rel, err := filepath.Rel(projectPath, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/goctl"
+	"github.com/jinguoxing/mcp-gozero/internal/process"
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+	"github.com/jinguoxing/mcp-gozero/internal/serverconfig"
+)
+
+var serverConfig atomic.Pointer[serverconfig.Config]
+
+func init() {
+	serverConfig.Store(serverconfig.Default())
+}
+
+// ServerConfig returns the active server config
+func ServerConfig() *serverconfig.Config {
+	return serverConfig.Load()
+}
+
+// ApplyConfig makes cfg the active server config: it sets the sandbox
+// allow-list, goctl and protoc paths and subprocess timeouts. Timeouts set
+// through MCP_GOZERO_TIMEOUT_<STEP> keep precedence over the file.
+// Call it before Register, which uses the tools selection.
+func ApplyConfig(cfg *serverconfig.Config) error {
+	if err := Sandbox.SetAllowList(cfg.AllowedRoots); err != nil {
+		return err
+	}
+	goctl.Configure(goctl.Settings{
+		Path:        cfg.Goctl.Path,
+		SearchPaths: cfg.Goctl.SearchPaths,
+		ProtocPath:  cfg.Protoc.Path,
+	})
+	for step := range process.DefaultTimeouts {
+		if os.Getenv(process.EnvVar(step)) == "" {
+			process.SetTimeout(step, cfg.Timeouts[string(step)])
+		}
+	}
+	History.SetMax(cfg.History.MaxOperations)
+	serverConfig.Store(cfg)
+	return nil
+}
+
+var (
+	knownToolsMu sync.Mutex
+	knownTools   = map[string]bool{}
+)
+
+// toolEnabled records the tool name and reports whether the config enables it
+func toolEnabled(name string) bool {
+	knownToolsMu.Lock()
+	knownTools[name] = true
+	knownToolsMu.Unlock()
+	return ServerConfig().ToolEnabled(name)
+}
+
+// checkToolNames rejects tools in the config that were never registered,
+// which usually is a typo that would silently disable nothing
+func checkToolNames(cfg *serverconfig.Config) error {
+	knownToolsMu.Lock()
+	defer knownToolsMu.Unlock()
+
+	var unknown []string
+	for _, name := range cfg.ToolNames() {
+		if !knownTools[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("server config lists unknown tools: %s", strings.Join(unknown, ", "))
+}
+
+// ShowServerConfigParams defines the input parameters for show_server_config
+type ShowServerConfigParams struct{}
+
+// ShowServerConfigResult is the structured result of show_server_config
+type ShowServerConfigResult struct {
+	Sources      []string           `json:"sources"`
+	ModulePrefix string             `json:"module_prefix"`
+	Style        string             `json:"style"`
+	Ports        ServerConfigPorts  `json:"ports"`
+	Layout       ServerConfigLayout `json:"layout"`
+	GoctlPath    string             `json:"goctl_path,omitempty"`
+	SearchPaths  []string           `json:"goctl_search_paths"`
+	ProtocPath   string             `json:"protoc_path,omitempty"`
+	Tools        ServerConfigTools  `json:"tools"`
+	AllowedRoots []string           `json:"allowed_roots"`
+	Cache        ServerConfigCache  `json:"cache"`
+	AuditLog     string             `json:"audit_log,omitempty"`
+	Timeouts     map[string]string  `json:"timeouts"`
+	Warnings     []string           `json:"warnings,omitempty"`
+}
+
+// ServerConfigPorts holds the API and RPC port ranges
+type ServerConfigPorts struct {
+	API serverconfig.PortRange `json:"api"`
+	RPC serverconfig.PortRange `json:"rpc"`
+}
+
+// ServerConfigLayout holds the default output locations
+type ServerConfigLayout struct {
+	ModelDir  string `json:"model_dir"`
+	ConfigDir string `json:"config_dir"`
+}
+
+// ServerConfigTools lists the registered and disabled tools
+type ServerConfigTools struct {
+	Enabled  []string `json:"enabled"`
+	Disabled []string `json:"disabled"`
+}
+
+// ServerConfigCache holds the analysis cache settings
+type ServerConfigCache struct {
+	TTL        string `json:"ttl"`
+	MaxEntries int    `json:"max_entries"`
+}
+
+// ShowServerConfig reports the effective server config and where it came from
+func ShowServerConfig(ctx context.Context, req *mcp.CallToolRequest, params ShowServerConfigParams) (*mcp.CallToolResult, any, error) {
+	cfg := ServerConfig()
+
+	knownToolsMu.Lock()
+	enabled, disabled := []string{}, []string{}
+	for name := range knownTools {
+		if cfg.ToolEnabled(name) {
+			enabled = append(enabled, name)
+		} else {
+			disabled = append(disabled, name)
+		}
+	}
+	knownToolsMu.Unlock()
+	sort.Strings(enabled)
+	sort.Strings(disabled)
+
+	timeouts := make(map[string]string, len(process.DefaultTimeouts))
+	for _, step := range serverconfig.Steps() {
+		timeouts[step] = process.Timeout(process.Step(step)).String()
+	}
+
+	data := &ShowServerConfigResult{
+		Sources:      append([]string{}, cfg.Sources...),
+		ModulePrefix: cfg.ModulePrefix,
+		Style:        cfg.Style,
+		Ports:        ServerConfigPorts{API: cfg.Ports.API, RPC: cfg.Ports.RPC},
+		Layout:       ServerConfigLayout{ModelDir: cfg.Layout.ModelDir, ConfigDir: cfg.Layout.ConfigDir},
+		GoctlPath:    cfg.Goctl.Path,
+		SearchPaths:  append([]string{}, cfg.Goctl.SearchPaths...),
+		ProtocPath:   cfg.Protoc.Path,
+		Tools:        ServerConfigTools{Enabled: enabled, Disabled: disabled},
+		AllowedRoots: Sandbox.AllowList(),
+		Cache:        ServerConfigCache{TTL: cfg.Cache.TTL.String(), MaxEntries: cfg.Cache.MaxEntries},
+		Timeouts:     timeouts,
+		AuditLog:     Audit.Path(),
+		Warnings:     append([]string(nil), cfg.Warnings...),
+	}
+	if data.AllowedRoots == nil {
+		data.AllowedRoots = []string{}
+	}
+
+	var message strings.Builder
+	message.WriteString("=== Server Configuration ===\n")
+	if len(data.Sources) == 0 {
+		message.WriteString("Source: built-in defaults (no " + serverconfig.FileName + " found)\n")
+	} else {
+		for _, source := range data.Sources {
+			fmt.Fprintf(&message, "Source: %s\n", source)
+		}
+	}
+	fmt.Fprintf(&message, "Module prefix: %s\n", data.ModulePrefix)
+	fmt.Fprintf(&message, "Style: %s\n", data.Style)
+	fmt.Fprintf(&message, "API ports: %d (range %d-%d)\n", cfg.Ports.API.Default, cfg.Ports.API.Min, cfg.Ports.API.Max)
+	fmt.Fprintf(&message, "RPC ports: %d (range %d-%d)\n", cfg.Ports.RPC.Default, cfg.Ports.RPC.Min, cfg.Ports.RPC.Max)
+	fmt.Fprintf(&message, "Model dir: %s\n", data.Layout.ModelDir)
+	fmt.Fprintf(&message, "Config dir: %s\n", data.Layout.ConfigDir)
+
+	message.WriteString("\n=== Executables ===\n")
+	fmt.Fprintf(&message, "goctl: %s\n", valueOrDefault(data.GoctlPath, "discovered"))
+	if len(data.SearchPaths) > 0 {
+		fmt.Fprintf(&message, "goctl search paths: %s\n", strings.Join(data.SearchPaths, ", "))
+	}
+	fmt.Fprintf(&message, "protoc: %s\n", valueOrDefault(data.ProtocPath, "from PATH"))
+
+	message.WriteString("\n=== Tools ===\n")
+	fmt.Fprintf(&message, "Enabled: %s\n", strings.Join(enabled, ", "))
+	if len(disabled) > 0 {
+		fmt.Fprintf(&message, "Disabled: %s\n", strings.Join(disabled, ", "))
+	}
+
+	message.WriteString("\n=== Access & Cache ===\n")
+	fmt.Fprintf(&message, "Allowed roots: %s\n", valueOrDefault(strings.Join(data.AllowedRoots, ", "), "client roots only"))
+	fmt.Fprintf(&message, "Analysis cache: ttl %s, max %d entries\n", data.Cache.TTL, data.Cache.MaxEntries)
+	fmt.Fprintf(&message, "Audit log: %s\n", valueOrDefault(data.AuditLog, "disabled"))
+	for _, step := range serverconfig.Steps() {
+		fmt.Fprintf(&message, "Timeout %s: %s\n", step, timeouts[step])
+	}
+	for _, warning := range data.Warnings {
+		fmt.Fprintf(&message, "\n⚠️ %s\n", warning)
+	}
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
+
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/jinguoxing/mcp-gozero/internal/responses"
+)
+
+// startedAt is used to report server uptime
+var startedAt = time.Now()
+
+type ServerStatsParams struct {
+	Format string `json:"format,omitempty"` // text (default) or prometheus
+}
+
+// ServerStatsResult is the structured result of server_stats
+type ServerStatsResult struct {
+	UptimeSeconds int64       `json:"uptime_seconds"`
+	Tools         []ToolStats `json:"tools"`
+	Cache         CacheStats  `json:"cache"`
+}
+
+// ToolStats summarizes the calls of one tool; durations are in milliseconds
+type ToolStats struct {
+	Tool             string         `json:"tool"`
+	Calls            int            `json:"calls"`
+	Errors           int            `json:"errors"`
+	ErrorsByCategory map[string]int `json:"errors_by_category"`
+	SuccessRate      float64        `json:"success_rate"`
+	AvgMs            float64        `json:"avg_ms"`
+	MinMs            float64        `json:"min_ms"`
+	MaxMs            float64        `json:"max_ms"`
+	P50Ms            float64        `json:"p50_ms"`
+	P90Ms            float64        `json:"p90_ms"`
+	P99Ms            float64        `json:"p99_ms"`
+}
+
+// CacheStats describes the project analysis cache
+type CacheStats struct {
+	TotalEntries int `json:"total_entries"`
+	TotalHits    int `json:"total_hits"`
+	MaxCapacity  int `json:"max_capacity"`
+}
+
+// ServerStats reports per-tool call metrics and analysis cache statistics
+func ServerStats(ctx context.Context, req *mcp.CallToolRequest, params ServerStatsParams) (*mcp.CallToolResult, any, error) {
+	format := params.Format
+	if format == "" {
+		format = "text"
+	}
+
+	switch format {
+	case "text", "prometheus":
+	default:
+		return responses.FormatValidationError("format", params.Format, "unsupported format", "Use 'text' or 'prometheus'")
+	}
+
+	stats := Metrics.Snapshot()
+	cacheStats := GetCacheStats()
+	uptime := time.Since(startedAt).Round(time.Second)
+
+	data := &ServerStatsResult{
+		UptimeSeconds: int64(uptime.Seconds()),
+		Tools:         make([]ToolStats, 0, len(stats)),
+		Cache: CacheStats{
+			TotalEntries: cacheStats["total_entries"].(int),
+			TotalHits:    cacheStats["total_hits"].(int),
+			MaxCapacity:  cacheStats["max_capacity"].(int),
+		},
+	}
+	for _, s := range stats {
+		data.Tools = append(data.Tools, ToolStats{
+			Tool:             s.Tool,
+			Calls:            s.Calls,
+			Errors:           s.Errors,
+			ErrorsByCategory: s.ErrorsByCat,
+			SuccessRate:      s.SuccessRate,
+			AvgMs:            milliseconds(s.Avg),
+			MinMs:            milliseconds(s.Min),
+			MaxMs:            milliseconds(s.Max),
+			P50Ms:            milliseconds(s.P50),
+			P90Ms:            milliseconds(s.P90),
+			P99Ms:            milliseconds(s.P99),
+		})
+	}
+
+	if format == "prometheus" {
+		var buf bytes.Buffer
+		WritePrometheus(&buf)
+		return responses.FormatSuccessWithData(buf.String(), data)
+	}
+
+	var message strings.Builder
+	message.WriteString("Server Stats\n\n")
+	message.WriteString(fmt.Sprintf("Uptime: %s\n\n", uptime))
+
+	message.WriteString("=== Tools ===\n")
+	if len(stats) == 0 {
+		message.WriteString("  No tool calls recorded yet\n")
+	}
+	for _, s := range stats {
+		icon := "✅"
+		if s.Errors > 0 {
+			icon = "⚠️ "
+		}
+		message.WriteString(fmt.Sprintf("  %s %s: %d call(s), %d error(s), p50 %s, p90 %s, p99 %s, max %s\n",
+			icon, s.Tool, s.Calls, s.Errors, roundDuration(s.P50), roundDuration(s.P90), roundDuration(s.P99), roundDuration(s.Max)))
+		categories := make([]string, 0, len(s.ErrorsByCat))
+		for category := range s.ErrorsByCat {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			message.WriteString(fmt.Sprintf("     - %s: %d\n", category, s.ErrorsByCat[category]))
+		}
+	}
+	message.WriteString("\n")
+
+	message.WriteString("=== Analysis Cache ===\n")
+	message.WriteString(fmt.Sprintf("  Entries: %d / %d\n", data.Cache.TotalEntries, data.Cache.MaxCapacity))
+	message.WriteString(fmt.Sprintf("  Hits: %d\n", data.Cache.TotalHits))
+
+	return responses.FormatSuccessWithData(message.String(), data)
+}
+
+// WritePrometheus writes tool metrics and analysis cache statistics in the
+// Prometheus text exposition format
+func WritePrometheus(w io.Writer) {
+	Metrics.WritePrometheus(w)
+
+	cacheStats := GetCacheStats()
+	fmt.Fprintln(w, "# HELP mcp_gozero_analysis_cache_entries Number of cached project analyses.")
+	fmt.Fprintln(w, "# TYPE mcp_gozero_analysis_cache_entries gauge")
+	fmt.Fprintf(w, "mcp_gozero_analysis_cache_entries %v\n", cacheStats["total_entries"])
+	fmt.Fprintln(w, "# HELP mcp_gozero_analysis_cache_capacity Maximum number of cached project analyses.")
+	fmt.Fprintln(w, "# TYPE mcp_gozero_analysis_cache_capacity gauge")
+	fmt.Fprintf(w, "mcp_gozero_analysis_cache_capacity %v\n", cacheStats["max_capacity"])
+	fmt.Fprintln(w, "# HELP mcp_gozero_analysis_cache_hits Cache hits of the currently cached analyses.")
+	fmt.Fprintln(w, "# TYPE mcp_gozero_analysis_cache_hits gauge")
+	fmt.Fprintf(w, "mcp_gozero_analysis_cache_hits %v\n", cacheStats["total_hits"])
+
+	fmt.Fprintln(w, "# HELP mcp_gozero_uptime_seconds Seconds since the server started.")
+	fmt.Fprintln(w, "# TYPE mcp_gozero_uptime_seconds gauge")
+	fmt.Fprintf(w, "mcp_gozero_uptime_seconds %d\n", int64(time.Since(startedAt).Seconds()))
+}
+
+// MetricsHandler serves WritePrometheus output for Prometheus scrapers
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WritePrometheus(w)
+	})
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(100 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,191 @@
+// Package transport serves the MCP server over streamable HTTP so a single
+// mcp-gozero instance can be shared by several clients
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Transport names accepted by the --transport flag
+const (
+	Stdio = "stdio"
+	HTTP  = "http"
+)
+
+// Default HTTP settings
+const (
+	DefaultListenAddr      = "127.0.0.1:8080"
+	DefaultSessionTimeout  = 30 * time.Minute
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
+// Endpoint paths
+const (
+	MCPPath     = "/mcp"
+	HealthPath  = "/healthz"
+	MetricsPath = "/metrics"
+)
+
+// HTTPOptions configures the HTTP transport
+type HTTPOptions struct {
+	// Addr is the listen address, e.g. 127.0.0.1:8080
+	Addr string
+	// AuthToken enables bearer-token auth on the MCP endpoint when set
+	AuthToken string
+	// SessionTimeout closes sessions idle for longer than this; zero keeps them open
+	SessionTimeout time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may run after shutdown starts
+	ShutdownTimeout time.Duration
+	// Version is reported by the health endpoint
+	Version string
+}
+
+// Health is the body returned by the health endpoint
+type Health struct {
+	Status   string `json:"status"`
+	Version  string `json:"version,omitempty"`
+	Sessions int    `json:"sessions"`
+	Uptime   string `json:"uptime"`
+}
+
+// ValidateTransport checks a --transport flag value
+func ValidateTransport(name string) error {
+	switch name {
+	case Stdio, HTTP:
+		return nil
+	default:
+		return fmt.Errorf("unsupported transport %q (expected %s or %s)", name, Stdio, HTTP)
+	}
+}
+
+// NewHTTPHandler returns the HTTP handler serving the MCP endpoint and the
+// health endpoint. Every client gets its own MCP session on the shared
+// server; sessions are identified by the Mcp-Session-Id header.
+func NewHTTPHandler(server *mcp.Server, opts HTTPOptions) http.Handler {
+	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return server
+	}, &mcp.StreamableHTTPOptions{
+		SessionTimeout: opts.SessionTimeout,
+	})
+	if opts.AuthToken != "" {
+		handler = auth.RequireBearerToken(tokenVerifier(opts.AuthToken), nil)(handler)
+	}
+
+	started := time.Now()
+	mux := http.NewServeMux()
+	mux.Handle(MCPPath, handler)
+	mux.HandleFunc(HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		sessions := 0
+		for range server.Sessions() {
+			sessions++
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Health{
+			Status:   "ok",
+			Version:  opts.Version,
+			Sessions: sessions,
+			Uptime:   time.Since(started).Round(time.Second).String(),
+		})
+	})
+
+	return mux
+}
+
+// ServeHTTP listens on opts.Addr and serves the MCP server until ctx is
+// cancelled, then shuts down gracefully
+func ServeHTTP(ctx context.Context, server *mcp.Server, opts HTTPOptions) error {
+	if opts.Addr == "" {
+		opts.Addr = DefaultListenAddr
+	}
+
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Addr, err)
+	}
+
+	return Serve(ctx, listener, server, opts)
+}
+
+// Serve serves the MCP server on an existing listener until ctx is cancelled.
+// In-flight requests get opts.ShutdownTimeout to finish before connections are closed.
+func Serve(ctx context.Context, listener net.Listener, server *mcp.Server, opts HTTPOptions) error {
+	return serveHandler(ctx, listener, NewHTTPHandler(server, opts), opts.ShutdownTimeout)
+}
+
+// ServeMetrics serves a Prometheus metrics handler at MetricsPath on addr
+// until ctx is cancelled
+func ServeMetrics(ctx context.Context, addr string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, handler)
+	return serveHandler(ctx, listener, mux, 0)
+}
+
+// serveHandler runs an HTTP server until ctx is cancelled, then shuts it down gracefully
+func serveHandler(ctx context.Context, listener net.Listener, handler http.Handler, shutdownTimeout time.Duration) error {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	httpServer := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Streaming GET requests stay open until the client disconnects, so
+	// closing the remaining connections after the timeout is expected
+	err := httpServer.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = httpServer.Close()
+	}
+	if serveErr := <-errCh; serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return err
+}
+
+// tokenVerifier accepts a single static token, compared in constant time
+func tokenVerifier(token string) auth.TokenVerifier {
+	return func(ctx context.Context, presented string, req *http.Request) (*auth.TokenInfo, error) {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return nil, auth.ErrInvalidToken
+		}
+		// Static tokens do not expire, but the middleware requires an expiration
+		return &auth.TokenInfo{Expiration: time.Now().Add(time.Hour)}, nil
+	}
+}